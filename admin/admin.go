@@ -0,0 +1,31 @@
+// Package admin implements the subcommands that operate on the server's
+// database directly, with the server's configuration. They are shared by the
+// server binary and inventoryctl.
+package admin
+
+import (
+	"fmt"
+	"greenbone-task/services"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Program is the command the subcommands are run as, shown in their usage.
+var Program = filepath.Base(os.Args[0])
+
+// usage prints a usage message with the name of the program to stderr.
+func usage(stderr io.Writer, format string) {
+	fmt.Fprintf(stderr, format+"\n", Program)
+}
+
+// connectDB connects the services to the configured database and reports
+// whether it succeeded.
+func connectDB(stderr io.Writer, command string) bool {
+	services.ConnectDB()
+	if services.DbConnection == nil {
+		fmt.Fprintf(stderr, "%s: cannot connect to the database\n", command)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,53 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/pflag"
+	"greenbone-task/services"
+	"io"
+)
+
+const configUsage = `usage: %s config <command> [flags]
+
+commands:
+  validate    load the configuration like the server and list every problem`
+
+// Config implements the "config" subcommand and returns the process exit code.
+func Config(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] != "validate" {
+		usage(stderr, configUsage)
+		return 2
+	}
+
+	rest, err := services.LoadConfig(args[1:])
+	if err != nil {
+		return ConfigFailed(err, stderr)
+	}
+	if len(rest) > 0 {
+		usage(stderr, configUsage)
+		return 2
+	}
+	fmt.Fprintln(stdout, "configuration is valid")
+	return 0
+}
+
+// ConfigFailed reports to stderr why the configuration could not be loaded
+// and returns the exit code.
+func ConfigFailed(err error, stderr io.Writer) int {
+	var configErr *services.ConfigError
+	switch {
+	case errors.Is(err, pflag.ErrHelp):
+		return 0
+	case errors.As(err, &configErr):
+		fmt.Fprintln(stderr, "invalid configuration:")
+		for _, problem := range configErr.Problems {
+			fmt.Fprintln(stderr, "  "+problem)
+		}
+		return 1
+	default:
+		fmt.Fprintln(stderr, err)
+		fmt.Fprintln(stderr, "run with --help to list the flags")
+		return 2
+	}
+}
@@ -0,0 +1,94 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"greenbone-task/services"
+	"io"
+	"strconv"
+	"text/tabwriter"
+)
+
+const migrateUsage = `usage: %s migrate [flags] <command>
+
+commands:
+  up          apply all pending migrations
+  down [n]    revert the last n applied migrations (default 1)
+  status      list migrations and whether they are applied`
+
+// Migrate implements the "migrate" subcommand and returns the process exit code.
+func Migrate(args []string, stdout, stderr io.Writer) int {
+	args, err := services.LoadConfig(args)
+	if err != nil {
+		return ConfigFailed(err, stderr)
+	}
+	if len(args) == 0 {
+		usage(stderr, migrateUsage)
+		return 2
+	}
+
+	services.Config.DBAutoMigrate = false
+	if !connectDB(stderr, "migrate") {
+		return 1
+	}
+
+	migrator, err := services.NewMigrator()
+	if err != nil {
+		fmt.Fprintln(stderr, "migrate:", err)
+		return 1
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Fprintf(stdout, "applied %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			fmt.Fprintln(stderr, "migrate:", err)
+			return 1
+		}
+		if len(applied) == 0 {
+			fmt.Fprintln(stdout, "no pending migrations")
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				fmt.Fprintln(stderr, "migrate: down expects a positive number of steps")
+				return 2
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, migration := range reverted {
+			fmt.Fprintf(stdout, "reverted %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			fmt.Fprintln(stderr, "migrate:", err)
+			return 1
+		}
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			fmt.Fprintln(stderr, "migrate:", err)
+			return 1
+		}
+		w := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.Applied {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+		w.Flush()
+	default:
+		usage(stderr, migrateUsage)
+		return 2
+	}
+
+	return 0
+}
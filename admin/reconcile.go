@@ -0,0 +1,57 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/pflag"
+	"greenbone-task/services"
+	"io"
+	"text/tabwriter"
+)
+
+const reconcileUsage = `usage: %s reconcile [--dry-run] [flags]
+
+Makes the employee_abbrev of every computer agree with its assignment. A
+computer whose employee_abbrev names an unknown employee is only reported.`
+
+// Reconcile implements the "reconcile" subcommand and returns the process
+// exit code.
+func Reconcile(args []string, stdout, stderr io.Writer) int {
+	commandFlags := pflag.NewFlagSet("reconcile", pflag.ContinueOnError)
+	dryRun := commandFlags.Bool("dry-run", false, "only report what would be changed")
+
+	args, err := services.LoadConfig(args, commandFlags)
+	if err != nil {
+		return ConfigFailed(err, stderr)
+	}
+	if len(args) > 0 {
+		usage(stderr, reconcileUsage)
+		return 2
+	}
+
+	if !connectDB(stderr, "reconcile") {
+		return 1
+	}
+	defer services.CloseDB()
+
+	findings, err := services.ReconcileAssignments(context.Background(), *dryRun)
+	if err != nil {
+		fmt.Fprintln(stderr, "reconcile:", err)
+		return 1
+	}
+	if len(findings) == 0 {
+		fmt.Fprintln(stdout, "all computers are consistent")
+		return 0
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "COMPUTER\tACTION\tFROM\tTO")
+	for _, finding := range findings {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", finding.ComputerID, finding.Action, finding.From, finding.To)
+	}
+	w.Flush()
+	if *dryRun {
+		fmt.Fprintln(stdout, "dry run, nothing was changed")
+	}
+	return 0
+}
@@ -0,0 +1,125 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"greenbone-task/filewatch"
+	"greenbone-task/logger"
+	"os"
+	"sync/atomic"
+)
+
+// Client certificate modes.
+const (
+	ClientAuthNone     = "none"     // do not ask for client certificates
+	ClientAuthOptional = "optional" // verify client certificates that are sent
+	ClientAuthRequire  = "require"  // refuse connections without a valid client certificate
+)
+
+// Options locates the server certificate and, for mutual TLS, the CA that
+// signs client certificates.
+type Options struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+	ClientAuth   string
+}
+
+type state struct {
+	certificate *tls.Certificate
+	clientCAs   *x509.CertPool
+}
+
+// Reloader serves the certificates read from Options and rereads them when
+// the files change, so certificates can be renewed without a restart.
+type Reloader struct {
+	opts    Options
+	current atomic.Pointer[state]
+}
+
+// Load reads the certificates of opts.
+func Load(opts Options) (*Reloader, error) {
+	r := &Reloader{opts: opts}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload rereads the certificates. If they cannot be read, the previous ones
+// stay in use.
+func (r *Reloader) Reload() error {
+	certificate, err := tls.LoadX509KeyPair(r.opts.CertFile, r.opts.KeyFile)
+	if err != nil {
+		return fmt.Errorf("error loading server certificate: %w", err)
+	}
+
+	next := &state{certificate: &certificate}
+	if r.opts.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.opts.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("error reading client CA: %w", err)
+		}
+		next.clientCAs = x509.NewCertPool()
+		if !next.clientCAs.AppendCertsFromPEM(pem) {
+			return errors.New("error reading client CA: no certificates found")
+		}
+	}
+
+	r.current.Store(next)
+	return nil
+}
+
+// Certificate returns the server certificate in use.
+func (r *Reloader) Certificate() *tls.Certificate {
+	return r.current.Load().certificate
+}
+
+// TLSConfig returns a server configuration that picks up reloaded
+// certificates for new connections.
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			current := r.current.Load()
+			config := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*current.certificate},
+				NextProtos:   []string{"h2", "http/1.1"},
+			}
+			if current.clientCAs != nil {
+				config.ClientCAs = current.clientCAs
+				config.ClientAuth = clientAuthType(r.opts.ClientAuth)
+			}
+			return config, nil
+		},
+	}
+}
+
+func clientAuthType(mode string) tls.ClientAuthType {
+	switch mode {
+	case ClientAuthOptional:
+		return tls.VerifyClientCertIfGiven
+	case ClientAuthRequire:
+		return tls.RequireAndVerifyClientCert
+	default:
+		return tls.NoClientCert
+	}
+}
+
+// Watch reloads the certificates whenever their files change, until ctx is
+// cancelled.
+func (r *Reloader) Watch(ctx context.Context) error {
+	files := []string{r.opts.CertFile, r.opts.KeyFile, r.opts.ClientCAFile}
+	return filewatch.Watch(ctx, files, func() {
+		if err := r.Reload(); err != nil {
+			logger.Error("failed to reload certificates, keeping the previous ones", zap.Error(err))
+			return
+		}
+		logger.Info("reloaded certificates")
+	})
+}
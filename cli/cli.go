@@ -0,0 +1,211 @@
+// Package cli implements inventoryctl, the command-line client of the
+// inventory API. It logs in once, caches the tokens per server and refreshes
+// them when they expire. The admin subcommands run in-process against the
+// server's database instead of the API.
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/spf13/pflag"
+	"greenbone-task/admin"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+)
+
+// Environment variables providing defaults for the global flags.
+const (
+	ServerEnv     = "INVENTORY_SERVER"
+	TokenCacheEnv = "INVENTORY_TOKEN_CACHE"
+)
+
+// DefaultServer is the API server used without --server or INVENTORY_SERVER.
+const DefaultServer = "http://localhost:8000"
+
+// Output formats selected with --output.
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+)
+
+const usage = `usage: inventoryctl [flags] <command> [args]
+
+commands:
+  login --email <email>                 log in and cache the tokens
+  logout                                forget the cached tokens
+  computers list                        list all computers
+  computers get <id>                    show a computer
+  computers create [flags]              create a computer
+  computers assign <id> <employee>      assign a computer to an employee
+  computers delete <id>                 delete a computer
+  employees create [flags]              create an employee
+  employees computers <employee>        list the computers of an employee
+  employees delete-computer <employee> <id>
+                                        delete a computer of an employee
+  import <file>                         import computers from JSON, YAML or CSV
+  export [--file <file>]                export all computers as JSON, YAML or CSV
+  admin <command> [flags]               run migrate, config, create-user or
+                                        reconcile against the database
+
+flags:
+`
+
+// usageError is returned for invalid command lines, which exit with code 2.
+type usageError struct {
+	message string
+}
+
+func (e *usageError) Error() string {
+	return e.message
+}
+
+func usagef(format string, args ...any) error {
+	return &usageError{message: fmt.Sprintf(format, args...)}
+}
+
+// app holds the global flags and the output streams of one invocation.
+type app struct {
+	stdout     io.Writer
+	stderr     io.Writer
+	globals    *pflag.FlagSet
+	command    string
+	server     string
+	output     string
+	tokenCache string
+}
+
+// Run runs inventoryctl with args, the arguments after the program name, and
+// returns the process exit code.
+func Run(args []string, stdout, stderr io.Writer) int {
+	a := &app{stdout: stdout, stderr: stderr}
+	a.globals = pflag.NewFlagSet("inventoryctl", pflag.ContinueOnError)
+	a.globals.StringVar(&a.server, "server", envOr(ServerEnv, DefaultServer), "URL of the inventory API (env "+ServerEnv+")")
+	a.globals.StringVarP(&a.output, "output", "o", OutputTable, "output format: table, json or yaml")
+	a.globals.StringVar(&a.tokenCache, "token-cache", os.Getenv(TokenCacheEnv), "file the tokens are cached in (env "+TokenCacheEnv+")")
+
+	flags := pflag.NewFlagSet("inventoryctl", pflag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.SetInterspersed(false)
+	flags.AddFlagSet(a.globals)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if !errors.Is(err, pflag.ErrHelp) {
+			err = &usageError{message: err.Error()}
+		}
+		return a.exitCode(err)
+	}
+	args = flags.Args()
+	if len(args) == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	// the admin subcommands load the server configuration and parse their
+	// own flags
+	if args[0] == "admin" {
+		return runAdmin(args[1:], stdout, stderr)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var err error
+	switch args[0] {
+	case "login":
+		err = a.login(ctx, args[1:])
+	case "logout":
+		err = a.logout(args[1:])
+	case "computers":
+		err = a.computers(ctx, args[1:])
+	case "employees":
+		err = a.employees(ctx, args[1:])
+	case "import":
+		err = a.importComputers(ctx, args[1:])
+	case "export":
+		err = a.exportComputers(ctx, args[1:])
+	case "help":
+		flags.Usage()
+		return 0
+	default:
+		err = usagef("unknown command %q, run inventoryctl help to list the commands", args[0])
+	}
+	return a.exitCode(err)
+}
+
+// runAdmin runs one of the admin subcommands shared with the server binary.
+func runAdmin(args []string, stdout, stderr io.Writer) int {
+	admin.Program = "inventoryctl admin"
+	if len(args) > 0 {
+		switch args[0] {
+		case "migrate":
+			return admin.Migrate(args[1:], stdout, stderr)
+		case "config":
+			return admin.Config(args[1:], stdout, stderr)
+		case "create-user":
+			return admin.CreateUser(args[1:], stdout, stderr)
+		case "reconcile":
+			return admin.Reconcile(args[1:], stdout, stderr)
+		}
+	}
+	fmt.Fprintln(stderr, "usage: inventoryctl admin <migrate|config|create-user|reconcile> [flags]")
+	return 2
+}
+
+// exitCode reports err and returns the exit code for it.
+func (a *app) exitCode(err error) int {
+	var usageErr *usageError
+	switch {
+	case err == nil, errors.Is(err, pflag.ErrHelp):
+		return 0
+	case errors.As(err, &usageErr):
+		fmt.Fprintln(a.stderr, "inventoryctl:", err)
+		return 2
+	default:
+		fmt.Fprintln(a.stderr, "inventoryctl:", err)
+		return 1
+	}
+}
+
+// flagSet returns the flags of a command, which include the global ones so
+// that they can be given after the command too.
+func (a *app) flagSet(command string) *pflag.FlagSet {
+	a.command = command
+	flags := pflag.NewFlagSet("inventoryctl "+command, pflag.ContinueOnError)
+	flags.SetOutput(a.stderr)
+	flags.AddFlagSet(a.globals)
+	return flags
+}
+
+// parse parses the flags of a command and checks that it got n positional
+// arguments, named by argsUsage.
+func (a *app) parse(flags *pflag.FlagSet, args []string, n int, argsUsage string) ([]string, error) {
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, pflag.ErrHelp) {
+			return nil, err
+		}
+		return nil, &usageError{message: err.Error()}
+	}
+	if flags.NArg() != n {
+		return nil, usagef("%s", strings.TrimSpace("usage: inventoryctl "+a.command+" "+argsUsage))
+	}
+	switch a.output {
+	case OutputTable, OutputJSON, OutputYAML:
+	default:
+		return nil, usagef("unknown output format %q, expected table, json or yaml", a.output)
+	}
+	return flags.Args(), nil
+}
+
+func envOr(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
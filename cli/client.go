@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"greenbone-task/client"
+	"strings"
+	"time"
+)
+
+// refreshMargin is how long before it expires an access token is refreshed.
+const refreshMargin = 30 * time.Second
+
+// apiClient calls the API of one server with the generated client,
+// authenticating with the cached session.
+type apiClient struct {
+	server string
+	api    *client.Client
+	tokens *tokenCache
+}
+
+func (a *app) client() (*apiClient, error) {
+	tokens, err := newTokenCache(a.tokenCache)
+	if err != nil {
+		return nil, err
+	}
+	c := &apiClient{server: strings.TrimRight(a.server, "/"), tokens: tokens}
+	c.api = client.New(c.server, client.WithTokenSource(c.accessToken))
+	return c, nil
+}
+
+// login requests tokens for email and caches them.
+func (c *apiClient) login(ctx context.Context, email string) error {
+	tokens, err := c.api.GenerateAccessToken(ctx, client.AuthRequest{Email: email})
+	if err != nil {
+		return err
+	}
+	return c.tokens.Put(c.server, session{
+		Email:        email,
+		AccessToken:  tokens.Data.Token.Access.Token,
+		RefreshToken: tokens.Data.Token.Refresh.Token,
+	})
+}
+
+// accessToken returns the cached access token, refreshing it first if it is
+// about to expire.
+func (c *apiClient) accessToken(ctx context.Context) (string, error) {
+	s, ok, err := c.tokens.Get(c.server)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("not logged in to %s, run inventoryctl login --email <email>", c.server)
+	}
+	if !expiresSoon(s.AccessToken, refreshMargin) {
+		return s.AccessToken, nil
+	}
+	if expiresSoon(s.RefreshToken, 0) {
+		return "", fmt.Errorf("the session of %s has expired, run inventoryctl login --email %s", s.Email, s.Email)
+	}
+
+	tokens, err := c.api.RefreshToken(ctx, client.RefreshRequest{Token: s.RefreshToken, Email: s.Email})
+	if err != nil {
+		return "", fmt.Errorf("error refreshing the session, log in again: %w", err)
+	}
+	s.AccessToken, s.RefreshToken = tokens.Data.Token.Access.Token, tokens.Data.Token.Refresh.Token
+	if err := c.tokens.Put(c.server, s); err != nil {
+		return "", err
+	}
+	return s.AccessToken, nil
+}
+
+func (c *apiClient) listComputers(ctx context.Context) ([]Computer, error) {
+	list, err := c.api.ListComputers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return fromAPI(list.Data.Data), nil
+}
+
+func (c *apiClient) getComputer(ctx context.Context, id int64) (Computer, error) {
+	get, err := c.api.GetComputer(ctx, id)
+	if err != nil {
+		return Computer{}, err
+	}
+	return fromAPI([]client.Computer{get.Data.Data})[0], nil
+}
+
+// fromAPI returns the computers of the API as inventoryctl shows them.
+func fromAPI(computers []client.Computer) []Computer {
+	out := make([]Computer, len(computers))
+	for i, computer := range computers {
+		out[i] = Computer{
+			ID:             uint(computer.ID),
+			MacAddress:     computer.MacAddress,
+			ComputerName:   computer.ComputerName,
+			IPAddress:      computer.IPAddress,
+			EmployeeAbbrev: computer.EmployeeAbbrev,
+			Description:    computer.Description,
+		}
+	}
+	return out
+}
+
+// request returns the computer as a request to create it.
+func (computer Computer) request() client.ComputerRequest {
+	return client.ComputerRequest{
+		MacAddress:     computer.MacAddress,
+		ComputerName:   computer.ComputerName,
+		IPAddress:      computer.IPAddress,
+		EmployeeAbbrev: computer.EmployeeAbbrev,
+		Description:    computer.Description,
+	}
+}
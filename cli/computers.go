@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"greenbone-task/client"
+	"strconv"
+)
+
+const computersUsage = "usage: inventoryctl computers <list|get|create|assign|delete> [args]"
+
+func (a *app) computers(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return usagef(computersUsage)
+	}
+	switch args[0] {
+	case "list":
+		return a.listComputers(ctx, args[1:])
+	case "get":
+		return a.getComputer(ctx, args[1:])
+	case "create":
+		return a.createComputer(ctx, args[1:])
+	case "assign":
+		return a.assignComputer(ctx, args[1:])
+	case "delete":
+		return a.deleteComputer(ctx, args[1:])
+	default:
+		return usagef(computersUsage)
+	}
+}
+
+func (a *app) listComputers(ctx context.Context, args []string) error {
+	if _, err := a.parse(a.flagSet("computers list"), args, 0, ""); err != nil {
+		return err
+	}
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	computers, err := c.listComputers(ctx)
+	if err != nil {
+		return err
+	}
+	return a.renderComputers(computers)
+}
+
+func (a *app) getComputer(ctx context.Context, args []string) error {
+	args, err := a.parse(a.flagSet("computers get"), args, 1, "<id>")
+	if err != nil {
+		return err
+	}
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	computer, err := c.getComputer(ctx, id)
+	if err != nil {
+		return err
+	}
+	return a.renderComputer(computer)
+}
+
+func (a *app) createComputer(ctx context.Context, args []string) error {
+	var request client.ComputerRequest
+	flags := a.flagSet("computers create")
+	flags.StringVar(&request.MacAddress, "mac", "", "MAC address (required)")
+	flags.StringVar(&request.ComputerName, "name", "", "computer name (required)")
+	flags.StringVar(&request.IPAddress, "ip", "", "IP address (required)")
+	flags.StringVar(&request.EmployeeAbbrev, "employee", "", "abbreviation of the employee to assign it to")
+	flags.StringVar(&request.Description, "description", "", "description")
+	if _, err := a.parse(flags, args, 0, "--mac <mac> --name <name> --ip <ip> [--employee <abbreviation>] [--description <text>]"); err != nil {
+		return err
+	}
+
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	created, err := c.api.CreateComputer(ctx, request)
+	if err != nil {
+		return err
+	}
+	computer, err := c.getComputer(ctx, created.Data.ComputerID)
+	if err != nil {
+		return err
+	}
+	return a.renderComputer(computer)
+}
+
+func (a *app) assignComputer(ctx context.Context, args []string) error {
+	args, err := a.parse(a.flagSet("computers assign"), args, 2, "<id> <employee>")
+	if err != nil {
+		return err
+	}
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	if _, err := c.api.AssignComputer(ctx, id, args[1]); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stdout, "computer %d assigned to %s\n", id, args[1])
+	return nil
+}
+
+func (a *app) deleteComputer(ctx context.Context, args []string) error {
+	args, err := a.parse(a.flagSet("computers delete"), args, 1, "<id>")
+	if err != nil {
+		return err
+	}
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	if _, err := c.api.DeleteComputer(ctx, id); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stdout, "computer %d deleted\n", id)
+	return nil
+}
+
+func parseID(arg string) (int64, error) {
+	id, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, usagef("invalid computer ID %q", arg)
+	}
+	return id, nil
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"greenbone-task/client"
+)
+
+const employeesUsage = "usage: inventoryctl employees <create|computers|delete-computer> [args]"
+
+func (a *app) employees(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return usagef(employeesUsage)
+	}
+	switch args[0] {
+	case "create":
+		return a.createEmployee(ctx, args[1:])
+	case "computers":
+		return a.employeeComputers(ctx, args[1:])
+	case "delete-computer":
+		return a.deleteEmployeeComputer(ctx, args[1:])
+	default:
+		return usagef(employeesUsage)
+	}
+}
+
+func (a *app) createEmployee(ctx context.Context, args []string) error {
+	var request client.EmployeeRequest
+	flags := a.flagSet("employees create")
+	flags.StringVar(&request.FirstName, "first-name", "", "first name (required)")
+	flags.StringVar(&request.LastName, "last-name", "", "last name (required)")
+	flags.StringVar(&request.Email, "email", "", "email address (required)")
+	flags.StringVar(&request.Abbreviation, "abbreviation", "", "abbreviation (required)")
+	if _, err := a.parse(flags, args, 0, "--first-name <name> --last-name <name> --email <email> --abbreviation <abbreviation>"); err != nil {
+		return err
+	}
+
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	if _, err := c.api.CreateEmployee(ctx, request); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stdout, "employee %s created\n", request.Abbreviation)
+	return nil
+}
+
+func (a *app) employeeComputers(ctx context.Context, args []string) error {
+	args, err := a.parse(a.flagSet("employees computers"), args, 1, "<employee>")
+	if err != nil {
+		return err
+	}
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	list, err := c.api.ListEmployeeComputers(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return a.renderComputers(fromAPI(list.Data.Data))
+}
+
+func (a *app) deleteEmployeeComputer(ctx context.Context, args []string) error {
+	args, err := a.parse(a.flagSet("employees delete-computer"), args, 2, "<employee> <id>")
+	if err != nil {
+		return err
+	}
+	id, err := parseID(args[1])
+	if err != nil {
+		return err
+	}
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	if _, err := c.api.DeleteEmployeeComputer(ctx, id, args[0]); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stdout, "computer %d of %s deleted\n", id, args[0])
+	return nil
+}
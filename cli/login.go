@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+)
+
+func (a *app) login(ctx context.Context, args []string) error {
+	flags := a.flagSet("login")
+	email := flags.String("email", "", "email address to log in with (required)")
+	if _, err := a.parse(flags, args, 0, "--email <email>"); err != nil {
+		return err
+	}
+	if *email == "" {
+		return usagef("usage: inventoryctl login --email <email>")
+	}
+
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	if err := c.login(ctx, *email); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stdout, "logged in to %s as %s\n", c.server, *email)
+	return nil
+}
+
+func (a *app) logout(args []string) error {
+	if _, err := a.parse(a.flagSet("logout"), args, 0, ""); err != nil {
+		return err
+	}
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	if err := c.tokens.Delete(c.server); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stdout, "logged out of %s\n", c.server)
+	return nil
+}
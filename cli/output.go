@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"text/tabwriter"
+)
+
+// Computer is a computer as shown and exported by inventoryctl.
+type Computer struct {
+	ID             uint   `json:"id" yaml:"id"`
+	MacAddress     string `json:"mac_address" yaml:"mac_address"`
+	ComputerName   string `json:"computer_name" yaml:"computer_name"`
+	IPAddress      string `json:"ip_address" yaml:"ip_address"`
+	EmployeeAbbrev string `json:"employee_abbrev,omitempty" yaml:"employee_abbrev,omitempty"`
+	Description    string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// render writes v to stdout in the output format, using table to write the
+// rows of the table format.
+func (a *app) render(v any, table func(w io.Writer)) error {
+	return writeFormat(a.stdout, a.output, v, table)
+}
+
+func writeFormat(out io.Writer, format string, v any, table func(w io.Writer)) error {
+	switch format {
+	case OutputJSON:
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	case OutputYAML:
+		encoder := yaml.NewEncoder(out)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(v); err != nil {
+			return err
+		}
+		return encoder.Close()
+	default:
+		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		table(w)
+		return w.Flush()
+	}
+}
+
+// renderComputers writes a list of computers in the output format.
+func (a *app) renderComputers(computers []Computer) error {
+	if computers == nil {
+		computers = []Computer{}
+	}
+	return a.render(computers, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tNAME\tMAC ADDRESS\tIP ADDRESS\tEMPLOYEE\tDESCRIPTION")
+		for _, computer := range computers {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", computer.ID, computer.ComputerName, computer.MacAddress,
+				computer.IPAddress, computer.EmployeeAbbrev, computer.Description)
+		}
+	})
+}
+
+// renderComputer writes a single computer in the output format.
+func (a *app) renderComputer(computer Computer) error {
+	return a.render(computer, func(w io.Writer) {
+		fmt.Fprintf(w, "ID\t%d\n", computer.ID)
+		fmt.Fprintf(w, "NAME\t%s\n", computer.ComputerName)
+		fmt.Fprintf(w, "MAC ADDRESS\t%s\n", computer.MacAddress)
+		fmt.Fprintf(w, "IP ADDRESS\t%s\n", computer.IPAddress)
+		fmt.Fprintf(w, "EMPLOYEE\t%s\n", computer.EmployeeAbbrev)
+		fmt.Fprintf(w, "DESCRIPTION\t%s\n", computer.Description)
+	})
+}
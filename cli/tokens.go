@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// session is the login of one server in the token cache.
+type session struct {
+	Email        string `json:"email"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// tokenCache stores the sessions by server URL in a file only the user can
+// read.
+type tokenCache struct {
+	path string
+}
+
+// newTokenCache returns the cache in path, or in the user's configuration
+// directory if path is empty.
+func newTokenCache(path string) (*tokenCache, error) {
+	if path == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return nil, fmt.Errorf("cannot locate the token cache, set --token-cache: %w", err)
+		}
+		path = filepath.Join(dir, "inventoryctl", "tokens.json")
+	}
+	return &tokenCache{path: path}, nil
+}
+
+func (c *tokenCache) read() (map[string]session, error) {
+	sessions := map[string]session{}
+	data, err := os.ReadFile(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return sessions, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading the token cache: %w", err)
+	}
+	if err := json.Unmarshal(data, &sessions); err != nil {
+		return nil, fmt.Errorf("error reading the token cache %s: %w", c.path, err)
+	}
+	return sessions, nil
+}
+
+// Get returns the session of server, if there is one.
+func (c *tokenCache) Get(server string) (session, bool, error) {
+	sessions, err := c.read()
+	if err != nil {
+		return session{}, false, err
+	}
+	s, ok := sessions[server]
+	return s, ok, nil
+}
+
+// Put stores the session of server, replacing the cache file atomically.
+func (c *tokenCache) Put(server string, s session) error {
+	return c.update(func(sessions map[string]session) { sessions[server] = s })
+}
+
+// Delete removes the session of server.
+func (c *tokenCache) Delete(server string) error {
+	return c.update(func(sessions map[string]session) { delete(sessions, server) })
+}
+
+func (c *tokenCache) update(change func(sessions map[string]session)) error {
+	sessions, err := c.read()
+	if err != nil {
+		return err
+	}
+	change(sessions)
+
+	data, err := json.MarshalIndent(sessions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return fmt.Errorf("error writing the token cache: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), ".tokens-*")
+	if err != nil {
+		return fmt.Errorf("error writing the token cache: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing the token cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing the token cache: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		return fmt.Errorf("error writing the token cache: %w", err)
+	}
+	return nil
+}
+
+// expiresSoon reports whether token expires within margin. The token is not
+// verified, the server does that; a token without an expiry never expires.
+func expiresSoon(token string, margin time.Duration) bool {
+	var claims jwt.RegisteredClaims
+	if _, _, err := jwt.NewParser().ParseUnverified(token, &claims); err != nil {
+		return true
+	}
+	if claims.ExpiresAt == nil {
+		return false
+	}
+	return time.Until(claims.ExpiresAt.Time) < margin
+}
@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"greenbone-task/client"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// File formats of import and export, besides JSON and YAML.
+const formatCSV = "csv"
+
+// csvColumns are the columns of exported CSV files. Imported CSV files need
+// a header row naming the columns, in any order; id is ignored.
+var csvColumns = []string{"id", "mac_address", "computer_name", "ip_address", "employee_abbrev", "description"}
+
+func (a *app) importComputers(ctx context.Context, args []string) error {
+	flags := a.flagSet("import")
+	format := flags.String("format", "", "json, yaml or csv (default: from the file extension)")
+	args, err := a.parse(flags, args, 1, "[--format json|yaml|csv] <file>, or - for stdin")
+	if err != nil {
+		return err
+	}
+	if *format == "" {
+		*format = formatOf(args[0])
+	}
+
+	var content []byte
+	if args[0] == "-" {
+		content, err = io.ReadAll(os.Stdin)
+	} else {
+		content, err = os.ReadFile(args[0])
+	}
+	if err != nil {
+		return err
+	}
+	computers, err := decodeComputers(content, *format)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", args[0], err)
+	}
+	if len(computers) == 0 {
+		return fmt.Errorf("%s contains no computers", args[0])
+	}
+
+	// the IDs are assigned by the server
+	requests := make([]client.ComputerRequest, len(computers))
+	for i, computer := range computers {
+		requests[i] = computer.request()
+	}
+
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	imported, err := c.api.ImportComputers(ctx, requests)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stdout, "imported %d computers\n", len(imported.Data.ComputerIDs))
+	return nil
+}
+
+func (a *app) exportComputers(ctx context.Context, args []string) error {
+	flags := a.flagSet("export")
+	file := flags.String("file", "", "file to write to (default: stdout)")
+	format := flags.String("format", "", "json, yaml or csv (default: from the file extension, or json)")
+	if _, err := a.parse(flags, args, 0, "[--format json|yaml|csv] [--file <file>]"); err != nil {
+		return err
+	}
+	if *format == "" {
+		*format = formatOf(*file)
+	}
+	if *format != OutputJSON && *format != OutputYAML && *format != formatCSV {
+		return usagef("unknown format %q, expected json, yaml or csv", *format)
+	}
+
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	computers, err := c.listComputers(ctx)
+	if err != nil {
+		return err
+	}
+
+	var out bytes.Buffer
+	if err := encodeComputers(&out, computers, *format); err != nil {
+		return err
+	}
+	if *file == "" {
+		_, err := a.stdout.Write(out.Bytes())
+		return err
+	}
+	if err := os.WriteFile(*file, out.Bytes(), 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stdout, "exported %d computers to %s\n", len(computers), *file)
+	return nil
+}
+
+// formatOf returns the format of file by its extension, defaulting to JSON.
+func formatOf(file string) string {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".yaml", ".yml":
+		return OutputYAML
+	case ".csv":
+		return formatCSV
+	default:
+		return OutputJSON
+	}
+}
+
+func decodeComputers(content []byte, format string) ([]Computer, error) {
+	var computers []Computer
+	switch format {
+	case OutputJSON:
+		if err := json.Unmarshal(content, &computers); err != nil {
+			return nil, err
+		}
+	case OutputYAML:
+		if err := yaml.Unmarshal(content, &computers); err != nil {
+			return nil, err
+		}
+	case formatCSV:
+		records, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+		if err != nil {
+			return nil, err
+		}
+		if len(records) == 0 {
+			return nil, nil
+		}
+		columns := map[string]int{}
+		for i, name := range records[0] {
+			columns[strings.TrimSpace(name)] = i
+		}
+		for _, required := range []string{"mac_address", "computer_name", "ip_address"} {
+			if _, ok := columns[required]; !ok {
+				return nil, fmt.Errorf("the header row has no %s column", required)
+			}
+		}
+		value := func(record []string, column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return record[i]
+			}
+			return ""
+		}
+		for _, record := range records[1:] {
+			computers = append(computers, Computer{
+				MacAddress:     value(record, "mac_address"),
+				ComputerName:   value(record, "computer_name"),
+				IPAddress:      value(record, "ip_address"),
+				EmployeeAbbrev: value(record, "employee_abbrev"),
+				Description:    value(record, "description"),
+			})
+		}
+	default:
+		return nil, usagef("unknown format %q, expected json, yaml or csv", format)
+	}
+	return computers, nil
+}
+
+func encodeComputers(out io.Writer, computers []Computer, format string) error {
+	if computers == nil {
+		computers = []Computer{}
+	}
+	if format != formatCSV {
+		return writeFormat(out, format, computers, nil)
+	}
+
+	w := csv.NewWriter(out)
+	if err := w.Write(csvColumns); err != nil {
+		return err
+	}
+	for _, computer := range computers {
+		record := []string{strconv.FormatUint(uint64(computer.ID), 10), computer.MacAddress, computer.ComputerName,
+			computer.IPAddress, computer.EmployeeAbbrev, computer.Description}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,832 @@
+// Code generated by go generate ./openapi from openapi/openapi.yaml. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// AuthRequest is the email to issue tokens for.
+type AuthRequest struct {
+	Email string `json:"email"`
+}
+
+// Computer is a computer of the inventory.
+type Computer struct {
+	ID             int64      `json:"ID"`
+	CreatedAt      time.Time  `json:"CreatedAt"`
+	UpdatedAt      time.Time  `json:"UpdatedAt"`
+	DeletedAt      *time.Time `json:"DeletedAt,omitempty"`
+	MacAddress     string     `json:"mac_address"`
+	ComputerName   string     `json:"computer_name"`
+	IPAddress      string     `json:"ip_address"`
+	EmployeeAbbrev string     `json:"employee_abbrev,omitempty"`
+	Description    string     `json:"description,omitempty"`
+}
+
+// ComputerDTO is a computer as returned by /v2.
+type ComputerDTO struct {
+	ID           int64  `json:"id"`
+	MacAddress   string `json:"mac_address"`
+	ComputerName string `json:"computer_name"`
+	IPAddress    string `json:"ip_address"`
+	// Abbreviation of the employee the computer is assigned to
+	EmployeeAbbrev string    `json:"employee_abbrev,omitempty"`
+	Description    string    `json:"description,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// ComputerDetail is a computer with a message.
+type ComputerDetail struct {
+	Data    Computer `json:"Data"`
+	Message string   `json:"Message"`
+}
+
+// ComputerList is a list of computers with a message.
+type ComputerList struct {
+	Data    []Computer `json:"Data"`
+	Message string     `json:"Message"`
+}
+
+// ComputerListResponse is the envelope of a list of computers.
+type ComputerListResponse struct {
+	Success bool         `json:"success"`
+	Message string       `json:"message,omitempty"`
+	Data    ComputerList `json:"data"`
+}
+
+// ComputerPage is a page of computers.
+type ComputerPage struct {
+	Items    []ComputerDTO `json:"items"`
+	Page     int64         `json:"page"`
+	PageSize int64         `json:"page_size"`
+	// The number of computers of all pages
+	Total int64 `json:"total"`
+}
+
+// ComputerRequest is the details of a computer to create.
+type ComputerRequest struct {
+	MacAddress   string `json:"mac_address"`
+	ComputerName string `json:"computer_name"`
+	IPAddress    string `json:"ip_address"`
+	// Abbreviation of the employee to assign the computer to
+	EmployeeAbbrev string `json:"employee_abbrev,omitempty"`
+	Description    string `json:"description,omitempty"`
+}
+
+// ComputerResponse is the envelope of a computer.
+type ComputerResponse struct {
+	Success bool           `json:"success"`
+	Message string         `json:"message,omitempty"`
+	Data    ComputerDetail `json:"data"`
+}
+
+// Configuration is the configuration in effect.
+type Configuration struct {
+	// The value of every configuration key, with secrets redacted
+	Config map[string]any `json:"config"`
+	// The keys that are applied on reload
+	Reloadable []string `json:"reloadable"`
+}
+
+// ConfigurationResponse is the envelope of the configuration.
+type ConfigurationResponse struct {
+	Success bool          `json:"success"`
+	Message string        `json:"message,omitempty"`
+	Data    Configuration `json:"data"`
+}
+
+// CreatedComputer is the ID of a created computer.
+type CreatedComputer struct {
+	ComputerID int64  `json:"Computer ID"`
+	Message    string `json:"Message"`
+}
+
+// CreatedComputerResponse is the envelope of a created computer.
+type CreatedComputerResponse struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message,omitempty"`
+	Data    CreatedComputer `json:"data"`
+}
+
+// CreatedWebhook is a created webhook with its secret.
+type CreatedWebhook struct {
+	Webhook Webhook `json:"webhook"`
+	Secret  string  `json:"secret"`
+}
+
+// CreatedWebhookResponse is the envelope of a created webhook.
+type CreatedWebhookResponse struct {
+	Success bool           `json:"success"`
+	Message string         `json:"message,omitempty"`
+	Data    CreatedWebhook `json:"data"`
+}
+
+// DependencyStatus is the status of a dependency.
+type DependencyStatus struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	// Whether the service is not ready while the dependency is down
+	Required bool   `json:"required"`
+	Error    string `json:"error,omitempty"`
+}
+
+// EmployeeDTO is an employee as returned by /v2.
+type EmployeeDTO struct {
+	ID           int64     `json:"id"`
+	FirstName    string    `json:"first_name"`
+	LastName     string    `json:"last_name"`
+	Email        string    `json:"email"`
+	Abbreviation string    `json:"abbreviation"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// EmployeeRequest is the details of an employee to create.
+type EmployeeRequest struct {
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name"`
+	Email        string `json:"email"`
+	Abbreviation string `json:"abbreviation"`
+}
+
+// GraphQLError is an error of a GraphQL request or of one of its fields.
+type GraphQLError struct {
+	Message string `json:"message"`
+	// The stable code and the HTTP status of the problem, and the problems with
+	// the fields of the input
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+// GraphQLRequest is a GraphQL request.
+type GraphQLRequest struct {
+	// The GraphQL document
+	Query string `json:"query"`
+	// The operation to run if the document has several
+	OperationName string `json:"operationName,omitempty"`
+	// The values of the variables of the operation
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+// GraphQLResponse is the result of a GraphQL request.
+type GraphQLResponse struct {
+	// The requested fields, shaped like the query
+	Data   map[string]any `json:"data,omitempty"`
+	Errors []GraphQLError `json:"errors,omitempty"`
+}
+
+// ImportDTO is the IDs of imported computers.
+type ImportDTO struct {
+	ComputerIDs []int64 `json:"computer_ids"`
+}
+
+// ImportedComputers is the IDs of imported computers.
+type ImportedComputers struct {
+	ComputerIDs []int64 `json:"Computer IDs"`
+	Message     string  `json:"Message"`
+}
+
+// ImportedComputersResponse is the envelope of imported computers.
+type ImportedComputersResponse struct {
+	Success bool              `json:"success"`
+	Message string            `json:"message,omitempty"`
+	Data    ImportedComputers `json:"data"`
+}
+
+// Liveness is the status of the process.
+type Liveness struct {
+	Status string `json:"status"`
+}
+
+// LogLevel is the log level in effect.
+type LogLevel struct {
+	Level string `json:"level"`
+}
+
+// LogLevelRequest is the log level to change to.
+type LogLevelRequest struct {
+	Level string `json:"level"`
+}
+
+// LogLevelResponse is the envelope of the log level.
+type LogLevelResponse struct {
+	Success bool     `json:"success"`
+	Message string   `json:"message,omitempty"`
+	Data    LogLevel `json:"data"`
+}
+
+// Message is the outcome of a change.
+type Message struct {
+	Message string `json:"Message"`
+}
+
+// MessageResponse is the envelope of a message.
+type MessageResponse struct {
+	Success bool    `json:"success"`
+	Message string  `json:"message,omitempty"`
+	Data    Message `json:"data"`
+}
+
+// Problem is an RFC 7807 problem detail.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int64  `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Stable, machine readable error code
+	Code      string `json:"code"`
+	RequestID string `json:"request_id,omitempty"`
+	// The problems with the fields of the request
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// ReadinessReport is the status of the service and its dependencies.
+type ReadinessReport struct {
+	Status string                      `json:"status"`
+	Checks map[string]DependencyStatus `json:"checks"`
+}
+
+// RefreshRequest is a refresh token to exchange.
+type RefreshRequest struct {
+	// The refresh token
+	Token string `json:"token"`
+	Email string `json:"email,omitempty"`
+}
+
+// ReplayedDelivery is the delivery created by a replay.
+type ReplayedDelivery struct {
+	Delivery WebhookDelivery `json:"delivery"`
+}
+
+// Token is a JWT with its expiry.
+type Token struct {
+	Token string `json:"token"`
+	// Expiry in the server's time zone, as 2006-01-02 15:04:05
+	Expires string `json:"expires"`
+}
+
+// TokenPair is the tokens issued for an email.
+type TokenPair struct {
+	// The email of the refreshed tokens
+	Email string `json:"Email,omitempty"`
+	Token Tokens `json:"token"`
+}
+
+// TokenResponse is the envelope of issued tokens.
+type TokenResponse struct {
+	Success bool      `json:"success"`
+	Message string    `json:"message,omitempty"`
+	Data    TokenPair `json:"data"`
+}
+
+// Tokens is an access and a refresh token.
+type Tokens struct {
+	Access  Token `json:"access"`
+	Refresh Token `json:"refresh"`
+}
+
+// Webhook is a subscription of a URL to inventory events.
+type Webhook struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	URL       string    `json:"url"`
+	// The events sent to the webhook; empty means all
+	EventTypes []string `json:"event_types"`
+	Active     bool     `json:"active"`
+}
+
+// WebhookAttempt is one attempt to send a delivery.
+type WebhookAttempt struct {
+	ID          int64     `json:"id"`
+	DeliveryID  int64     `json:"delivery_id"`
+	AttemptedAt time.Time `json:"attempted_at"`
+	// The status the receiver answered, 0 if it did not
+	StatusCode int64  `json:"status_code"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// WebhookDelivery is one event to be sent to one webhook.
+type WebhookDelivery struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	WebhookID int64     `json:"webhook_id"`
+	EventID   string    `json:"event_id"`
+	EventType string    `json:"event_type"`
+	// The WebhookEvent sent as body
+	Payload        string     `json:"payload"`
+	Status         string     `json:"status"`
+	Attempts       int64      `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	LastStatusCode int64      `json:"last_status_code,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
+
+// WebhookDeliveryDetail is a delivery with the log of its attempts.
+type WebhookDeliveryDetail struct {
+	Delivery WebhookDelivery  `json:"delivery"`
+	Attempts []WebhookAttempt `json:"attempts"`
+}
+
+// WebhookDeliveryDetailResponse is the envelope of a delivery with its
+// attempts.
+type WebhookDeliveryDetailResponse struct {
+	Success bool                  `json:"success"`
+	Message string                `json:"message,omitempty"`
+	Data    WebhookDeliveryDetail `json:"data"`
+}
+
+// WebhookDeliveryList is the last deliveries of a webhook.
+type WebhookDeliveryList struct {
+	Deliveries []WebhookDelivery `json:"deliveries"`
+}
+
+// WebhookDeliveryListResponse is the envelope of deliveries.
+type WebhookDeliveryListResponse struct {
+	Success bool                `json:"success"`
+	Message string              `json:"message,omitempty"`
+	Data    WebhookDeliveryList `json:"data"`
+}
+
+// WebhookDeliveryResponse is the envelope of a replayed delivery.
+type WebhookDeliveryResponse struct {
+	Success bool             `json:"success"`
+	Message string           `json:"message,omitempty"`
+	Data    ReplayedDelivery `json:"data"`
+}
+
+// WebhookDetail is a webhook.
+type WebhookDetail struct {
+	Webhook Webhook `json:"webhook"`
+}
+
+// WebhookList is all webhooks.
+type WebhookList struct {
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+// WebhookListResponse is the envelope of the webhooks.
+type WebhookListResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message,omitempty"`
+	Data    WebhookList `json:"data"`
+}
+
+// WebhookRequest is a URL to subscribe to events.
+type WebhookRequest struct {
+	// The http or https URL the events are posted to
+	URL string `json:"url"`
+	// The events to send; empty sends all
+	EventTypes []string `json:"event_types,omitempty"`
+	// The secret signing the payloads, at least 16 characters. One is generated on
+	// create if it is empty, and it is kept on update.
+	Secret string `json:"secret,omitempty"`
+	// Whether events are sent; true on create if it is missing, kept on update
+	Active *bool `json:"active,omitempty"`
+}
+
+// WebhookResponse is the envelope of a webhook.
+type WebhookResponse struct {
+	Success bool          `json:"success"`
+	Message string        `json:"message,omitempty"`
+	Data    WebhookDetail `json:"data"`
+}
+
+// GraphQLQuery sends GET /graphql: run a GraphQL query.
+//
+// Mutations are refused with the code mutation_not_allowed; send them with
+// POST.
+//
+// Query parameters with the zero value are not sent.
+func (c *Client) GraphQLQuery(ctx context.Context, query string, operationName string, variables string) (*GraphQLResponse, error) {
+	values := url.Values{}
+	if query != "" {
+		values.Set("query", query)
+	}
+	if operationName != "" {
+		values.Set("operationName", operationName)
+	}
+	if variables != "" {
+		values.Set("variables", variables)
+	}
+	var response GraphQLResponse
+	if err := c.do(ctx, http.MethodGet, withQuery("/graphql", values), true, nil, &response, 200); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GraphQL sends POST /graphql: run a GraphQL query or mutation.
+func (c *Client) GraphQL(ctx context.Context, body GraphQLRequest) (*GraphQLResponse, error) {
+	var response GraphQLResponse
+	if err := c.do(ctx, http.MethodPost, "/graphql", true, body, &response, 200); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Healthz sends GET /healthz: liveness probe.
+//
+// Returns 200 as long as the process is running.
+func (c *Client) Healthz(ctx context.Context) (*Liveness, error) {
+	var response Liveness
+	if err := c.do(ctx, http.MethodGet, "/healthz", false, nil, &response, 200); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Metrics sends GET /metrics: scrape the Prometheus metrics.
+func (c *Client) Metrics(ctx context.Context) (string, error) {
+	return c.doText(ctx, http.MethodGet, "/metrics", false, "text/plain", 200)
+}
+
+// GetOpenAPISpec sends GET /openapi.yaml: get this document.
+func (c *Client) GetOpenAPISpec(ctx context.Context) (string, error) {
+	return c.doText(ctx, http.MethodGet, "/openapi.yaml", false, "application/yaml", 200)
+}
+
+// Readyz sends GET /readyz: readiness probe.
+//
+// Pings the database, Redis when enabled and optionally the notification
+// server, with the status and latency of each.
+func (c *Client) Readyz(ctx context.Context) (*ReadinessReport, error) {
+	var response ReadinessReport
+	if err := c.do(ctx, http.MethodGet, "/readyz", false, nil, &response, 200, 503); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetConfig sends GET /v1/admin/config: get the configuration.
+//
+// Returns the configuration in effect by key, including the changes of the
+// last reload, with secrets redacted, and the keys that are applied on reload.
+// Only for clients with a certificate of the admin role.
+func (c *Client) GetConfig(ctx context.Context) (*ConfigurationResponse, error) {
+	var response ConfigurationResponse
+	if err := c.do(ctx, http.MethodGet, "/v1/admin/config", true, nil, &response, 200); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetLogLevel sends GET /v1/admin/log-level: get the log level.
+//
+// Only for clients with a certificate of the admin role.
+func (c *Client) GetLogLevel(ctx context.Context) (*LogLevelResponse, error) {
+	var response LogLevelResponse
+	if err := c.do(ctx, http.MethodGet, "/v1/admin/log-level", true, nil, &response, 200); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// SetLogLevel sends PUT /v1/admin/log-level: change the log level.
+//
+// Changes the log level until the next restart or reload. Only for clients
+// with a certificate of the admin role.
+func (c *Client) SetLogLevel(ctx context.Context, body LogLevelRequest) (*LogLevelResponse, error) {
+	var response LogLevelResponse
+	if err := c.do(ctx, http.MethodPut, "/v1/admin/log-level", true, body, &response, 200); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// CreateEmployee sends POST /v1/api/employees/: create a new employee.
+func (c *Client) CreateEmployee(ctx context.Context, body EmployeeRequest) (*MessageResponse, error) {
+	var response MessageResponse
+	if err := c.do(ctx, http.MethodPost, "/v1/api/employees/", true, body, &response, 201); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// DeleteEmployeeComputer sends DELETE
+// /v1/api/employees/computers/{computer_id}/{employee_abbrev}: delete a
+// computer assigned to an employee.
+//
+// Deletes the computer if it is assigned to the employee.
+func (c *Client) DeleteEmployeeComputer(ctx context.Context, computerID int64, employeeAbbrev string) (*MessageResponse, error) {
+	var response MessageResponse
+	if err := c.do(ctx, http.MethodDelete, "/v1/api/employees/computers/"+strconv.FormatInt(computerID, 10)+"/"+url.PathEscape(employeeAbbrev), true, nil, &response, 200); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListEmployeeComputers sends GET
+// /v1/api/employees/computers/{employee_abbrev}: retrieve all computers
+// assigned to an employee.
+func (c *Client) ListEmployeeComputers(ctx context.Context, employeeAbbrev string) (*ComputerListResponse, error) {
+	var response ComputerListResponse
+	if err := c.do(ctx, http.MethodGet, "/v1/api/employees/computers/"+url.PathEscape(employeeAbbrev), true, nil, &response, 200); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GenerateAccessToken sends POST /v1/auth/generate_access_token: generate new
+// access tokens.
+//
+// Issues an access and a refresh token for the email.
+func (c *Client) GenerateAccessToken(ctx context.Context, body AuthRequest) (*TokenResponse, error) {
+	var response TokenResponse
+	if err := c.do(ctx, http.MethodPost, "/v1/auth/generate_access_token", false, body, &response, 200); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// RefreshToken sends POST /v1/auth/refresh: refresh the tokens.
+//
+// Exchanges a refresh token for a new access and refresh token. The refresh
+// token can only be used once.
+func (c *Client) RefreshToken(ctx context.Context, body RefreshRequest) (*TokenResponse, error) {
+	var response TokenResponse
+	if err := c.do(ctx, http.MethodPost, "/v1/auth/refresh", false, body, &response, 200); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListComputers sends GET /v1/computers: fetch all computers.
+func (c *Client) ListComputers(ctx context.Context) (*ComputerListResponse, error) {
+	var response ComputerListResponse
+	if err := c.do(ctx, http.MethodGet, "/v1/computers", true, nil, &response, 200); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// CreateComputer sends POST /v1/computers: create a new computer.
+//
+// Creates a computer and assigns it to its employee. The administrator is
+// notified if the employee reaches the quota.
+func (c *Client) CreateComputer(ctx context.Context, body ComputerRequest) (*CreatedComputerResponse, error) {
+	var response CreatedComputerResponse
+	if err := c.do(ctx, http.MethodPost, "/v1/computers", true, body, &response, 201); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ImportComputers sends POST /v1/computers/import: import computers.
+//
+// Creates and assigns a list of computers in a single transaction. Nothing is
+// imported if one of them fails.
+func (c *Client) ImportComputers(ctx context.Context, body []ComputerRequest) (*ImportedComputersResponse, error) {
+	var response ImportedComputersResponse
+	if err := c.do(ctx, http.MethodPost, "/v1/computers/import", true, body, &response, 201); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetComputer sends GET /v1/computers/{computer_id}: get a computer by ID.
+func (c *Client) GetComputer(ctx context.Context, computerID int64) (*ComputerResponse, error) {
+	var response ComputerResponse
+	if err := c.do(ctx, http.MethodGet, "/v1/computers/"+strconv.FormatInt(computerID, 10), true, nil, &response, 200); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// DeleteComputer sends DELETE /v1/computers/{computer_id}: delete a computer.
+func (c *Client) DeleteComputer(ctx context.Context, computerID int64) (*MessageResponse, error) {
+	var response MessageResponse
+	if err := c.do(ctx, http.MethodDelete, "/v1/computers/"+strconv.FormatInt(computerID, 10), true, nil, &response, 200); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// AssignComputer sends PUT /v1/computers/{computer_id}/{employee_abbrev}:
+// assign a computer to an employee.
+//
+// Assigns the computer to the employee, replacing its previous assignment.
+func (c *Client) AssignComputer(ctx context.Context, computerID int64, employeeAbbrev string) (*MessageResponse, error) {
+	var response MessageResponse
+	if err := c.do(ctx, http.MethodPut, "/v1/computers/"+strconv.FormatInt(computerID, 10)+"/"+url.PathEscape(employeeAbbrev), true, nil, &response, 201); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetWebhooks sends GET /v1/webhooks: list the webhooks.
+func (c *Client) GetWebhooks(ctx context.Context) (*WebhookListResponse, error) {
+	var response WebhookListResponse
+	if err := c.do(ctx, http.MethodGet, "/v1/webhooks", true, nil, &response, 200); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// CreateWebhook sends POST /v1/webhooks: subscribe a URL to events.
+//
+// The response is the only one holding the secret that signs the payloads.
+func (c *Client) CreateWebhook(ctx context.Context, body WebhookRequest) (*CreatedWebhookResponse, error) {
+	var response CreatedWebhookResponse
+	if err := c.do(ctx, http.MethodPost, "/v1/webhooks", true, body, &response, 201); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetWebhook sends GET /v1/webhooks/{webhook_id}: get a webhook by ID.
+func (c *Client) GetWebhook(ctx context.Context, webhookID int64) (*WebhookResponse, error) {
+	var response WebhookResponse
+	if err := c.do(ctx, http.MethodGet, "/v1/webhooks/"+strconv.FormatInt(webhookID, 10), true, nil, &response, 200); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// UpdateWebhook sends PUT /v1/webhooks/{webhook_id}: change a webhook.
+//
+// Replaces the URL, the events and, if given, the state and the secret of a
+// webhook.
+func (c *Client) UpdateWebhook(ctx context.Context, webhookID int64, body WebhookRequest) (*WebhookResponse, error) {
+	var response WebhookResponse
+	if err := c.do(ctx, http.MethodPut, "/v1/webhooks/"+strconv.FormatInt(webhookID, 10), true, body, &response, 200); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// DeleteWebhook sends DELETE /v1/webhooks/{webhook_id}: delete a webhook with
+// its deliveries.
+func (c *Client) DeleteWebhook(ctx context.Context, webhookID int64) (*MessageResponse, error) {
+	var response MessageResponse
+	if err := c.do(ctx, http.MethodDelete, "/v1/webhooks/"+strconv.FormatInt(webhookID, 10), true, nil, &response, 200); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetWebhookDeliveries sends GET /v1/webhooks/{webhook_id}/deliveries: list
+// the last deliveries of a webhook.
+//
+// Returns the last 100 deliveries, newest first.
+//
+// Query parameters with the zero value are not sent.
+func (c *Client) GetWebhookDeliveries(ctx context.Context, webhookID int64, status string) (*WebhookDeliveryListResponse, error) {
+	values := url.Values{}
+	if status != "" {
+		values.Set("status", status)
+	}
+	var response WebhookDeliveryListResponse
+	if err := c.do(ctx, http.MethodGet, withQuery("/v1/webhooks/"+strconv.FormatInt(webhookID, 10)+"/deliveries", values), true, nil, &response, 200); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetWebhookDelivery sends GET
+// /v1/webhooks/{webhook_id}/deliveries/{delivery_id}: get a delivery with its
+// attempts.
+func (c *Client) GetWebhookDelivery(ctx context.Context, webhookID int64, deliveryID int64) (*WebhookDeliveryDetailResponse, error) {
+	var response WebhookDeliveryDetailResponse
+	if err := c.do(ctx, http.MethodGet, "/v1/webhooks/"+strconv.FormatInt(webhookID, 10)+"/deliveries/"+strconv.FormatInt(deliveryID, 10), true, nil, &response, 200); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ReplayWebhookDelivery sends POST
+// /v1/webhooks/{webhook_id}/deliveries/{delivery_id}/replay: send the event of
+// a delivery again.
+//
+// Creates a new pending delivery of the same event, with the same event ID.
+func (c *Client) ReplayWebhookDelivery(ctx context.Context, webhookID int64, deliveryID int64) (*WebhookDeliveryResponse, error) {
+	var response WebhookDeliveryResponse
+	if err := c.do(ctx, http.MethodPost, "/v1/webhooks/"+strconv.FormatInt(webhookID, 10)+"/deliveries/"+strconv.FormatInt(deliveryID, 10)+"/replay", true, nil, &response, 202); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListComputersV2 sends GET /v2/computers: list computers.
+//
+// Query parameters with the zero value are not sent.
+func (c *Client) ListComputersV2(ctx context.Context, page int64, pageSize int64) (*ComputerPage, error) {
+	values := url.Values{}
+	if page != 0 {
+		values.Set("page", strconv.FormatInt(page, 10))
+	}
+	if pageSize != 0 {
+		values.Set("page_size", strconv.FormatInt(pageSize, 10))
+	}
+	var response ComputerPage
+	if err := c.do(ctx, http.MethodGet, withQuery("/v2/computers", values), true, nil, &response, 200); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// CreateComputerV2 sends POST /v2/computers: create a computer.
+//
+// Creates a computer and assigns it to its employee. The administrator is
+// notified if the employee reaches the quota.
+func (c *Client) CreateComputerV2(ctx context.Context, body ComputerRequest) (*ComputerDTO, error) {
+	var response ComputerDTO
+	if err := c.do(ctx, http.MethodPost, "/v2/computers", true, body, &response, 201); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ImportComputersV2 sends POST /v2/computers/import: import computers.
+//
+// Creates and assigns a list of computers in a single transaction. Nothing is
+// imported if one of them fails.
+func (c *Client) ImportComputersV2(ctx context.Context, body []ComputerRequest) (*ImportDTO, error) {
+	var response ImportDTO
+	if err := c.do(ctx, http.MethodPost, "/v2/computers/import", true, body, &response, 201); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetComputerV2 sends GET /v2/computers/{computer_id}: get a computer.
+func (c *Client) GetComputerV2(ctx context.Context, computerID int64) (*ComputerDTO, error) {
+	var response ComputerDTO
+	if err := c.do(ctx, http.MethodGet, "/v2/computers/"+strconv.FormatInt(computerID, 10), true, nil, &response, 200); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// DeleteComputerV2 sends DELETE /v2/computers/{computer_id}: delete a
+// computer.
+func (c *Client) DeleteComputerV2(ctx context.Context, computerID int64) error {
+	return c.do(ctx, http.MethodDelete, "/v2/computers/"+strconv.FormatInt(computerID, 10), true, nil, nil, 204)
+}
+
+// AssignComputerV2 sends PUT /v2/computers/{computer_id}/{employee_abbrev}:
+// assign a computer to an employee.
+//
+// Assigns the computer to the employee, replacing its previous assignment.
+func (c *Client) AssignComputerV2(ctx context.Context, computerID int64, employeeAbbrev string) (*ComputerDTO, error) {
+	var response ComputerDTO
+	if err := c.do(ctx, http.MethodPut, "/v2/computers/"+strconv.FormatInt(computerID, 10)+"/"+url.PathEscape(employeeAbbrev), true, nil, &response, 200); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// CreateEmployeeV2 sends POST /v2/employees: create an employee.
+func (c *Client) CreateEmployeeV2(ctx context.Context, body EmployeeRequest) (*EmployeeDTO, error) {
+	var response EmployeeDTO
+	if err := c.do(ctx, http.MethodPost, "/v2/employees", true, body, &response, 201); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// GetEmployeeV2 sends GET /v2/employees/{employee_abbrev}: get an employee.
+func (c *Client) GetEmployeeV2(ctx context.Context, employeeAbbrev string) (*EmployeeDTO, error) {
+	var response EmployeeDTO
+	if err := c.do(ctx, http.MethodGet, "/v2/employees/"+url.PathEscape(employeeAbbrev), true, nil, &response, 200); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ListEmployeeComputersV2 sends GET /v2/employees/{employee_abbrev}/computers:
+// list the computers of an employee.
+//
+// Query parameters with the zero value are not sent.
+func (c *Client) ListEmployeeComputersV2(ctx context.Context, employeeAbbrev string, page int64, pageSize int64) (*ComputerPage, error) {
+	values := url.Values{}
+	if page != 0 {
+		values.Set("page", strconv.FormatInt(page, 10))
+	}
+	if pageSize != 0 {
+		values.Set("page_size", strconv.FormatInt(pageSize, 10))
+	}
+	var response ComputerPage
+	if err := c.do(ctx, http.MethodGet, withQuery("/v2/employees/"+url.PathEscape(employeeAbbrev)+"/computers", values), true, nil, &response, 200); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// DeleteEmployeeComputerV2 sends DELETE
+// /v2/employees/{employee_abbrev}/computers/{computer_id}: delete a computer
+// of an employee.
+//
+// Deletes the computer if it is assigned to the employee.
+func (c *Client) DeleteEmployeeComputerV2(ctx context.Context, employeeAbbrev string, computerID int64) error {
+	return c.do(ctx, http.MethodDelete, "/v2/employees/"+url.PathEscape(employeeAbbrev)+"/computers/"+strconv.FormatInt(computerID, 10), true, nil, nil, 204)
+}
@@ -0,0 +1,166 @@
+// Package client is a typed client of the inventory API. The types and the
+// methods in client.gen.go are generated from openapi/openapi.yaml; run
+// go generate ./openapi after changing the document.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Client calls the API of one server.
+type Client struct {
+	server string
+	http   *http.Client
+	token  func(ctx context.Context) (string, error)
+}
+
+// Option configures a Client.
+type Option func(c *Client)
+
+// WithHTTPClient sends the requests with client, e.g. for mutual TLS.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) { c.http = client }
+}
+
+// WithToken authenticates the requests with an access token.
+func WithToken(token string) Option {
+	return WithTokenSource(func(context.Context) (string, error) { return token, nil })
+}
+
+// WithTokenSource authenticates the requests with the access token returned
+// by source before each request, e.g. to refresh it when it expires.
+func WithTokenSource(source func(ctx context.Context) (string, error)) Option {
+	return func(c *Client) { c.token = source }
+}
+
+// New returns a client of the API at server, e.g. http://localhost:8000.
+func New(server string, options ...Option) *Client {
+	c := &Client{
+		server: strings.TrimRight(server, "/"),
+		http:   &http.Client{Timeout: 30 * time.Second},
+	}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+func (p *Problem) Error() string {
+	message := p.Detail
+	if message == "" {
+		message = p.Title
+	}
+	fields := make([]string, 0, len(p.Errors))
+	for field := range p.Errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		message += fmt.Sprintf("; %s: %s", field, p.Errors[field])
+	}
+	if p.Code != "" {
+		message += " (" + p.Code + ")"
+	}
+	return message
+}
+
+// StatusError is returned for an unexpected response that is not a problem,
+// e.g. from a proxy in front of the API.
+type StatusError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Body       []byte
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s %s: server answered %d %s", e.Method, e.Path, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// do sends body as JSON and decodes the response into out, unless it is nil,
+// if its status is one of statuses. Other responses are returned as a *Problem
+// or a *StatusError.
+func (c *Client) do(ctx context.Context, method string, path string, authenticated bool, body any, out any, statuses ...int) error {
+	content, err := c.send(ctx, method, path, authenticated, "application/json", body, statuses)
+	if err != nil || out == nil {
+		return err
+	}
+	if err := json.Unmarshal(content, out); err != nil {
+		return fmt.Errorf("error decoding the response of %s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+// doText returns the response as text if its status is one of statuses.
+func (c *Client) doText(ctx context.Context, method string, path string, authenticated bool, accept string, statuses ...int) (string, error) {
+	content, err := c.send(ctx, method, path, authenticated, accept, nil, statuses)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// withQuery returns path with the query, if any.
+func withQuery(path string, query url.Values) string {
+	if len(query) == 0 {
+		return path
+	}
+	return path + "?" + query.Encode()
+}
+
+func (c *Client) send(ctx context.Context, method string, path string, authenticated bool, accept string, body any, statuses []int) ([]byte, error) {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.server+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if authenticated && c.token != nil {
+		token, err := c.token(ctx)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Bearer-Token", token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the response of %s %s: %w", method, path, err)
+	}
+
+	for _, status := range statuses {
+		if resp.StatusCode == status {
+			return content, nil
+		}
+	}
+	var problem Problem
+	if err := json.Unmarshal(content, &problem); err == nil && problem.Code != "" {
+		return nil, &problem
+	}
+	return nil, &StatusError{Method: method, Path: path, StatusCode: resp.StatusCode, Body: content}
+}
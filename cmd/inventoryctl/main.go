@@ -0,0 +1,11 @@
+// Command inventoryctl is the command-line client of the inventory API.
+package main
+
+import (
+	"greenbone-task/cli"
+	"os"
+)
+
+func main() {
+	os.Exit(cli.Run(os.Args[1:], os.Stdout, os.Stderr))
+}
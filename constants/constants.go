@@ -1,6 +1,32 @@
 package constants
 
 const (
-	Warning          = "warning"
-	NOTIFICATION_URL = "http://host.docker.internal:8080/api/notify"
+	Warning = "warning"
+
+	// DefaultNotificationURL receives the administrator notifications if
+	// NOTIFICATION_URL is not configured.
+	DefaultNotificationURL = "http://host.docker.internal:8080/api/notify"
+
+	// DefaultComputerQuota is the number of computers from which on the system
+	// administrator is notified about further assignments to an employee, if
+	// COMPUTER_QUOTA is not configured.
+	DefaultComputerQuota = 3
+)
+
+// Roles of clients authenticated with a certificate
+const (
+	RoleAdmin    = "admin"     // may use every endpoint
+	RoleReadOnly = "read-only" // may only read
+)
+
+// Supported storage drivers
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// Pagination of the /v2 lists
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
 )
@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"greenbone-task/logger"
+	"greenbone-task/models"
+	"greenbone-task/services"
+)
+
+// GetLogLevel returns the current log level
+func GetLogLevel(c *gin.Context) {
+	models.SendResponseData(c, gin.H{"level": logger.Level()})
+}
+
+// SetLogLevel changes the log level at runtime
+func SetLogLevel(c *gin.Context) {
+	var request models.LogLevelRequest
+	if err := c.ShouldBindBodyWith(&request, binding.JSON); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+	if err := request.Validate(); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+
+	if err := services.SetLogLevel(c.Request.Context(), request.Level); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+	models.SendResponseData(c, gin.H{"level": logger.Level()})
+}
+
+// GetConfig returns the configuration in effect
+func GetConfig(c *gin.Context) {
+	models.SendResponseData(c, gin.H{
+		"config":     services.RedactedConfig(),
+		"reloadable": services.ReloadableKeys(),
+	})
+}
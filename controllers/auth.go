@@ -6,89 +6,55 @@ import (
 	"greenbone-task/models"
 	db "greenbone-task/models/db"
 	"greenbone-task/services"
-	"net/http"
 )
 
 // GenerateAccessToken generates new access tokens.
-// @Summary Generate new access tokens.
-// @Description Generate new access tokens for the provided email.
-// @Tags Tokens
-// @Accept  json
-// @Produce  json
-// @Param authReq body models.AuthRequest true "Auth Request"
-// @Success 200 {object} models.Response
-// @Success 400 {object} models.Response
-// @Router /access [post]
 func GenerateAccessToken(c *gin.Context) {
 	var requestBody models.AuthRequest
 	_ = c.ShouldBindBodyWith(&requestBody, binding.JSON)
 
-	response := &models.Response{
-		StatusCode: http.StatusBadRequest,
-		Success:    false,
-	}
-
 	// generate new access tokens
-	accessToken, refreshToken, err := services.GenerateAccessTokens(requestBody.Email)
+	accessToken, refreshToken, err := services.GenerateAccessTokens(c.Request.Context(), requestBody.Email)
 	if err != nil {
-		response.Message = err.Error()
-		response.SendResponse(c)
+		_ = c.Error(err)
 		return
 	}
 
-	response.StatusCode = http.StatusOK
-	response.Success = true
-	response.Data = gin.H{
+	models.SendResponseData(c, gin.H{
 		"token": gin.H{
 			"access":  accessToken.GetResponseJson(),
 			"refresh": refreshToken.GetResponseJson()},
-	}
-	response.SendResponse(c)
+	})
 }
 
 // Refresh handles the request for token refresh.
-// @Summary Handle the request for token refresh.
-// @Description Handle the request for token refresh by validating the refresh token, generating new access and refresh tokens and returning the response.
-// @Tags Tokens
-// @Accept  json
-// @Produce  json
-// @Param requestBody body models.RefreshRequest true "Refresh Request"
-// @Success 200 {object} models.Response
-// @Success 400 {object} models.Response
-// @Router /refresh [post]
 func Refresh(c *gin.Context) {
 	var requestBody models.RefreshRequest
 	_ = c.ShouldBindBodyWith(&requestBody, binding.JSON)
 
-	response := &models.Response{
-		StatusCode: http.StatusBadRequest,
-		Success:    false,
-	}
-
 	// check token validity
-	token, err := services.VerifyToken(requestBody.Token, db.TokenTypeRefresh)
+	token, err := services.VerifyToken(c.Request.Context(), requestBody.Token, db.TokenTypeRefresh)
 	if err != nil {
-		response.Message = err.Error()
-		response.SendResponse(c)
+		_ = c.Error(err)
 		return
 	}
 
 	// delete old token
-	err = services.DeleteTokenById(token.ID)
+	if err := services.DeleteTokenById(c.Request.Context(), token.ID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	accessToken, refreshToken, err := services.GenerateAccessTokens(c.Request.Context(), requestBody.Email)
 	if err != nil {
-		response.Message = err.Error()
-		response.SendResponse(c)
+		_ = c.Error(err)
 		return
 	}
 
-	accessToken, refreshToken, err := services.GenerateAccessTokens(requestBody.Email)
-	response.StatusCode = http.StatusOK
-	response.Success = true
-	response.Data = gin.H{
+	models.SendResponseData(c, gin.H{
 		"Email": requestBody.Email,
 		"token": gin.H{
 			"access":  accessToken.GetResponseJson(),
 			"refresh": refreshToken.GetResponseJson()},
-	}
-	response.SendResponse(c)
+	})
 }
@@ -0,0 +1,150 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"greenbone-task/models"
+	db "greenbone-task/models/db"
+	"greenbone-task/services"
+	"net/http"
+)
+
+// CreateComputerV2 creates a computer and returns it
+func CreateComputerV2(c *gin.Context) {
+	var request models.ComputerRequest
+	if err := c.ShouldBindBodyWith(&request, binding.JSON); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+	computer := newComputer(request)
+	if err := models.ValidateComputerRequest(computer); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+
+	computerID, err := services.CreateComputer(c.Request.Context(), computer)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	created, err := services.GetComputerByID(c.Request.Context(), int64(computerID))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.NewComputerDTO(*created))
+}
+
+// ImportComputersV2 creates several computers at once and returns their IDs
+func ImportComputersV2(c *gin.Context) {
+	var requests []models.ComputerRequest
+	if err := c.ShouldBindBodyWith(&requests, binding.JSON); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+	if len(requests) == 0 {
+		_ = c.Error(services.ValidationError(services.CodeValidationFailed, nil, "at least one computer is required"))
+		return
+	}
+
+	computers := make([]db.Computer, len(requests))
+	for i, request := range requests {
+		computers[i] = newComputer(request)
+		if err := models.ValidateComputerRequest(computers[i]); err != nil {
+			_ = c.Error(invalidRequest(err))
+			return
+		}
+	}
+
+	computerIDs, err := services.ImportComputers(c.Request.Context(), computers)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.ImportDTO{ComputerIDs: computerIDs})
+}
+
+// ListComputersV2 returns a page of all computers
+func ListComputersV2(c *gin.Context) {
+	page, pageSize, err := pageParams(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	computers, total, err := services.ListComputers(c.Request.Context(), (page-1)*pageSize, pageSize)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.PageDTO[models.ComputerDTO]{
+		Items:    models.NewComputerDTOs(computers),
+		Page:     page,
+		PageSize: pageSize,
+		Total:    total,
+	})
+}
+
+// GetComputerV2 returns a computer by its ID
+func GetComputerV2(c *gin.Context) {
+	computerID, err := computerIDParam(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	computer, err := services.GetComputerByID(c.Request.Context(), computerID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewComputerDTO(*computer))
+}
+
+// AssignComputerV2 assigns a computer to an employee and returns it
+func AssignComputerV2(c *gin.Context) {
+	computerID, err := computerIDParam(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	computer, err := services.AssignComputerToEmployee(c.Request.Context(), computerID, c.Param("employee_abbrev"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewComputerDTO(*computer))
+}
+
+// DeleteComputerV2 deletes a computer
+func DeleteComputerV2(c *gin.Context) {
+	computerID, err := computerIDParam(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err := services.DeleteComputer(c.Request.Context(), computerID); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// newComputer returns the computer to create for a request.
+func newComputer(request models.ComputerRequest) db.Computer {
+	return db.Computer{
+		MacAddress:     request.MacAddress,
+		ComputerName:   request.ComputerName,
+		IPAddress:      request.IPAddress,
+		EmployeeAbbrev: request.EmployeeAbbrev,
+		Description:    request.Description,
+	}
+}
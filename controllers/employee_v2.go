@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"greenbone-task/models"
+	"greenbone-task/services"
+	"net/http"
+)
+
+// CreateEmployeeV2 creates an employee and returns it
+func CreateEmployeeV2(c *gin.Context) {
+	var request models.EmployeeRequest
+	if err := c.ShouldBindBodyWith(&request, binding.JSON); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+	if err := models.ValidateEmployeeRequest(request); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+
+	if err := services.CreateEmployee(c.Request.Context(), request); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	employee, err := services.FindByEmployeeAbbrev(c.Request.Context(), request.Abbreviation)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.NewEmployeeDTO(employee))
+}
+
+// GetEmployeeV2 returns an employee by its abbreviation
+func GetEmployeeV2(c *gin.Context) {
+	employee, err := services.FindByEmployeeAbbrev(c.Request.Context(), c.Param("employee_abbrev"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewEmployeeDTO(employee))
+}
+
+// ListEmployeeComputersV2 returns a page of the computers assigned to an
+// employee
+func ListEmployeeComputersV2(c *gin.Context) {
+	page, pageSize, err := pageParams(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	computers, err := services.FindComputersByEmployeeAbbrev(c.Request.Context(), c.Param("employee_abbrev"))
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	// an employee only has a few computers, so they are paged in memory
+	total := len(computers)
+	start := (page - 1) * pageSize
+	if start > total {
+		start = total
+	}
+	end := start + pageSize
+	if end > total {
+		end = total
+	}
+
+	c.JSON(http.StatusOK, models.PageDTO[models.ComputerDTO]{
+		Items:    models.NewComputerDTOs(computers[start:end]),
+		Page:     page,
+		PageSize: pageSize,
+		Total:    int64(total),
+	})
+}
+
+// DeleteEmployeeComputerV2 deletes a computer if it is assigned to the
+// employee
+func DeleteEmployeeComputerV2(c *gin.Context) {
+	computerID, err := computerIDParam(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err := services.DeleteEmployeeComputer(c.Request.Context(), computerID, c.Param("employee_abbrev")); err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"greenbone-task/constants"
+	"greenbone-task/graph"
+	"greenbone-task/middlewares"
+	"greenbone-task/services"
+	"net/http"
+)
+
+// GraphQL runs a GraphQL request. A GET passes it in the query string and may
+// only run queries; a POST passes it as JSON and may also run mutations.
+func GraphQL(c *gin.Context) {
+	var request graph.Request
+	if c.Request.Method == http.MethodGet {
+		request.Query = c.Query("query")
+		request.OperationName = c.Query("operationName")
+		if variables := c.Query("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &request.Variables); err != nil {
+				_ = c.Error(services.ValidationError(services.CodeValidationFailed,
+					validation.Errors{"variables": errors.New("must be a JSON object")}, "invalid GraphQL request"))
+				return
+			}
+		}
+	} else if err := c.ShouldBindBodyWith(&request, binding.JSON); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return
+	}
+	if request.Query == "" {
+		_ = c.Error(services.ValidationError(services.CodeValidationFailed,
+			validation.Errors{"query": errors.New("cannot be blank")}, "invalid GraphQL request"))
+		return
+	}
+
+	c.JSON(http.StatusOK, graph.Execute(c.Request.Context(), request, refuseMutations(c)))
+}
+
+// refuseMutations returns why the request may not run mutations, like the
+// REST routes refuse changes: they must be sent with POST, and read-only
+// clients may not make them.
+func refuseMutations(c *gin.Context) error {
+	if c.Request.Method != http.MethodPost {
+		return services.ForbiddenError(services.CodeMutationNotAllowed, "mutations must be sent with POST")
+	}
+	if c.GetString(middlewares.ClientRoleKey) == constants.RoleReadOnly {
+		return services.ForbiddenError(services.CodeInsufficientRole, "client %s may only read", c.GetString(middlewares.ClientIdentityKey))
+	}
+	return nil
+}
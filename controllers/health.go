@@ -0,0 +1,27 @@
+package controllers
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"greenbone-task/services"
+	"net/http"
+	"time"
+)
+
+// Healthz reports that the process is alive
+func Healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz reports whether the service and its dependencies can serve requests
+func Readyz(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+	defer cancel()
+
+	report := services.CheckReadiness(ctx)
+	status := http.StatusOK
+	if report.Status != services.StatusReady {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
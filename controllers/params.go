@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"greenbone-task/constants"
+	"greenbone-task/services"
+	"strconv"
+)
+
+// computerIDParam parses the computer ID path parameter.
+func computerIDParam(c *gin.Context) (int64, error) {
+	value := c.Param("computer_id")
+	if value == "" {
+		// older clients pass the ID as a query parameter
+		value = c.Query("computer_id")
+	}
+
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, services.ValidationError(services.CodeValidationFailed,
+			validation.Errors{"computer_id": errors.New("must be a positive integer")},
+			"invalid computer ID %q", value)
+	}
+	return id, nil
+}
+
+// idParam parses a positive ID path parameter.
+func idParam(c *gin.Context, name string) (uint, error) {
+	value := c.Param(name)
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || id == 0 {
+		return 0, services.ValidationError(services.CodeValidationFailed,
+			validation.Errors{name: errors.New("must be a positive integer")},
+			"invalid %s %q", name, value)
+	}
+	return uint(id), nil
+}
+
+// invalidRequest reports a body that cannot be bound or fails validation.
+func invalidRequest(err error) error {
+	return services.ValidationError(services.CodeValidationFailed, err, "invalid request body")
+}
+
+// pageParams parses the page and page_size query parameters of a /v2 list.
+func pageParams(c *gin.Context) (int, int, error) {
+	page, pageSize := 1, constants.DefaultPageSize
+	problems := validation.Errors{}
+	if value := c.Query("page"); value != "" {
+		var err error
+		if page, err = strconv.Atoi(value); err != nil || page < 1 {
+			problems["page"] = errors.New("must be a positive integer")
+		}
+	}
+	if value := c.Query("page_size"); value != "" {
+		var err error
+		if pageSize, err = strconv.Atoi(value); err != nil || pageSize < 1 || pageSize > constants.MaxPageSize {
+			problems["page_size"] = fmt.Errorf("must be an integer from 1 to %d", constants.MaxPageSize)
+		}
+	}
+	if len(problems) > 0 {
+		return 0, 0, services.ValidationError(services.CodeValidationFailed, problems, "invalid pagination")
+	}
+	return page, pageSize, nil
+}
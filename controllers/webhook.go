@@ -0,0 +1,183 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"greenbone-task/models"
+	db "greenbone-task/models/db"
+	"greenbone-task/services"
+	"net/http"
+)
+
+// CreateWebhook handles the request to subscribe a URL to events. The
+// response is the only one holding the secret.
+func CreateWebhook(c *gin.Context) {
+	request, ok := bindWebhookRequest(c)
+	if !ok {
+		return
+	}
+
+	webhook, err := services.CreateWebhook(c.Request.Context(), request)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response := &models.Response{
+		StatusCode: http.StatusCreated,
+		Success:    true,
+		Data: gin.H{
+			"webhook": webhook,
+			"secret":  webhook.Secret,
+		},
+	}
+	response.SendResponse(c)
+}
+
+// GetWebhooks returns all webhooks
+func GetWebhooks(c *gin.Context) {
+	webhooks, err := services.GetWebhooks(c.Request.Context())
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	models.SendResponseData(c, gin.H{"webhooks": webhooks})
+}
+
+// GetWebhook returns a webhook by ID
+func GetWebhook(c *gin.Context) {
+	id, err := idParam(c, "webhook_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	webhook, err := services.GetWebhook(c.Request.Context(), id)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	models.SendResponseData(c, gin.H{"webhook": webhook})
+}
+
+// UpdateWebhook replaces the URL, the events and the state of a webhook
+func UpdateWebhook(c *gin.Context) {
+	id, err := idParam(c, "webhook_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	request, ok := bindWebhookRequest(c)
+	if !ok {
+		return
+	}
+
+	webhook, err := services.UpdateWebhook(c.Request.Context(), id, request)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	models.SendResponseData(c, gin.H{"webhook": webhook})
+}
+
+// DeleteWebhook deletes a webhook with its deliveries
+func DeleteWebhook(c *gin.Context) {
+	id, err := idParam(c, "webhook_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	if err := services.DeleteWebhook(c.Request.Context(), id); err != nil {
+		_ = c.Error(err)
+		return
+	}
+	models.SendResponseData(c, gin.H{"Message": "Webhook deleted successfully"})
+}
+
+// GetWebhookDeliveries returns the last deliveries of a webhook, optionally
+// only those with the status query parameter
+func GetWebhookDeliveries(c *gin.Context) {
+	id, err := idParam(c, "webhook_id")
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	status := c.Query("status")
+	if err := validation.Validate(status, validation.In(db.DeliveryPending, db.DeliverySucceeded, db.DeliveryDead)); err != nil {
+		_ = c.Error(services.ValidationError(services.CodeValidationFailed,
+			validation.Errors{"status": err}, "invalid delivery status %q", status))
+		return
+	}
+
+	deliveries, err := services.GetWebhookDeliveries(c.Request.Context(), id, status)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	models.SendResponseData(c, gin.H{"deliveries": deliveries})
+}
+
+// GetWebhookDelivery returns a delivery with the log of its attempts
+func GetWebhookDelivery(c *gin.Context) {
+	webhookID, deliveryID, err := deliveryParams(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	delivery, attempts, err := services.GetWebhookDelivery(c.Request.Context(), webhookID, deliveryID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+	models.SendResponseData(c, gin.H{"delivery": delivery, "attempts": attempts})
+}
+
+// ReplayWebhookDelivery sends the event of a delivery again
+func ReplayWebhookDelivery(c *gin.Context) {
+	webhookID, deliveryID, err := deliveryParams(c)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	delivery, err := services.ReplayWebhookDelivery(c.Request.Context(), webhookID, deliveryID)
+	if err != nil {
+		_ = c.Error(err)
+		return
+	}
+
+	response := &models.Response{
+		StatusCode: http.StatusAccepted,
+		Success:    true,
+		Data:       gin.H{"delivery": delivery},
+	}
+	response.SendResponse(c)
+}
+
+func bindWebhookRequest(c *gin.Context) (models.WebhookRequest, bool) {
+	var request models.WebhookRequest
+	if err := c.ShouldBindBodyWith(&request, binding.JSON); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return request, false
+	}
+	if err := request.Validate(); err != nil {
+		_ = c.Error(invalidRequest(err))
+		return request, false
+	}
+	return request, true
+}
+
+func deliveryParams(c *gin.Context) (uint, uint, error) {
+	webhookID, err := idParam(c, "webhook_id")
+	if err != nil {
+		return 0, 0, err
+	}
+	deliveryID, err := idParam(c, "delivery_id")
+	if err != nil {
+		return 0, 0, err
+	}
+	return webhookID, deliveryID, nil
+}
@@ -0,0 +1,65 @@
+// Package filewatch calls a function when files change.
+package filewatch
+
+import (
+	"context"
+	"fmt"
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+	"greenbone-task/logger"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Delay collects the several events of a single update, e.g. a key and a
+// certificate being replaced one after another, into one call.
+const Delay = 200 * time.Millisecond
+
+// Watch calls onChange after any of files has been written, created,
+// replaced or removed, until ctx is cancelled. It watches the directories of
+// the files rather than the files themselves to also catch files that are
+// replaced, as editors do, or swapped through a "..data" symlink, as
+// Kubernetes does with mounted secrets and config maps. Other files in these
+// directories are ignored.
+func Watch(ctx context.Context, files []string, onChange func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	watched := map[string]bool{}
+	dirs := map[string]bool{}
+	for _, file := range files {
+		if file == "" {
+			continue
+		}
+		watched[filepath.Clean(file)] = true
+		dirs[filepath.Dir(file)] = true
+	}
+	for dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			return fmt.Errorf("error watching %s: %w", dir, err)
+		}
+	}
+
+	timer := time.NewTimer(Delay)
+	timer.Stop()
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event := <-watcher.Events:
+			name := filepath.Clean(event.Name)
+			if watched[name] || strings.HasPrefix(filepath.Base(name), "..") {
+				timer.Reset(Delay)
+			}
+		case err := <-watcher.Errors:
+			logger.Error("error watching files", zap.Strings("files", files), zap.Error(err))
+		case <-timer.C:
+			onChange()
+		}
+	}
+}
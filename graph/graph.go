@@ -0,0 +1,126 @@
+// Package graph serves the inventory as GraphQL over the service layer.
+// Related objects are loaded in batches per request, so a query for many
+// employees with their computers costs one lookup per level instead of one
+// per employee.
+package graph
+
+import (
+	"context"
+	_ "embed"
+	"github.com/graph-gophers/graphql-go"
+	graphqlotel "github.com/graph-gophers/graphql-go/trace/otel"
+	"greenbone-task/middlewares"
+	"greenbone-task/services"
+	"sync/atomic"
+)
+
+//go:embed schema.graphql
+var schemaSource string
+
+const (
+	// maxDepth limits the nesting of queries, e.g. computer.employee.computers.
+	maxDepth = 10
+
+	// maxParallelism is how many fields are resolved at the same time. Fields
+	// waiting on the same loader are only batched together if they run in
+	// parallel.
+	maxParallelism = 100
+
+	// defaultMaxObjects limits how many computers, employees and assignments
+	// a request resolves unless GRAPHQL_MAX_OBJECTS is set, so that nesting
+	// lists cannot walk the whole inventory once per level.
+	defaultMaxObjects = 10000
+)
+
+var schema = graphql.MustParseSchema(schemaSource, &resolver{},
+	graphql.MaxDepth(maxDepth),
+	graphql.MaxParallelism(maxParallelism),
+	graphql.Tracer(graphqlotel.DefaultTracer()),
+)
+
+// Request is a GraphQL request.
+type Request struct {
+	Query         string         `json:"query" form:"query"`
+	OperationName string         `json:"operationName" form:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Execute runs a GraphQL request. Unless refuseMutations is nil, every
+// mutation fails with it, e.g. for requests that are not sent with POST or
+// clients that may only read.
+func Execute(ctx context.Context, request Request, refuseMutations error) *graphql.Response {
+	ctx = withLoaders(ctx)
+	ctx = context.WithValue(ctx, mutationsKey{}, mutationsPolicy{refuse: refuseMutations})
+	ctx = context.WithValue(ctx, budgetKey{}, &budget{limit: maxObjects()})
+	return schema.Exec(ctx, request.Query, request.OperationName, request.Variables)
+}
+
+type mutationsKey struct{}
+
+type mutationsPolicy struct {
+	refuse error
+}
+
+// mutationsAllowed fails unless the request may change the inventory.
+func mutationsAllowed(ctx context.Context) error {
+	policy, ok := ctx.Value(mutationsKey{}).(mutationsPolicy)
+	if !ok {
+		return services.ForbiddenError(services.CodeMutationNotAllowed, "mutations must be sent with POST")
+	}
+	return policy.refuse
+}
+
+type budgetKey struct{}
+
+// budget counts the objects a request resolved.
+type budget struct {
+	resolved int64
+	limit    int64
+}
+
+func maxObjects() int64 {
+	if config := services.LiveConfig(); config != nil && config.GraphQLMaxObjects > 0 {
+		return int64(config.GraphQLMaxObjects)
+	}
+	return defaultMaxObjects
+}
+
+// spend counts n more objects resolved by the request and fails once it
+// resolved more than its limit. Fields are resolved in parallel.
+func spend(ctx context.Context, n int) error {
+	b, ok := ctx.Value(budgetKey{}).(*budget)
+	if !ok {
+		return nil
+	}
+	if atomic.AddInt64(&b.resolved, int64(n)) > b.limit {
+		return services.ValidationError(services.CodeQueryTooComplex, nil, "the query resolves more than %d objects; select fewer nested lists", b.limit)
+	}
+	return nil
+}
+
+// resolverError is the error of a field. Its extensions hold the stable code
+// and the HTTP status of the problem that REST would answer with.
+type resolverError struct {
+	message string
+	code    string
+	status  int
+	errors  map[string]string
+}
+
+func (e *resolverError) Error() string {
+	return e.message
+}
+
+func (e *resolverError) Extensions() map[string]any {
+	extensions := map[string]any{"code": e.code, "status": e.status}
+	if len(e.errors) > 0 {
+		extensions["errors"] = e.errors
+	}
+	return extensions
+}
+
+// fieldError maps err like the REST error middleware does.
+func fieldError(ctx context.Context, err error) error {
+	problem := middlewares.ProblemForContext(ctx, err)
+	return &resolverError{message: problem.Detail, code: problem.Code, status: problem.Status, errors: problem.Errors}
+}
@@ -0,0 +1,70 @@
+package graph
+
+import (
+	"context"
+	"github.com/graph-gophers/dataloader/v7"
+	db "greenbone-task/models/db"
+	"greenbone-task/services"
+	"time"
+)
+
+// loaderWait is how long a loader collects keys before it fetches them.
+const loaderWait = 2 * time.Millisecond
+
+// loaders batch and cache the lookups of one request.
+type loaders struct {
+	employees         *dataloader.Loader[uint, *db.Employee]
+	computers         *dataloader.Loader[uint, *db.Computer]
+	assignees         *dataloader.Loader[uint, uint]
+	employeeComputers *dataloader.Loader[uint, []db.Computer]
+	computerHistory   *dataloader.Loader[uint, []db.Assignment]
+	employeeHistory   *dataloader.Loader[uint, []db.Assignment]
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context) context.Context {
+	return context.WithValue(ctx, loadersKey{}, &loaders{
+		employees: newLoader(func(ctx context.Context, ids []uint) (map[uint]*db.Employee, error) {
+			employees, err := services.FindEmployeesByIDs(ctx, ids)
+			return byID(employees, err, func(employee db.Employee) uint { return employee.ID })
+		}),
+		computers: newLoader(func(ctx context.Context, ids []uint) (map[uint]*db.Computer, error) {
+			computers, err := services.FindComputersByIDs(ctx, ids)
+			return byID(computers, err, func(computer db.Computer) uint { return computer.ID })
+		}),
+		assignees:         newLoader(services.AssigneesOfComputers),
+		employeeComputers: newLoader(services.ComputersOfEmployees),
+		computerHistory:   newLoader(services.AssignmentsOfComputers),
+		employeeHistory:   newLoader(services.AssignmentsOfEmployees),
+	})
+}
+
+func loadersOf(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// newLoader returns a loader fetching its keys with fetch. Keys missing in
+// the map fetch returns load the zero value.
+func newLoader[V any](fetch func(ctx context.Context, ids []uint) (map[uint]V, error)) *dataloader.Loader[uint, V] {
+	return dataloader.NewBatchedLoader(func(ctx context.Context, ids []uint) []*dataloader.Result[V] {
+		values, err := fetch(ctx, ids)
+		results := make([]*dataloader.Result[V], len(ids))
+		for i, id := range ids {
+			results[i] = &dataloader.Result[V]{Data: values[id], Error: err}
+		}
+		return results
+	}, dataloader.WithWait[uint, V](loaderWait))
+}
+
+// byID indexes the records returned by a lookup by their ID.
+func byID[T any](records []T, err error, id func(T) uint) (map[uint]*T, error) {
+	if err != nil {
+		return nil, err
+	}
+	indexed := make(map[uint]*T, len(records))
+	for i := range records {
+		indexed[id(records[i])] = &records[i]
+	}
+	return indexed, nil
+}
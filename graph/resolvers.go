@@ -0,0 +1,405 @@
+package graph
+
+import (
+	"context"
+	"github.com/graph-gophers/graphql-go"
+	"greenbone-task/models"
+	db "greenbone-task/models/db"
+	"greenbone-task/services"
+	"sort"
+	"strconv"
+)
+
+// resolver resolves the queries and the mutations.
+type resolver struct{}
+
+func (r *resolver) Computer(ctx context.Context, args struct{ ID graphql.ID }) (*computerResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, fieldError(ctx, err)
+	}
+	computer, err := services.GetComputerByID(ctx, int64(id))
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fieldError(ctx, err)
+	}
+	return &computerResolver{*computer}, nil
+}
+
+func (r *resolver) Computers(ctx context.Context) ([]*computerResolver, error) {
+	computers, err := services.GetAllComputers(ctx)
+	if err != nil {
+		return nil, fieldError(ctx, err)
+	}
+	sort.Slice(computers, func(i, j int) bool { return computers[i].ID < computers[j].ID })
+	if err := spend(ctx, len(computers)); err != nil {
+		return nil, fieldError(ctx, err)
+	}
+	return computerResolvers(computers), nil
+}
+
+func (r *resolver) Employee(ctx context.Context, args struct{ Abbreviation string }) (*employeeResolver, error) {
+	employee, err := services.FindByEmployeeAbbrev(ctx, args.Abbreviation)
+	if isNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fieldError(ctx, err)
+	}
+	return &employeeResolver{employee}, nil
+}
+
+func (r *resolver) Employees(ctx context.Context) ([]*employeeResolver, error) {
+	employees, err := services.GetAllEmployees(ctx)
+	if err != nil {
+		return nil, fieldError(ctx, err)
+	}
+	if err := spend(ctx, len(employees)); err != nil {
+		return nil, fieldError(ctx, err)
+	}
+	resolvers := make([]*employeeResolver, len(employees))
+	for i, employee := range employees {
+		resolvers[i] = &employeeResolver{employee}
+	}
+	return resolvers, nil
+}
+
+func (r *resolver) Assignments(ctx context.Context, args struct {
+	ComputerID           *graphql.ID
+	EmployeeAbbreviation *string
+	CurrentOnly          bool
+}) ([]*assignmentResolver, error) {
+	var filter services.AssignmentFilter
+	if args.ComputerID != nil {
+		id, err := parseID(*args.ComputerID)
+		if err != nil {
+			return nil, fieldError(ctx, err)
+		}
+		filter.ComputerID = id
+	}
+	if args.EmployeeAbbreviation != nil {
+		filter.EmployeeAbbrev = *args.EmployeeAbbreviation
+	}
+	filter.CurrentOnly = args.CurrentOnly
+
+	assignments, err := services.FindAssignments(ctx, filter)
+	if err != nil {
+		return nil, fieldError(ctx, err)
+	}
+	if err := spend(ctx, len(assignments)); err != nil {
+		return nil, fieldError(ctx, err)
+	}
+	return assignmentResolvers(assignments), nil
+}
+
+type employeeInput struct {
+	FirstName    string
+	LastName     string
+	Email        string
+	Abbreviation string
+}
+
+func (r *resolver) CreateEmployee(ctx context.Context, args struct{ Input employeeInput }) (*employeeResolver, error) {
+	if err := mutationsAllowed(ctx); err != nil {
+		return nil, fieldError(ctx, err)
+	}
+	request := models.EmployeeRequest{
+		FirstName:    args.Input.FirstName,
+		LastName:     args.Input.LastName,
+		Email:        args.Input.Email,
+		Abbreviation: args.Input.Abbreviation,
+	}
+	if err := models.ValidateEmployeeRequest(request); err != nil {
+		return nil, fieldError(ctx, invalidInput(err))
+	}
+
+	if err := services.CreateEmployee(ctx, request); err != nil {
+		return nil, fieldError(ctx, err)
+	}
+	employee, err := services.FindByEmployeeAbbrev(ctx, request.Abbreviation)
+	if err != nil {
+		return nil, fieldError(ctx, err)
+	}
+	return &employeeResolver{employee}, nil
+}
+
+type computerInput struct {
+	MacAddress           string
+	Name                 string
+	IPAddress            string
+	EmployeeAbbreviation string
+	Description          *string
+}
+
+func (r *resolver) CreateComputer(ctx context.Context, args struct{ Input computerInput }) (*computerResolver, error) {
+	if err := mutationsAllowed(ctx); err != nil {
+		return nil, fieldError(ctx, err)
+	}
+	computer := db.Computer{
+		MacAddress:     args.Input.MacAddress,
+		ComputerName:   args.Input.Name,
+		IPAddress:      args.Input.IPAddress,
+		EmployeeAbbrev: args.Input.EmployeeAbbreviation,
+	}
+	if args.Input.Description != nil {
+		computer.Description = *args.Input.Description
+	}
+	if err := models.ValidateComputerRequest(computer); err != nil {
+		return nil, fieldError(ctx, invalidInput(err))
+	}
+
+	id, err := services.CreateComputer(ctx, computer)
+	if err != nil {
+		return nil, fieldError(ctx, err)
+	}
+	return r.computer(ctx, id)
+}
+
+func (r *resolver) AssignComputer(ctx context.Context, args struct {
+	ComputerID           graphql.ID
+	EmployeeAbbreviation string
+}) (*computerResolver, error) {
+	if err := mutationsAllowed(ctx); err != nil {
+		return nil, fieldError(ctx, err)
+	}
+	id, err := parseID(args.ComputerID)
+	if err != nil {
+		return nil, fieldError(ctx, err)
+	}
+
+	computer, err := services.AssignComputerToEmployee(ctx, int64(id), args.EmployeeAbbreviation)
+	if err != nil {
+		return nil, fieldError(ctx, err)
+	}
+	return &computerResolver{*computer}, nil
+}
+
+func (r *resolver) DeleteComputer(ctx context.Context, args struct{ ID graphql.ID }) (bool, error) {
+	if err := mutationsAllowed(ctx); err != nil {
+		return false, fieldError(ctx, err)
+	}
+	id, err := parseID(args.ID)
+	if err != nil {
+		return false, fieldError(ctx, err)
+	}
+
+	if err := services.DeleteComputer(ctx, int64(id)); err != nil {
+		return false, fieldError(ctx, err)
+	}
+	return true, nil
+}
+
+// computer returns the computer a mutation changed.
+func (r *resolver) computer(ctx context.Context, id uint) (*computerResolver, error) {
+	computer, err := services.GetComputerByID(ctx, int64(id))
+	if err != nil {
+		return nil, fieldError(ctx, err)
+	}
+	return &computerResolver{*computer}, nil
+}
+
+type computerResolver struct {
+	computer db.Computer
+}
+
+func computerResolvers(computers []db.Computer) []*computerResolver {
+	resolvers := make([]*computerResolver, len(computers))
+	for i, computer := range computers {
+		resolvers[i] = &computerResolver{computer}
+	}
+	return resolvers
+}
+
+func (r *computerResolver) ID() graphql.ID {
+	return formatID(r.computer.ID)
+}
+
+func (r *computerResolver) MacAddress() string {
+	return r.computer.MacAddress
+}
+
+func (r *computerResolver) Name() string {
+	return r.computer.ComputerName
+}
+
+func (r *computerResolver) IPAddress() string {
+	return r.computer.IPAddress
+}
+
+func (r *computerResolver) Description() *string {
+	if r.computer.Description == "" {
+		return nil
+	}
+	return &r.computer.Description
+}
+
+func (r *computerResolver) Employee(ctx context.Context) (*employeeResolver, error) {
+	employeeID, err := loadersOf(ctx).assignees.Load(ctx, r.computer.ID)()
+	if err != nil {
+		return nil, fieldError(ctx, err)
+	}
+	if employeeID == 0 {
+		return nil, nil
+	}
+	return loadEmployee(ctx, employeeID)
+}
+
+func (r *computerResolver) History(ctx context.Context) ([]*assignmentResolver, error) {
+	assignments, err := loadersOf(ctx).computerHistory.Load(ctx, r.computer.ID)()
+	if err != nil {
+		return nil, fieldError(ctx, err)
+	}
+	if err := spend(ctx, len(assignments)); err != nil {
+		return nil, fieldError(ctx, err)
+	}
+	return assignmentResolvers(assignments), nil
+}
+
+func (r *computerResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.computer.CreatedAt}
+}
+
+func (r *computerResolver) UpdatedAt() graphql.Time {
+	return graphql.Time{Time: r.computer.UpdatedAt}
+}
+
+type employeeResolver struct {
+	employee db.Employee
+}
+
+func loadEmployee(ctx context.Context, id uint) (*employeeResolver, error) {
+	if err := spend(ctx, 1); err != nil {
+		return nil, fieldError(ctx, err)
+	}
+	employee, err := loadersOf(ctx).employees.Load(ctx, id)()
+	if err != nil {
+		return nil, fieldError(ctx, err)
+	}
+	if employee == nil {
+		return nil, nil
+	}
+	return &employeeResolver{*employee}, nil
+}
+
+func (r *employeeResolver) ID() graphql.ID {
+	return formatID(r.employee.ID)
+}
+
+func (r *employeeResolver) FirstName() string {
+	return r.employee.FirstName
+}
+
+func (r *employeeResolver) LastName() string {
+	return r.employee.LastName
+}
+
+func (r *employeeResolver) Email() string {
+	return r.employee.Email
+}
+
+func (r *employeeResolver) Abbreviation() string {
+	return r.employee.Abbreviation
+}
+
+func (r *employeeResolver) Computers(ctx context.Context) ([]*computerResolver, error) {
+	computers, err := loadersOf(ctx).employeeComputers.Load(ctx, r.employee.ID)()
+	if err != nil {
+		return nil, fieldError(ctx, err)
+	}
+	if err := spend(ctx, len(computers)); err != nil {
+		return nil, fieldError(ctx, err)
+	}
+	return computerResolvers(computers), nil
+}
+
+func (r *employeeResolver) History(ctx context.Context) ([]*assignmentResolver, error) {
+	assignments, err := loadersOf(ctx).employeeHistory.Load(ctx, r.employee.ID)()
+	if err != nil {
+		return nil, fieldError(ctx, err)
+	}
+	if err := spend(ctx, len(assignments)); err != nil {
+		return nil, fieldError(ctx, err)
+	}
+	return assignmentResolvers(assignments), nil
+}
+
+func (r *employeeResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.employee.CreatedAt}
+}
+
+func (r *employeeResolver) UpdatedAt() graphql.Time {
+	return graphql.Time{Time: r.employee.UpdatedAt}
+}
+
+type assignmentResolver struct {
+	assignment db.Assignment
+}
+
+func assignmentResolvers(assignments []db.Assignment) []*assignmentResolver {
+	resolvers := make([]*assignmentResolver, len(assignments))
+	for i, assignment := range assignments {
+		resolvers[i] = &assignmentResolver{assignment}
+	}
+	return resolvers
+}
+
+func (r *assignmentResolver) ID() graphql.ID {
+	return formatID(r.assignment.ID)
+}
+
+func (r *assignmentResolver) Computer(ctx context.Context) (*computerResolver, error) {
+	if err := spend(ctx, 1); err != nil {
+		return nil, fieldError(ctx, err)
+	}
+	computer, err := loadersOf(ctx).computers.Load(ctx, r.assignment.ComputerID)()
+	if err != nil {
+		return nil, fieldError(ctx, err)
+	}
+	if computer == nil {
+		return nil, nil
+	}
+	return &computerResolver{*computer}, nil
+}
+
+func (r *assignmentResolver) Employee(ctx context.Context) (*employeeResolver, error) {
+	return loadEmployee(ctx, r.assignment.EmployeeID)
+}
+
+func (r *assignmentResolver) AssignedAt() graphql.Time {
+	return graphql.Time{Time: r.assignment.AssignedAt}
+}
+
+func (r *assignmentResolver) UnassignedAt() *graphql.Time {
+	if r.assignment.UnassignedAt == nil {
+		return nil
+	}
+	return &graphql.Time{Time: *r.assignment.UnassignedAt}
+}
+
+func (r *assignmentResolver) Current() bool {
+	return r.assignment.UnassignedAt == nil
+}
+
+func formatID(id uint) graphql.ID {
+	return graphql.ID(strconv.FormatUint(uint64(id), 10))
+}
+
+func parseID(id graphql.ID) (uint, error) {
+	parsed, err := strconv.ParseUint(string(id), 10, 0)
+	if err != nil || parsed == 0 {
+		return 0, services.ValidationError(services.CodeValidationFailed, nil, "invalid ID %q", id)
+	}
+	return uint(parsed), nil
+}
+
+func isNotFound(err error) bool {
+	domainErr, ok := services.AsError(err)
+	return ok && domainErr.Kind == services.KindNotFound
+}
+
+// invalidInput reports input that fails validation, with its fields.
+func invalidInput(err error) error {
+	return services.ValidationError(services.CodeValidationFailed, err, "invalid input")
+}
@@ -0,0 +1,213 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"greenbone-task/logger"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Process exit codes returned by Wait.
+const (
+	ExitOK              = 0 // stopped by a signal and shut down cleanly
+	ExitComponentFailed = 1 // a component failed while running
+	ExitShutdownFailed  = 2 // a stop hook failed or the shutdown timed out
+)
+
+type hook struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// Manager runs the long-lived components of the process and stops them in
+// order when the process receives SIGINT/SIGTERM or a component fails. On
+// SIGHUP it runs the reload hooks and keeps running.
+type Manager struct {
+	timeout  time.Duration
+	mu       sync.Mutex
+	hooks    []hook
+	reloads  []hook
+	reloadMu sync.Mutex
+	failed   chan error
+	shutdown chan struct{}
+	once     sync.Once
+}
+
+// New returns a manager that gives its stop hooks timeout to finish.
+func New(timeout time.Duration) *Manager {
+	return &Manager{
+		timeout:  timeout,
+		failed:   make(chan error, 1),
+		shutdown: make(chan struct{}),
+	}
+}
+
+// Go runs a blocking component such as an HTTP server. If it returns before
+// the shutdown has begun, the manager shuts the process down.
+func (m *Manager) Go(name string, run func() error) {
+	go func() {
+		err := run()
+		select {
+		case <-m.shutdown:
+			return
+		default:
+		}
+		if err == nil {
+			err = errors.New("stopped unexpectedly")
+		}
+		logger.Error("component failed", zap.String("component", name), zap.Error(err))
+		select {
+		case m.failed <- err:
+		default:
+		}
+	}()
+}
+
+// OnStop registers a stop hook. Hooks run in the order they are registered,
+// so register them from the outside in: servers, workers, then pools.
+func (m *Manager) OnStop(name string, stop func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hook{name: name, run: stop})
+}
+
+// OnReload registers a hook that runs, in registration order, every time the
+// process receives SIGHUP or Reload is called. A failing hook is logged and
+// does not stop the process.
+func (m *Manager) OnReload(name string, reload func(ctx context.Context) error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reloads = append(m.reloads, hook{name: name, run: reload})
+}
+
+// Every runs job every interval until the shutdown reaches its stop hook,
+// which cancels the running job and waits for it to return.
+func (m *Manager) Every(name string, interval time.Duration, job func(ctx context.Context) error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := job(ctx); err != nil && ctx.Err() == nil {
+					logger.Error("background job failed", zap.String("job", name), zap.Error(err))
+				}
+			}
+		}
+	}()
+
+	m.OnStop(name, func(stopCtx context.Context) error {
+		cancel()
+		select {
+		case <-done:
+			return nil
+		case <-stopCtx.Done():
+			return stopCtx.Err()
+		}
+	})
+}
+
+// Reload runs the reload hooks as if the process had received SIGHUP, e.g.
+// when a configuration file changes. Reloads do not overlap.
+func (m *Manager) Reload() {
+	m.reloadMu.Lock()
+	defer m.reloadMu.Unlock()
+
+	m.mu.Lock()
+	reloads := append([]hook(nil), m.reloads...)
+	m.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+	for _, h := range reloads {
+		if err := h.run(ctx); err != nil {
+			logger.Error("failed to reload component", zap.String("component", h.name), zap.Error(err))
+		}
+	}
+}
+
+// Shutdown starts the shutdown as if the process had received SIGTERM.
+func (m *Manager) Shutdown() {
+	m.once.Do(func() { close(m.shutdown) })
+}
+
+// Wait blocks until a signal, a call to Shutdown or a component failure, then
+// runs the stop hooks and returns the exit code for the process. A second
+// signal during the shutdown exits immediately.
+func (m *Manager) Wait() int {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer signal.Stop(signals)
+
+	code := ExitOK
+	for waiting := true; waiting; {
+		select {
+		case sig := <-signals:
+			if sig == syscall.SIGHUP {
+				logger.Info("received SIGHUP, reloading")
+				m.Reload()
+				continue
+			}
+			logger.Info("received signal, shutting down", zap.String("signal", sig.String()))
+		case <-m.shutdown:
+			logger.Info("shutting down")
+		case <-m.failed:
+			code = ExitComponentFailed
+		}
+		waiting = false
+	}
+	m.Shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	stopped := make(chan bool, 1)
+	go func() { stopped <- m.stop(ctx) }()
+
+	for {
+		select {
+		case ok := <-stopped:
+			if !ok && code == ExitOK {
+				code = ExitShutdownFailed
+			}
+			logger.Info("shutdown complete", zap.Int("exit_code", code))
+			return code
+		case sig := <-signals:
+			if sig == syscall.SIGHUP {
+				continue
+			}
+			logger.Error("received second signal, exiting immediately", zap.String("signal", sig.String()))
+			return ExitShutdownFailed
+		}
+	}
+}
+
+// stop runs every hook in order and reports whether all of them succeeded.
+func (m *Manager) stop(ctx context.Context) bool {
+	m.mu.Lock()
+	hooks := append([]hook(nil), m.hooks...)
+	m.mu.Unlock()
+
+	ok := true
+	for _, h := range hooks {
+		started := time.Now()
+		if err := h.run(ctx); err != nil {
+			logger.Error("failed to stop component", zap.String("component", h.name), zap.Error(err))
+			ok = false
+			continue
+		}
+		logger.Info("stopped component", zap.String("component", h.name), zap.Duration("took", time.Since(started)))
+	}
+	return ok
+}
@@ -1,24 +1,166 @@
 package logger
 
 import (
+	"fmt"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+	"io"
+	"os"
+	"sync"
 )
 
+// Log encodings supported by Configure.
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console"
+)
+
+// Options configures the level, encoding and sinks of the loggers.
+type Options struct {
+	Level  string // debug, info, warn or error
+	Format string // json or console
+
+	// File is an additional sink rotated by size, at most MaxSizeMB, keeping
+	// MaxBackups old files for MaxAgeDays. Empty disables it.
+	File       string
+	MaxSizeMB  int
+	MaxBackups int
+	MaxAgeDays int
+	Compress   bool
+
+	// Syslog sends the log to the syslog daemon at SyslogAddress over
+	// SyslogNetwork ("udp" or "tcp"), or to the local one if both are empty.
+	Syslog        bool
+	SyslogNetwork string
+	SyslogAddress string
+}
+
 var zapLog *zap.Logger
 
+// plainLog is zapLog without the caller skip of the package level functions.
+var plainLog *zap.Logger
+
+// level is shared by every logger built by this package, so changing it at
+// runtime applies everywhere.
+var level = zap.NewAtomicLevelAt(zap.InfoLevel)
+
+var (
+	mu      sync.Mutex
+	options = Options{Format: FormatJSON, MaxSizeMB: 100, MaxBackups: 7, MaxAgeDays: 30, Compress: true}
+	files   []*lumberjack.Logger
+)
+
 func init() {
-	var err error
-	config := zap.NewProductionConfig()
+	build(zapcore.AddSync(os.Stderr))
+}
+
+// Configure rebuilds the default logger from opts. It is meant to be called
+// once on start, before the loggers are used concurrently.
+func Configure(opts Options) error {
+	if err := SetLevel(opts.Level); err != nil {
+		return err
+	}
+	if opts.Format == "" {
+		opts.Format = FormatJSON
+	}
+	if opts.Format != FormatJSON && opts.Format != FormatConsole {
+		return fmt.Errorf("unknown log format %q", opts.Format)
+	}
+
+	mu.Lock()
+	options = opts
+	mu.Unlock()
+
+	sinks := []zapcore.WriteSyncer{zapcore.AddSync(os.Stderr)}
+	if opts.File != "" {
+		sinks = append(sinks, zapcore.AddSync(RotatingFile(opts.File)))
+	}
+	if opts.Syslog {
+		writer, err := dialSyslog(opts.SyslogNetwork, opts.SyslogAddress)
+		if err != nil {
+			return fmt.Errorf("error connecting to syslog: %w", err)
+		}
+		sinks = append(sinks, zapcore.AddSync(writer))
+	}
+
+	build(zapcore.NewMultiWriteSyncer(sinks...))
+	return nil
+}
+
+func build(sink zapcore.WriteSyncer) {
+	core := zapcore.NewCore(encoder(), sink, level)
+	zapLog = zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1), zap.ErrorOutput(zapcore.AddSync(os.Stderr)))
+	plainLog = zapLog.WithOptions(zap.AddCallerSkip(-1))
+}
+
+func encoder() zapcore.Encoder {
 	enccoderConfig := zap.NewProductionEncoderConfig()
 	zapcore.TimeEncoderOfLayout("Jan _2 15:04:05.000000000")
 	enccoderConfig.StacktraceKey = "" // to hide stacktrace info
-	config.EncoderConfig = enccoderConfig
 
-	zapLog, err = config.Build(zap.AddCallerSkip(1))
-	if err != nil {
-		panic(err)
+	mu.Lock()
+	defer mu.Unlock()
+	if options.Format == FormatConsole {
+		enccoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+		enccoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+		return zapcore.NewConsoleEncoder(enccoderConfig)
+	}
+	return zapcore.NewJSONEncoder(enccoderConfig)
+}
+
+// New returns a logger writing to w in the configured format, e.g. for the
+// access log. It always logs at info level.
+func New(w io.Writer) *zap.Logger {
+	core := zapcore.NewCore(encoder(), zapcore.AddSync(w), zap.InfoLevel)
+	return zap.New(core)
+}
+
+// RotatingFile returns a writer appending to path that is rotated by size
+// and by Rotate, with the retention set by Configure.
+func RotatingFile(path string) io.Writer {
+	mu.Lock()
+	defer mu.Unlock()
+
+	file := &lumberjack.Logger{
+		Filename:   path,
+		MaxSize:    options.MaxSizeMB,
+		MaxBackups: options.MaxBackups,
+		MaxAge:     options.MaxAgeDays,
+		Compress:   options.Compress,
 	}
+	files = append(files, file)
+	return file
+}
+
+// Rotate starts a new file for every rotating file, e.g. once a day.
+func Rotate() error {
+	mu.Lock()
+	rotating := append([]*lumberjack.Logger(nil), files...)
+	mu.Unlock()
+
+	for _, file := range rotating {
+		if err := file.Rotate(); err != nil {
+			return fmt.Errorf("error rotating %s: %w", file.Filename, err)
+		}
+	}
+	return nil
+}
+
+// SetLevel changes the level of the default logger and of the loggers
+// derived from it.
+func SetLevel(name string) error {
+	var parsed zapcore.Level
+	if err := parsed.UnmarshalText([]byte(name)); err != nil {
+		return fmt.Errorf("unknown log level %q", name)
+	}
+	level.SetLevel(parsed)
+	return nil
+}
+
+// Level returns the current log level.
+func Level() string {
+	return level.Level().String()
 }
 
 func Info(message string, fields ...zap.Field) {
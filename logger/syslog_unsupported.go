@@ -0,0 +1,12 @@
+//go:build windows || plan9
+
+package logger
+
+import (
+	"errors"
+	"io"
+)
+
+func dialSyslog(string, string) (io.Writer, error) {
+	return nil, errors.New("syslog is not supported on this platform")
+}
@@ -2,54 +2,205 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"greenbone-task/admin"
+	"greenbone-task/certs"
+	"greenbone-task/lifecycle"
+	"greenbone-task/logger"
 	"greenbone-task/routes"
+	"greenbone-task/rpc"
 	"greenbone-task/services"
+	"net"
 	"net/http"
 	"os"
-	"time"
 )
 
 func main() {
-	logger, _ := zap.NewDevelopment()
-	defer logger.Sync()
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "migrate":
+			os.Exit(admin.Migrate(os.Args[2:], os.Stdout, os.Stderr))
+		case "config":
+			os.Exit(admin.Config(os.Args[2:], os.Stdout, os.Stderr))
+		case "create-user":
+			os.Exit(admin.CreateUser(os.Args[2:], os.Stdout, os.Stderr))
+		case "reconcile":
+			os.Exit(admin.Reconcile(os.Args[2:], os.Stdout, os.Stderr))
+		}
+	}
+	os.Exit(run(os.Args[1:]))
+}
+
+// run starts the server and blocks until it has shut down, returning the exit code.
+func run(args []string) int {
+	args, err := services.LoadConfig(args)
+	if err != nil {
+		return admin.ConfigFailed(err, os.Stderr)
+	}
+	if len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "unknown command %q, expected migrate, config, create-user or reconcile\n", args[0])
+		return 2
+	}
+	if err := services.ConfigureLogging(); err != nil {
+		logger.Error("failed to set up logging", zap.Error(err))
+		return lifecycle.ExitComponentFailed
+	}
+
+	shutdownTracing, err := services.InitTracing()
+	if err != nil {
+		logger.Error("failed to set up tracing", zap.Error(err))
+		return lifecycle.ExitComponentFailed
+	}
 
-	services.LoadConfig()
 	services.ConnectDB()
+	if services.DbConnection == nil {
+		return lifecycle.ExitComponentFailed
+	}
 
 	if services.Config.UseRedis {
-		services.CheckRedisConnection()
+		if err := services.CheckRedisConnection(); err != nil {
+			return lifecycle.ExitComponentFailed
+		}
+	}
+
+	if err := services.ConfigureRateLimiting(); err != nil {
+		logger.Error("failed to set up rate limiting", zap.Error(err))
+		return lifecycle.ExitComponentFailed
 	}
 
+	// send administrator notifications from a background worker, with the
+	// configured timeout
+	dispatcher := services.NewNotificationDispatcher(services.NewNotificationService(), services.Config.NotificationQueueSize)
+	services.Notifier = dispatcher
+
+	// handle the side effects of changes that follow their transaction on
+	// background workers, in order for each computer and employee
+	services.Events.Start(services.Config.EventWorkers, services.Config.EventQueueSize)
+
+	// send the webhook deliveries when they are stored, and retry the failed
+	// ones every poll interval
+	services.WebhookWorker = services.NewWebhookDispatcher(services.Config.WebhookPollInterval)
+
 	routes.InitGin()
 	router := routes.New()
 
 	server := &http.Server{
-		Addr:         services.Config.ServerHost + ":" + services.Config.ServerPort,
-		WriteTimeout: time.Second * 30,
-		ReadTimeout:  time.Second * 30,
-		IdleTimeout:  time.Second * 30,
-		Handler:      router,
+		Addr:              services.Config.ServerHost + ":" + services.Config.ServerPort,
+		WriteTimeout:      services.Config.ServerWriteTimeout,
+		ReadTimeout:       services.Config.ServerReadTimeout,
+		ReadHeaderTimeout: services.Config.ServerReadHeaderTimeout,
+		IdleTimeout:       services.Config.ServerIdleTimeout,
+		Handler:           router,
 	}
 
-	go func() {
-		if err := server.ListenAndServe(); err != nil {
-			logger.Error("listen", zap.Error(err))
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		logger.Error("failed to listen on "+server.Addr, zap.Error(err))
+		return lifecycle.ExitComponentFailed
+	}
+
+	var certificates *certs.Reloader
+	if services.TLSEnabled() {
+		certificates, err = services.LoadCertificates()
+		if err != nil {
+			logger.Error("failed to load the TLS certificates", zap.Error(err))
+			return lifecycle.ExitComponentFailed
 		}
-	}()
+		listener = tls.NewListener(listener, certificates.TLSConfig())
+	}
+
+	// serve gRPC on its own port with the same certificates
+	var grpcServer *grpc.Server
+	var grpcListener net.Listener
+	if services.Config.GRPCPort != "" {
+		grpcAddr := services.Config.ServerHost + ":" + services.Config.GRPCPort
+		grpcListener, err = net.Listen("tcp", grpcAddr)
+		if err != nil {
+			logger.Error("failed to listen on "+grpcAddr, zap.Error(err))
+			return lifecycle.ExitComponentFailed
+		}
+		var options []grpc.ServerOption
+		if certificates != nil {
+			options = append(options, grpc.Creds(credentials.NewTLS(certificates.TLSConfig())))
+		}
+		grpcServer = rpc.NewServer(options...)
+	}
 
-	// Wait for interrupt signal to gracefully shut down the server with
-	// a timeout of 15 seconds.
-	quit := make(chan os.Signal, 1)
-	//signal.Notify(quit, os.Interrupt)
-	<-quit
-	logger.Info("Shutdown Server")
+	// Shut down gracefully on SIGINT/SIGTERM within SHUTDOWN_TIMEOUT: report
+	// not ready, drain in-flight requests, stop the workers, then close the
+	// pools.
+	app := lifecycle.New(services.Config.ShutdownTimeout)
+	app.Go("http server", func() error {
+		logger.Info("Starting server on "+server.Addr, zap.Bool("tls", certificates != nil))
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	})
+	if grpcServer != nil {
+		app.Go("grpc server", func() error {
+			logger.Info("Starting gRPC server on "+grpcListener.Addr().String(), zap.Bool("tls", certificates != nil))
+			return grpcServer.Serve(grpcListener)
+		})
+	}
+	services.SetReady(true)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-	defer cancel()
-	if err := server.Shutdown(ctx); err != nil {
-		logger.Fatal("Server Shutdown", zap.Error(err))
+	app.OnStop("readiness", func(context.Context) error {
+		services.SetReady(false)
+		return nil
+	})
+	app.OnStop("http server", server.Shutdown)
+	if grpcServer != nil {
+		app.OnStop("grpc server", func(ctx context.Context) error { return rpc.Stop(ctx, grpcServer) })
+	}
+	// apply the reloadable settings on SIGHUP and when a configuration file
+	// changes, then point the notifications at the configured target
+	app.OnReload("configuration", services.ReloadConfig)
+	app.OnReload("notification target", func(context.Context) error {
+		dispatcher.SetNext(services.NewNotificationService())
+		return nil
+	})
+	configCtx, stopConfigWatch := context.WithCancel(context.Background())
+	go func() {
+		if err := services.WatchConfig(configCtx, app.Reload); err != nil {
+			logger.Error("not watching the configuration files, reload them with SIGHUP", zap.Error(err))
+		}
+	}()
+	app.OnStop("configuration watcher", func(context.Context) error {
+		stopConfigWatch()
+		return nil
+	})
+	if certificates != nil {
+		// pick up renewed certificates when their files change or on SIGHUP
+		watchCtx, stopWatching := context.WithCancel(context.Background())
+		go func() {
+			if err := certificates.Watch(watchCtx); err != nil {
+				logger.Error("not watching the TLS certificates, reload them with SIGHUP", zap.Error(err))
+			}
+		}()
+		app.OnStop("certificate watcher", func(context.Context) error {
+			stopWatching()
+			return nil
+		})
+		app.OnReload("certificates", func(context.Context) error { return certificates.Reload() })
+	}
+	app.OnStop("event bus", services.Events.Stop)
+	app.OnStop("notification dispatcher", dispatcher.Stop)
+	app.OnStop("webhook dispatcher", services.WebhookWorker.Stop)
+	if services.Config.TokenCleanupInterval > 0 {
+		app.Every("token cleanup", services.Config.TokenCleanupInterval, services.DeleteExpiredTokens)
+	}
+	if services.Config.LogRotateInterval > 0 {
+		app.Every("log rotation", services.Config.LogRotateInterval, services.RotateLogs)
 	}
+	app.OnStop("database", func(context.Context) error { return services.CloseDB() })
+	app.OnStop("redis", func(context.Context) error { return services.CloseRedis() })
+	app.OnStop("tracing", shutdownTracing)
 
-	logger.Info("Server exiting")
+	return app.Wait()
 }
@@ -0,0 +1,121 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+)
+
+const namespace = "greenbone"
+
+// Cache names used as the "cache" label of the cache counters.
+const (
+	CacheComputer            = "computer"
+	CacheComputersByEmployee = "computers_by_employee"
+)
+
+var (
+	// HTTPRequestDuration is labelled with the gin route template rather than
+	// the request path, so path parameters do not create new series.
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of HTTP requests by method, route template and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Duration of database queries by operation and table.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table"})
+
+	CacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "requests_total",
+		Help:      "Redis cache lookups by cache and result (hit or miss).",
+	}, []string{"cache", "result"})
+
+	Notifications = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "notifications",
+		Name:      "sent_total",
+		Help:      "Administrator notifications by result (success or failure).",
+	}, []string{"result"})
+
+	WebhookAttempts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "webhooks",
+		Name:      "attempts_total",
+		Help:      "Webhook delivery attempts by result (success, retry or dead).",
+	}, []string{"result"})
+
+	EventsHandled = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "events",
+		Name:      "handled_total",
+		Help:      "Domain events handled by event, subscriber and result (success or failure).",
+	}, []string{"event", "subscriber", "result"})
+
+	RateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "rate_limited_total",
+		Help:      "Requests rejected by the rate limiter by scope (ip, route, user or api_key).",
+	}, []string{"scope"})
+)
+
+// Registry holds the application metrics together with the Go runtime and
+// process collectors.
+var Registry = prometheus.NewRegistry()
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestDuration,
+		DBQueryDuration,
+		CacheRequests,
+		Notifications,
+		WebhookAttempts,
+		EventsHandled,
+		RateLimited,
+	)
+}
+
+// Handler serves the metrics of Registry in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// ObserveCache counts a lookup in the given cache.
+func ObserveCache(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	CacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+// ObserveNotification counts a notification attempt that ended with err.
+func ObserveNotification(err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	Notifications.WithLabelValues(result).Inc()
+}
+
+// ObserveEvent counts an event handled by subscriber that ended with err.
+func ObserveEvent(event string, subscriber string, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	EventsHandled.WithLabelValues(event, subscriber, result).Inc()
+}
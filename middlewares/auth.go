@@ -1,25 +1,113 @@
 package middlewares
 
 import (
+	"crypto/x509"
 	"github.com/gin-gonic/gin"
-	"greenbone-task/models"
+	"go.uber.org/zap"
+	"greenbone-task/constants"
+	"greenbone-task/logger"
 	db "greenbone-task/models/db"
 	"greenbone-task/services"
 	"net/http"
 )
 
+// ClientIdentityKey is the gin context key of the common name of a client
+// authenticated with a certificate.
+const ClientIdentityKey = "clientIdentity"
+
+// ClientRoleKey is the gin context key of the role of a client authenticated
+// with a certificate.
+const ClientRoleKey = "clientRole"
+
+// AuthMiddleware authenticates the request with a verified client
+// certificate if the client sent one over mutual TLS, and otherwise with the
+// Bearer-Token like JWTMiddleware. Read-only clients may only use read
+// methods.
+func AuthMiddleware() gin.HandlerFunc {
+	return authenticate(true)
+}
+
+// QueryAuthMiddleware authenticates like AuthMiddleware, but lets read-only
+// clients use any method, for endpoints like GraphQL that read with POST and
+// authorize the changes themselves by the ClientRoleKey.
+func QueryAuthMiddleware() gin.HandlerFunc {
+	return authenticate(false)
+}
+
+func authenticate(readOnlyMethods bool) gin.HandlerFunc {
+	jwt := JWTMiddleware()
+	return func(c *gin.Context) {
+		certificate := verifiedClientCertificate(c.Request)
+		if certificate == nil {
+			jwt(c)
+			return
+		}
+
+		identity, err := services.ClientIdentity(certificate)
+		if err != nil {
+			SendError(c, err)
+			return
+		}
+		if readOnlyMethods && identity.Role == constants.RoleReadOnly && !readOnlyMethod(c.Request.Method) {
+			SendError(c, services.ForbiddenError(services.CodeInsufficientRole, "client %s may only read", identity.Name))
+			return
+		}
+
+		c.Set(ClientIdentityKey, identity.Name)
+		c.Set(ClientRoleKey, identity.Role)
+		c.Request = c.Request.WithContext(logger.With(c.Request.Context(), zap.String("client", identity.Name)))
+		if !allowUser(c, "client:"+identity.Name) {
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AdminMiddleware lets only clients whose certificate has the admin role
+// through. It runs after AuthMiddleware. Access tokens carry no role, since
+// anyone can generate one, so token users are forbidden.
+func AdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString(ClientRoleKey) != constants.RoleAdmin {
+			SendError(c, services.ForbiddenError(services.CodeInsufficientRole, "only clients with the %s role may use this endpoint", constants.RoleAdmin))
+			return
+		}
+		c.Next()
+	}
+}
+
 func JWTMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := c.GetHeader("Bearer-Token")
-		tokenModel, err := services.VerifyToken(token, db.TokenTypeAccess)
+		tokenModel, err := services.VerifyToken(c.Request.Context(), token, db.TokenTypeAccess)
 		if err != nil {
-			models.SendErrorResponse(c, http.StatusUnauthorized, err.Error())
+			SendError(c, err)
 			return
 		}
 
 		c.Set("userIdHex", tokenModel.ID)
 		c.Set("userId", tokenModel.ID)
+		c.Request = c.Request.WithContext(logger.With(c.Request.Context(), zap.Int64("user_id", tokenModel.ID)))
+		// every token has a new ID, so the user is limited by the email of
+		// its tokens
+		if !allowUser(c, "user:"+tokenModel.Email) {
+			return
+		}
 
 		c.Next()
 	}
 }
+
+// verifiedClientCertificate returns the client certificate that the TLS
+// handshake verified against the client CA, if any.
+func verifiedClientCertificate(r *http.Request) *x509.Certificate {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return r.TLS.VerifiedChains[0][0]
+}
+
+func readOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
@@ -2,15 +2,84 @@ package middlewares
 
 import (
 	"github.com/gin-gonic/gin"
+	"greenbone-task/models"
+	"greenbone-task/services"
+	"net/http"
+	"strconv"
+	"strings"
 )
 
+// Methods and headers cross-origin clients may use, and response headers they
+// may read.
+const (
+	corsAllowedMethods = "GET, POST, PUT, PATCH, DELETE, OPTIONS"
+	corsAllowedHeaders = "Content-Type, Accept, Authorization, Bearer-Token, X-API-Key, X-Request-ID, Cache-Control, X-Requested-With"
+	corsExposedHeaders = "X-Request-ID, Retry-After"
+)
+
+// CORSMiddleware allows cross-origin requests from the origins in
+// CORS_ALLOWED_ORIGINS and answers their preflight requests. Requests from
+// other origins get no CORS headers, so browsers do not let pages read the
+// response; their preflight requests are rejected.
 func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+		c.Writer.Header().Add("Vary", "Origin")
+
+		// the allowed origins may change when the configuration is reloaded
+		config := services.LiveConfig()
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		allowOrigin, allowed := allowedOrigin(origin, config)
+		if !allowed {
+			if preflight {
+				problem := models.NewProblem(http.StatusForbidden, "origin_not_allowed", "cross-origin requests from "+origin+" are not allowed")
+				problem.RequestID = c.GetString(RequestIDKey)
+				models.SendProblem(c, problem)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		header := c.Writer.Header()
+		header.Set("Access-Control-Allow-Origin", allowOrigin)
+		if config.CORSAllowCredentials && allowOrigin != "*" {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
 
+		if preflight {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+			header.Set("Access-Control-Allow-Methods", corsAllowedMethods)
+			header.Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+			if maxAge := config.CORSMaxAge; maxAge > 0 {
+				header.Set("Access-Control-Max-Age", strconv.Itoa(int(maxAge.Seconds())))
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		header.Set("Access-Control-Expose-Headers", corsExposedHeaders)
 		c.Next()
 	}
 }
+
+// allowedOrigin returns the Access-Control-Allow-Origin value for origin. A
+// listed origin is echoed; with "*" any origin is allowed, but without
+// credentials even if CORS_ALLOW_CREDENTIALS is set, as echoing the origin
+// would let any site make credentialed requests.
+func allowedOrigin(origin string, config *models.EnvConfig) (string, bool) {
+	for _, candidate := range config.CORSOrigins() {
+		if candidate == "*" {
+			return "*", true
+		}
+		if strings.EqualFold(candidate, origin) {
+			return origin, true
+		}
+	}
+	return "", false
+}
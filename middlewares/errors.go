@@ -0,0 +1,82 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"go.uber.org/zap"
+	"greenbone-task/logger"
+	"greenbone-task/models"
+	"greenbone-task/services"
+	"net/http"
+)
+
+// CodeInternalError is returned for errors that are not domain errors. Their
+// message is logged but not shown to clients.
+const CodeInternalError = "internal_error"
+
+// statusByKind maps the kinds of domain errors to HTTP status codes.
+var statusByKind = map[services.ErrorKind]int{
+	services.KindNotFound:      http.StatusNotFound,
+	services.KindConflict:      http.StatusConflict,
+	services.KindValidation:    http.StatusBadRequest,
+	services.KindQuotaExceeded: http.StatusTooManyRequests,
+	services.KindUpstream:      http.StatusBadGateway,
+	services.KindUnauthorized:  http.StatusUnauthorized,
+	services.KindForbidden:     http.StatusForbidden,
+}
+
+// ErrorMiddleware turns the last error a handler added with c.Error into an
+// application/problem+json response, unless the handler already responded.
+func ErrorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		SendError(c, c.Errors.Last().Err)
+	}
+}
+
+// SendError responds with the problem for err.
+func SendError(c *gin.Context, err error) {
+	problem := ProblemFor(c, err)
+	problem.RequestID = c.GetString(RequestIDKey)
+	models.SendProblem(c, problem)
+}
+
+// ProblemFor maps err to a problem. Domain errors keep their code and
+// message, any other error is reported as an internal error.
+func ProblemFor(c *gin.Context, err error) *models.Problem {
+	return ProblemForContext(c.Request.Context(), err)
+}
+
+// ProblemForContext is ProblemFor for a request that is not handled by gin
+// directly, e.g. a GraphQL resolver.
+func ProblemForContext(ctx context.Context, err error) *models.Problem {
+	domainErr, ok := services.AsError(err)
+	if !ok {
+		logger.FromContext(ctx).Error("request failed", zap.Error(err))
+		return models.NewProblem(http.StatusInternalServerError, CodeInternalError, "the server could not process the request")
+	}
+
+	status, ok := statusByKind[domainErr.Kind]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+	if status >= http.StatusInternalServerError {
+		logger.FromContext(ctx).Error("request failed", zap.String("code", domainErr.Code), zap.Error(err))
+	}
+
+	problem := models.NewProblem(status, domainErr.Code, domainErr.Message)
+	var fieldErrs validation.Errors
+	if errors.As(domainErr, &fieldErrs) {
+		problem.Errors = make(map[string]string, len(fieldErrs))
+		for field, fieldErr := range fieldErrs {
+			problem.Errors[field] = fieldErr.Error()
+		}
+	}
+	return problem
+}
@@ -1,19 +1,56 @@
 package middlewares
 
 import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"greenbone-task/logger"
 	"io"
 	"os"
 	"path"
+	"time"
 )
 
 const LogPath = "logs/"
 const LogFile = "access.log"
 
+// LogWriter writes the access log to standard output and to a file that is
+// rotated with the retention of the application log.
 func LogWriter() io.Writer {
-	_ = os.Mkdir(LogPath, 0770)
-
 	logFilePath := path.Join(LogPath, LogFile)
+	return io.MultiWriter(logger.RotatingFile(logFilePath), os.Stdout)
+}
+
+// AccessLogger writes one JSON line per request to w. It has to run after
+// RequestIDMiddleware to log the request ID.
+func AccessLogger(w io.Writer) gin.HandlerFunc {
+	accessLog := logger.New(w)
+
+	return func(c *gin.Context) {
+		started := time.Now()
+		c.Next()
 
-	logFile, _ := os.OpenFile(logFilePath, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0660)
-	return io.MultiWriter(logFile, os.Stdout)
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		fields := []zap.Field{
+			zap.String("request_id", c.GetString(RequestIDKey)),
+			zap.String("method", c.Request.Method),
+			zap.String("route", route),
+			zap.String("path", c.Request.URL.Path),
+			zap.Int("status", c.Writer.Status()),
+			zap.Duration("latency", time.Since(started)),
+			zap.String("client_ip", c.ClientIP()),
+		}
+		if userID, ok := c.Get("userId"); ok {
+			fields = append(fields, zap.Any("user_id", userID))
+		}
+		if client := c.GetString(ClientIdentityKey); client != "" {
+			fields = append(fields, zap.String("client", client))
+		}
+		if len(c.Errors) > 0 {
+			fields = append(fields, zap.String("errors", c.Errors.String()))
+		}
+		accessLog.Info("request", fields...)
+	}
 }
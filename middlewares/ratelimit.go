@@ -0,0 +1,89 @@
+package middlewares
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"greenbone-task/logger"
+	"greenbone-task/metrics"
+	"greenbone-task/ratelimit"
+	"greenbone-task/services"
+	"math"
+	"strconv"
+)
+
+// APIKeyHeader identifies API clients for the per API key rate limit.
+const APIKeyHeader = "X-API-Key"
+
+// unlimitedPaths are polled by probes and scrapers.
+var unlimitedPaths = untracedPaths
+
+type rateLimitCheck struct {
+	scope string
+	key   string
+	limit ratelimit.Limit
+}
+
+// RateLimitMiddleware applies the per IP, per route and per API key limits
+// of the rate limit policy. The per user limit is applied by AuthMiddleware
+// once the user is known.
+func RateLimitMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policy := services.RateLimitPolicy()
+		if policy == nil || unlimitedPaths[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
+
+		ip := c.ClientIP()
+		checks := []rateLimitCheck{{scope: "ip", key: "ip:" + ip, limit: policy.IP}}
+		if limit, ok := policy.Routes[c.FullPath()]; ok {
+			checks = append(checks, rateLimitCheck{scope: "route", key: "route:" + c.FullPath() + ":" + ip, limit: limit})
+		}
+		if apiKey := c.GetHeader(APIKeyHeader); apiKey != "" {
+			// keep the key itself out of Redis
+			sum := sha256.Sum256([]byte(apiKey))
+			checks = append(checks, rateLimitCheck{scope: "api_key", key: "api_key:" + hex.EncodeToString(sum[:]), limit: policy.APIKey})
+		}
+
+		if !allowRequest(c, checks...) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// allowUser applies the per user limit to the authenticated user or client
+// identified by key and reports whether the request may continue.
+func allowUser(c *gin.Context, key string) bool {
+	policy := services.RateLimitPolicy()
+	if policy == nil {
+		return true
+	}
+	return allowRequest(c, rateLimitCheck{scope: "user", key: key, limit: policy.User})
+}
+
+// allowRequest takes a token for each check. If one is exhausted it aborts
+// with 429 and a Retry-After header. The request is let through if the
+// limiter fails.
+func allowRequest(c *gin.Context, checks ...rateLimitCheck) bool {
+	for _, check := range checks {
+		result, err := services.RateLimiter.Allow(c.Request.Context(), check.key, check.limit)
+		if err != nil {
+			logger.FromContext(c.Request.Context()).Error("rate limiter failed", zap.String("scope", check.scope), zap.Error(err))
+			continue
+		}
+		if result.Allowed {
+			continue
+		}
+
+		metrics.RateLimited.WithLabelValues(check.scope).Inc()
+		retryAfter := int(math.Max(1, math.Ceil(result.RetryAfter.Seconds())))
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		_ = c.Error(services.QuotaExceededError(services.CodeRateLimitExceeded, "too many requests, retry in %d seconds", retryAfter))
+		c.Abort()
+		return false
+	}
+	return true
+}
@@ -1,17 +1,20 @@
 package middlewares
 
 import (
+	"fmt"
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"greenbone-task/logger"
 	"greenbone-task/models"
 	"net/http"
 )
 
 func AppRecovery() func(c *gin.Context, recovered interface{}) {
 	return func(c *gin.Context, recovered interface{}) {
-		if err, ok := recovered.(string); ok {
-			models.SendErrorResponse(c, http.StatusInternalServerError, err)
-			return
-		}
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"success": false}) // recovery failed
+		logger.FromContext(c.Request.Context()).Error("request panicked", zap.String("panic", fmt.Sprint(recovered)))
+
+		problem := models.NewProblem(http.StatusInternalServerError, CodeInternalError, "the server could not process the request")
+		problem.RequestID = c.GetString(RequestIDKey)
+		models.SendProblem(c, problem)
 	}
 }
@@ -0,0 +1,40 @@
+package middlewares
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"greenbone-task/logger"
+	"regexp"
+)
+
+// RequestIDHeader carries the ID that correlates the log lines of a request.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDKey is the gin context key of the request ID.
+const RequestIDKey = "requestId"
+
+// validRequestID limits client supplied IDs to what is safe to log.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestIDMiddleware reuses the X-Request-ID of the caller or assigns a new
+// one, returns it in the response and adds it to the request's logger.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+		c.Set(RequestIDKey, requestID)
+		c.Header(RequestIDHeader, requestID)
+
+		fields := []zap.Field{zap.String("request_id", requestID)}
+		if span := trace.SpanContextFromContext(c.Request.Context()); span.IsValid() {
+			fields = append(fields, zap.String("trace_id", span.TraceID().String()))
+		}
+		c.Request = c.Request.WithContext(logger.With(c.Request.Context(), fields...))
+
+		c.Next()
+	}
+}
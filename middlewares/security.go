@@ -0,0 +1,38 @@
+package middlewares
+
+import (
+	"github.com/gin-gonic/gin"
+	"greenbone-task/services"
+	"strconv"
+	"strings"
+)
+
+// apiContentSecurityPolicy forbids loading anything, since the API only
+// returns JSON.
+const apiContentSecurityPolicy = "default-src 'none'; frame-ancestors 'none'"
+
+// swaggerContentSecurityPolicy lets the swagger UI load its own scripts,
+// styles and images, including the inline script that starts it.
+const swaggerContentSecurityPolicy = "default-src 'self'; script-src 'self' 'unsafe-inline'; " +
+	"style-src 'self' 'unsafe-inline'; img-src 'self' data:; frame-ancestors 'none'"
+
+// SecurityHeadersMiddleware sets the headers that keep browsers from
+// sniffing, framing or downgrading the responses.
+func SecurityHeadersMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		header.Set("X-Content-Type-Options", "nosniff")
+		header.Set("X-Frame-Options", "DENY")
+		header.Set("Referrer-Policy", "no-referrer")
+		if maxAge := services.Config.HSTSMaxAge; maxAge > 0 {
+			header.Set("Strict-Transport-Security", "max-age="+strconv.Itoa(int(maxAge.Seconds()))+"; includeSubDomains")
+		}
+		if strings.HasPrefix(c.Request.URL.Path, "/swagger/") {
+			header.Set("Content-Security-Policy", swaggerContentSecurityPolicy)
+		} else {
+			header.Set("Content-Security-Policy", apiContentSecurityPolicy)
+		}
+
+		c.Next()
+	}
+}
@@ -4,7 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"greenbone-task/models"
-	"net/http"
+	"greenbone-task/services"
 )
 
 func AuthValidator() gin.HandlerFunc {
@@ -14,7 +14,8 @@ func AuthValidator() gin.HandlerFunc {
 		_ = c.ShouldBindBodyWith(&authRequest, binding.JSON)
 
 		if err := authRequest.Validate(); err != nil {
-			models.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+			_ = c.Error(services.ValidationError(services.CodeValidationFailed, err, "invalid auth request"))
+			c.Abort()
 			return
 		}
 
@@ -29,7 +30,8 @@ func RefreshValidator() gin.HandlerFunc {
 		_ = c.ShouldBindBodyWith(&refreshRequest, binding.JSON)
 
 		if err := refreshRequest.Validate(); err != nil {
-			models.SendErrorResponse(c, http.StatusBadRequest, err.Error())
+			_ = c.Error(services.ValidationError(services.CodeValidationFailed, err, "invalid refresh request"))
+			c.Abort()
 			return
 		}
 
@@ -4,8 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/go-ozzo/ozzo-validation/is"
-	"greenbone-task/models"
-	"net/http"
+	"greenbone-task/services"
 )
 
 func PathIdValidator() gin.HandlerFunc {
@@ -14,7 +13,8 @@ func PathIdValidator() gin.HandlerFunc {
 		id := c.Param("id")
 		err := validation.Validate(id, is.MongoID)
 		if err != nil {
-			models.SendErrorResponse(c, http.StatusBadRequest, "invalid id: "+id)
+			_ = c.Error(services.ValidationError(services.CodeValidationFailed, err, "invalid id: %s", id))
+			c.Abort()
 			return
 		}
 
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS tokens;
+DROP TABLE IF EXISTS employee_computers;
+DROP TABLE IF EXISTS computers;
+DROP TABLE IF EXISTS employees;
@@ -0,0 +1,67 @@
+-- Initial schema. Tables use IF NOT EXISTS so that databases previously
+-- created through gorm's AutoMigrate can be adopted by the migrator.
+
+CREATE TABLE IF NOT EXISTS employees (
+    id           BIGSERIAL PRIMARY KEY,
+    created_at   TIMESTAMPTZ,
+    updated_at   TIMESTAMPTZ,
+    deleted_at   TIMESTAMPTZ,
+    first_name   TEXT NOT NULL,
+    last_name    TEXT NOT NULL,
+    email        TEXT NOT NULL,
+    abbreviation TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_employees_email ON employees (email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_employees_abbreviation ON employees (abbreviation);
+CREATE INDEX IF NOT EXISTS idx_employees_deleted_at ON employees (deleted_at);
+
+CREATE TABLE IF NOT EXISTS computers (
+    id              BIGSERIAL PRIMARY KEY,
+    created_at      TIMESTAMPTZ,
+    updated_at      TIMESTAMPTZ,
+    deleted_at      TIMESTAMPTZ,
+    mac_address     TEXT NOT NULL,
+    computer_name   TEXT NOT NULL,
+    ip_address      TEXT NOT NULL,
+    employee_abbrev TEXT,
+    description     TEXT
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_computers_mac_address ON computers (mac_address);
+CREATE INDEX IF NOT EXISTS idx_computers_employee_abbrev ON computers (employee_abbrev);
+CREATE INDEX IF NOT EXISTS idx_computers_deleted_at ON computers (deleted_at);
+
+CREATE TABLE IF NOT EXISTS employee_computers (
+    employee_id BIGINT NOT NULL,
+    computer_id BIGINT NOT NULL,
+    PRIMARY KEY (employee_id, computer_id)
+);
+
+-- A computer is assigned to at most one employee at a time.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_employee_computers_computer_id ON employee_computers (computer_id);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_employee_computers_employee') THEN
+        ALTER TABLE employee_computers
+            ADD CONSTRAINT fk_employee_computers_employee
+            FOREIGN KEY (employee_id) REFERENCES employees (id) ON DELETE CASCADE;
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_employee_computers_computer') THEN
+        ALTER TABLE employee_computers
+            ADD CONSTRAINT fk_employee_computers_computer
+            FOREIGN KEY (computer_id) REFERENCES computers (id) ON DELETE CASCADE;
+    END IF;
+END
+$$;
+
+CREATE TABLE IF NOT EXISTS tokens (
+    id          BIGINT PRIMARY KEY,
+    token       TEXT NOT NULL,
+    type        TEXT NOT NULL,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    blacklisted BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX IF NOT EXISTS idx_tokens_expires_at ON tokens (expires_at);
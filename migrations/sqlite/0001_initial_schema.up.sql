@@ -0,0 +1,51 @@
+-- Initial schema, kept in step with postgres/0001_initial_schema.up.sql.
+
+CREATE TABLE IF NOT EXISTS employees (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at   DATETIME,
+    updated_at   DATETIME,
+    deleted_at   DATETIME,
+    first_name   TEXT NOT NULL,
+    last_name    TEXT NOT NULL,
+    email        TEXT NOT NULL,
+    abbreviation TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_employees_email ON employees (email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_employees_abbreviation ON employees (abbreviation);
+CREATE INDEX IF NOT EXISTS idx_employees_deleted_at ON employees (deleted_at);
+
+CREATE TABLE IF NOT EXISTS computers (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at      DATETIME,
+    updated_at      DATETIME,
+    deleted_at      DATETIME,
+    mac_address     TEXT NOT NULL,
+    computer_name   TEXT NOT NULL,
+    ip_address      TEXT NOT NULL,
+    employee_abbrev TEXT,
+    description     TEXT
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_computers_mac_address ON computers (mac_address);
+CREATE INDEX IF NOT EXISTS idx_computers_employee_abbrev ON computers (employee_abbrev);
+CREATE INDEX IF NOT EXISTS idx_computers_deleted_at ON computers (deleted_at);
+
+CREATE TABLE IF NOT EXISTS employee_computers (
+    employee_id INTEGER NOT NULL REFERENCES employees (id) ON DELETE CASCADE,
+    computer_id INTEGER NOT NULL REFERENCES computers (id) ON DELETE CASCADE,
+    PRIMARY KEY (employee_id, computer_id)
+);
+
+-- A computer is assigned to at most one employee at a time.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_employee_computers_computer_id ON employee_computers (computer_id);
+
+CREATE TABLE IF NOT EXISTS tokens (
+    id          INTEGER PRIMARY KEY,
+    token       TEXT NOT NULL,
+    type        TEXT NOT NULL,
+    expires_at  DATETIME NOT NULL,
+    blacklisted BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX IF NOT EXISTS idx_tokens_expires_at ON tokens (expires_at);
@@ -0,0 +1,20 @@
+-- Assignment history, kept in step with postgres/0002_assignment_history.up.sql.
+-- Every row is a period in which a computer was assigned to an employee; the
+-- current assignment has no unassigned_at.
+
+CREATE TABLE IF NOT EXISTS assignment_history (
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    computer_id   INTEGER NOT NULL,
+    employee_id   INTEGER NOT NULL,
+    assigned_at   DATETIME NOT NULL,
+    unassigned_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_assignment_history_computer_id ON assignment_history (computer_id);
+CREATE INDEX IF NOT EXISTS idx_assignment_history_employee_id ON assignment_history (employee_id);
+
+-- The existing assignments start when their computer was last updated.
+INSERT INTO assignment_history (computer_id, employee_id, assigned_at)
+SELECT employee_computers.computer_id, employee_computers.employee_id, COALESCE(computers.updated_at, CURRENT_TIMESTAMP)
+FROM employee_computers
+JOIN computers ON computers.id = employee_computers.computer_id;
@@ -1,35 +1,170 @@
 package models
 
 import (
+	"fmt"
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/go-ozzo/ozzo-validation/is"
+	"greenbone-task/certs"
+	"greenbone-task/constants"
+	"greenbone-task/logger"
+	"greenbone-task/ratelimit"
+	"greenbone-task/tracing"
+	"net/url"
+	"strings"
+	"time"
 )
 
+// EnvConfig is the configuration of the service. Fields tagged reload are
+// applied again when the configuration is reloaded, the others only on a
+// restart. Fields tagged secret are never shown.
 type EnvConfig struct {
-	DBHost                     string `mapstructure:"POSTGRES_HOST"`
-	DBUserName                 string `mapstructure:"POSTGRES_USER"`
-	DBUserPassword             string `mapstructure:"POSTGRES_PASSWORD"`
-	DBName                     string `mapstructure:"POSTGRES_DB"`
-	DBPort                     string `mapstructure:"POSTGRES_PORT"`
-	ServerHost                 string `mapstructure:"SERVER_HOST"`
-	ServerPort                 string `mapstructure:"SERVER_PORT"`
-	UseRedis                   bool   `mapstructure:"USE_REDIS"`
-	RedisDefaultAddr           string `mapstructure:"REDIS_DEFAULT_ADDR"`
-	RedisPassword              string `mapstructure:"REDIS_PASSWORD"`
-	JWTSecretKey               string `mapstructure:"JWT_SECRET"`
-	JWTAccessExpirationMinutes int    `mapstructure:"JWT_ACCESS_EXPIRATION_MINUTES"`
-	JWTRefreshExpirationDays   int    `mapstructure:"JWT_REFRESH_EXPIRATION_DAYS"`
-	Mode                       string `mapstructure:"MODE"`
+	DBDriver                   string        `mapstructure:"DB_DRIVER"`
+	SQLitePath                 string        `mapstructure:"SQLITE_PATH"`
+	DBHost                     string        `mapstructure:"POSTGRES_HOST"`
+	DBUserName                 string        `mapstructure:"POSTGRES_USER"`
+	DBUserPassword             string        `mapstructure:"POSTGRES_PASSWORD" secret:"true"`
+	DBName                     string        `mapstructure:"POSTGRES_DB"`
+	DBPort                     string        `mapstructure:"POSTGRES_PORT"`
+	DBAutoMigrate              bool          `mapstructure:"DB_AUTO_MIGRATE"`
+	DBMaxOpenConns             int           `mapstructure:"DB_MAX_OPEN_CONNS"`
+	DBMaxIdleConns             int           `mapstructure:"DB_MAX_IDLE_CONNS"`
+	DBConnMaxLifetime          time.Duration `mapstructure:"DB_CONN_MAX_LIFETIME"`
+	StartupRetries             int           `mapstructure:"STARTUP_RETRIES"`
+	HealthCheckNotification    bool          `mapstructure:"HEALTH_CHECK_NOTIFICATION"`
+	DBQueryTimeout             time.Duration `mapstructure:"DB_QUERY_TIMEOUT"`
+	RedisTimeout               time.Duration `mapstructure:"REDIS_TIMEOUT"`
+	NotificationTimeout        time.Duration `mapstructure:"NOTIFICATION_TIMEOUT" reload:"true"`
+	NotificationURL            string        `mapstructure:"NOTIFICATION_URL" reload:"true"`
+	NotificationQueueSize      int           `mapstructure:"NOTIFICATION_QUEUE_SIZE"`
+	EventWorkers               int           `mapstructure:"EVENT_WORKERS"`
+	EventQueueSize             int           `mapstructure:"EVENT_QUEUE_SIZE"`
+	ComputerQuota              int           `mapstructure:"COMPUTER_QUOTA" reload:"true"`
+	CacheTTL                   time.Duration `mapstructure:"CACHE_TTL"`
+	ComputerCacheTTL           time.Duration `mapstructure:"COMPUTER_CACHE_TTL"`
+	LocalCacheSize             int           `mapstructure:"LOCAL_CACHE_SIZE"`
+	LocalCacheTTL              time.Duration `mapstructure:"LOCAL_CACHE_TTL"`
+	TokenCleanupInterval       time.Duration `mapstructure:"TOKEN_CLEANUP_INTERVAL"`
+	RateLimitEnabled           bool          `mapstructure:"RATE_LIMIT_ENABLED"`
+	RateLimitIP                string        `mapstructure:"RATE_LIMIT_IP" reload:"true"`
+	RateLimitUser              string        `mapstructure:"RATE_LIMIT_USER" reload:"true"`
+	RateLimitAPIKey            string        `mapstructure:"RATE_LIMIT_API_KEY" reload:"true"`
+	RateLimitRoutes            string        `mapstructure:"RATE_LIMIT_ROUTES" reload:"true"`
+	CORSAllowedOrigins         string        `mapstructure:"CORS_ALLOWED_ORIGINS" reload:"true"`
+	CORSAllowCredentials       bool          `mapstructure:"CORS_ALLOW_CREDENTIALS" reload:"true"`
+	CORSMaxAge                 time.Duration `mapstructure:"CORS_MAX_AGE" reload:"true"`
+	HSTSMaxAge                 time.Duration `mapstructure:"HSTS_MAX_AGE"`
+	TLSCertFile                string        `mapstructure:"TLS_CERT_FILE"`
+	TLSKeyFile                 string        `mapstructure:"TLS_KEY_FILE"`
+	TLSClientCAFile            string        `mapstructure:"TLS_CLIENT_CA_FILE"`
+	TLSClientAuth              string        `mapstructure:"TLS_CLIENT_AUTH"`
+	TLSClientRoles             string        `mapstructure:"TLS_CLIENT_ROLES"`
+	TracingExporter            string        `mapstructure:"TRACING_EXPORTER"`
+	TracingFile                string        `mapstructure:"TRACING_FILE"`
+	TracingOTLPEndpoint        string        `mapstructure:"TRACING_OTLP_ENDPOINT"`
+	TracingOTLPInsecure        bool          `mapstructure:"TRACING_OTLP_INSECURE"`
+	TracingSampleRatio         float64       `mapstructure:"TRACING_SAMPLE_RATIO"`
+	LogLevel                   string        `mapstructure:"LOG_LEVEL" reload:"true"`
+	LogFormat                  string        `mapstructure:"LOG_FORMAT"`
+	LogFile                    string        `mapstructure:"LOG_FILE"`
+	LogMaxSizeMB               int           `mapstructure:"LOG_MAX_SIZE_MB"`
+	LogMaxBackups              int           `mapstructure:"LOG_MAX_BACKUPS"`
+	LogMaxAgeDays              int           `mapstructure:"LOG_MAX_AGE_DAYS"`
+	LogCompress                bool          `mapstructure:"LOG_COMPRESS"`
+	LogRotateInterval          time.Duration `mapstructure:"LOG_ROTATE_INTERVAL"`
+	LogSyslog                  bool          `mapstructure:"LOG_SYSLOG"`
+	LogSyslogNetwork           string        `mapstructure:"LOG_SYSLOG_NETWORK"`
+	LogSyslogAddress           string        `mapstructure:"LOG_SYSLOG_ADDRESS"`
+	WebhookTimeout             time.Duration `mapstructure:"WEBHOOK_TIMEOUT" reload:"true"`
+	WebhookMaxAttempts         int           `mapstructure:"WEBHOOK_MAX_ATTEMPTS" reload:"true"`
+	WebhookRetryBackoff        time.Duration `mapstructure:"WEBHOOK_RETRY_BACKOFF" reload:"true"`
+	WebhookMaxBackoff          time.Duration `mapstructure:"WEBHOOK_MAX_BACKOFF" reload:"true"`
+	WebhookPollInterval        time.Duration `mapstructure:"WEBHOOK_POLL_INTERVAL"`
+	GraphQLMaxObjects          int           `mapstructure:"GRAPHQL_MAX_OBJECTS"`
+	ServerHost                 string        `mapstructure:"SERVER_HOST"`
+	ServerPort                 string        `mapstructure:"SERVER_PORT"`
+	GRPCPort                   string        `mapstructure:"GRPC_PORT"`
+	ServerReadTimeout          time.Duration `mapstructure:"SERVER_READ_TIMEOUT"`
+	ServerReadHeaderTimeout    time.Duration `mapstructure:"SERVER_READ_HEADER_TIMEOUT"`
+	ServerWriteTimeout         time.Duration `mapstructure:"SERVER_WRITE_TIMEOUT"`
+	ServerIdleTimeout          time.Duration `mapstructure:"SERVER_IDLE_TIMEOUT"`
+	ShutdownTimeout            time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
+	UseRedis                   bool          `mapstructure:"USE_REDIS"`
+	RedisDefaultAddr           string        `mapstructure:"REDIS_DEFAULT_ADDR"`
+	RedisPassword              string        `mapstructure:"REDIS_PASSWORD" secret:"true"`
+	RedisPoolSize              int           `mapstructure:"REDIS_POOL_SIZE"`
+	JWTSecretKey               string        `mapstructure:"JWT_SECRET" secret:"true"`
+	JWTAccessExpirationMinutes int           `mapstructure:"JWT_ACCESS_EXPIRATION_MINUTES"`
+	JWTRefreshExpirationDays   int           `mapstructure:"JWT_REFRESH_EXPIRATION_DAYS"`
+	Mode                       string        `mapstructure:"MODE"`
 }
 
 func (config *EnvConfig) Validate() error {
+	usePostgres := config.DBDriver == constants.DriverPostgres
+	useSQLite := config.DBDriver == constants.DriverSQLite
+
 	return validation.ValidateStruct(config,
+		validation.Field(&config.DBDriver, validation.Required, validation.In(constants.DriverPostgres, constants.DriverSQLite)),
+		validation.Field(&config.SQLitePath, requiredWhen(useSQLite)),
 		validation.Field(&config.DBPort, is.Port),
-		validation.Field(&config.DBHost, validation.Required),
-		validation.Field(&config.DBUserPassword, validation.Required),
-		validation.Field(&config.DBName, validation.Required),
+		validation.Field(&config.DBHost, requiredWhen(usePostgres)),
+		validation.Field(&config.DBUserPassword, requiredWhen(usePostgres)),
+		validation.Field(&config.DBName, requiredWhen(usePostgres)),
+		validation.Field(&config.StartupRetries, validation.Min(0)),
+		validation.Field(&config.DBMaxOpenConns, validation.Min(0)),
+		validation.Field(&config.DBMaxIdleConns, validation.Min(0)),
+		validation.Field(&config.DBConnMaxLifetime, validation.Min(time.Duration(0))),
+		validation.Field(&config.DBQueryTimeout, validation.Min(time.Duration(0))),
+		validation.Field(&config.RedisTimeout, validation.Min(time.Duration(0))),
+		validation.Field(&config.NotificationTimeout, validation.Min(time.Duration(0))),
+		validation.Field(&config.NotificationURL, is.URL),
+		validation.Field(&config.NotificationQueueSize, validation.Required, validation.Min(1)),
+		validation.Field(&config.EventWorkers, validation.Min(0)),
+		validation.Field(&config.EventQueueSize, validation.Min(0)),
+		validation.Field(&config.ComputerQuota, validation.Required, validation.Min(1)),
+		validation.Field(&config.CacheTTL, validation.Min(time.Duration(0))),
+		validation.Field(&config.ComputerCacheTTL, validation.Min(time.Duration(0))),
+		validation.Field(&config.LocalCacheSize, validation.Min(0)),
+		validation.Field(&config.LocalCacheTTL, validation.Min(time.Duration(0))),
+		validation.Field(&config.TokenCleanupInterval, validation.Min(time.Duration(0))),
+		validation.Field(&config.RateLimitIP, validation.By(validRateLimit)),
+		validation.Field(&config.RateLimitUser, validation.By(validRateLimit)),
+		validation.Field(&config.RateLimitAPIKey, validation.By(validRateLimit)),
+		validation.Field(&config.RateLimitRoutes, validation.By(validRouteRateLimits)),
+		validation.Field(&config.CORSAllowedOrigins, validation.By(validOrigins)),
+		validation.Field(&config.CORSMaxAge, validation.Min(time.Duration(0))),
+		validation.Field(&config.HSTSMaxAge, validation.Min(time.Duration(0))),
+		validation.Field(&config.TLSCertFile, requiredWhen(config.TLSKeyFile != "")),
+		validation.Field(&config.TLSKeyFile, requiredWhen(config.TLSCertFile != "")),
+		validation.Field(&config.TLSClientAuth, validation.In(certs.ClientAuthNone, certs.ClientAuthOptional, certs.ClientAuthRequire)),
+		validation.Field(&config.TLSClientCAFile, requiredWhen(config.TLSClientAuth == certs.ClientAuthOptional || config.TLSClientAuth == certs.ClientAuthRequire)),
+		validation.Field(&config.TLSClientRoles, validation.By(validClientRoles)),
+		validation.Field(&config.TracingExporter, validation.In(tracing.ExporterNone, tracing.ExporterOTLP, tracing.ExporterStdout, tracing.ExporterFile)),
+		validation.Field(&config.TracingFile, requiredWhen(config.TracingExporter == tracing.ExporterFile)),
+		validation.Field(&config.TracingSampleRatio, validation.Min(0.0), validation.Max(1.0)),
+		validation.Field(&config.LogLevel, validation.In("debug", "info", "warn", "error")),
+		validation.Field(&config.LogFormat, validation.In(logger.FormatJSON, logger.FormatConsole)),
+		validation.Field(&config.LogMaxSizeMB, validation.Min(0)),
+		validation.Field(&config.LogMaxBackups, validation.Min(0)),
+		validation.Field(&config.LogMaxAgeDays, validation.Min(0)),
+		validation.Field(&config.LogRotateInterval, validation.Min(time.Duration(0))),
+		validation.Field(&config.LogSyslogNetwork, validation.In("udp", "tcp")),
+		validation.Field(&config.LogSyslogAddress, requiredWhen(config.LogSyslogNetwork != "")),
+		validation.Field(&config.WebhookTimeout, validation.Min(time.Duration(0))),
+		validation.Field(&config.WebhookMaxAttempts, validation.Min(0)),
+		validation.Field(&config.WebhookRetryBackoff, validation.Min(time.Duration(0))),
+		validation.Field(&config.WebhookMaxBackoff, validation.Min(time.Duration(0))),
+		validation.Field(&config.WebhookPollInterval, validation.Min(time.Duration(0))),
+		validation.Field(&config.GraphQLMaxObjects, validation.Min(0)),
+		validation.Field(&config.ServerPort, is.Port),
+		validation.Field(&config.GRPCPort, is.Port),
+		validation.Field(&config.ServerReadTimeout, validation.Min(time.Duration(0))),
+		validation.Field(&config.ServerReadHeaderTimeout, validation.Min(time.Duration(0))),
+		validation.Field(&config.ServerWriteTimeout, validation.Min(time.Duration(0))),
+		validation.Field(&config.ServerIdleTimeout, validation.Min(time.Duration(0))),
+		validation.Field(&config.ShutdownTimeout, validation.Min(time.Duration(0))),
 		validation.Field(&config.UseRedis, validation.In(true, false)),
-		validation.Field(&config.RedisDefaultAddr),
+		validation.Field(&config.RedisDefaultAddr, requiredWhen(config.UseRedis)),
+		validation.Field(&config.RedisPoolSize, validation.Min(0)),
 
 		validation.Field(&config.JWTSecretKey, validation.Required),
 		validation.Field(&config.JWTAccessExpirationMinutes, validation.Required),
@@ -38,3 +173,93 @@ func (config *EnvConfig) Validate() error {
 		validation.Field(&config.Mode, validation.In("debug", "release")),
 	)
 }
+
+// RateLimitPolicy returns the rate limits configured in config.
+func (config *EnvConfig) RateLimitPolicy() (*ratelimit.Policy, error) {
+	var err error
+	policy := &ratelimit.Policy{}
+	if policy.IP, err = ratelimit.ParseLimit(config.RateLimitIP); err != nil {
+		return nil, err
+	}
+	if policy.User, err = ratelimit.ParseLimit(config.RateLimitUser); err != nil {
+		return nil, err
+	}
+	if policy.APIKey, err = ratelimit.ParseLimit(config.RateLimitAPIKey); err != nil {
+		return nil, err
+	}
+	if policy.Routes, err = ratelimit.ParseRoutes(config.RateLimitRoutes); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// CORSOrigins returns the origins allowed to make cross-origin requests.
+// "*" allows any origin.
+func (config *EnvConfig) CORSOrigins() []string {
+	var origins []string
+	for _, origin := range strings.Split(config.CORSAllowedOrigins, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, strings.TrimSuffix(origin, "/"))
+		}
+	}
+	return origins
+}
+
+func validOrigins(value interface{}) error {
+	config := EnvConfig{CORSAllowedOrigins: value.(string)}
+	for _, origin := range config.CORSOrigins() {
+		if origin == "*" {
+			continue
+		}
+		parsed, err := url.Parse(origin)
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" || parsed.Path != "" {
+			return fmt.Errorf("invalid origin %q, expected <scheme>://<host>[:<port>]", origin)
+		}
+	}
+	return nil
+}
+
+// ClientRoles returns the roles of the client certificates by common name,
+// read from comma separated "<common name>=<role>" pairs.
+func (config *EnvConfig) ClientRoles() (map[string]string, error) {
+	roles := make(map[string]string)
+	for _, entry := range strings.Split(config.TLSClientRoles, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		name, role, found := strings.Cut(entry, "=")
+		name, role = strings.TrimSpace(name), strings.TrimSpace(role)
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid client role %q, expected <common name>=<role>", entry)
+		}
+		if role != constants.RoleAdmin && role != constants.RoleReadOnly {
+			return nil, fmt.Errorf("unknown role %q for client %s", role, name)
+		}
+		roles[name] = role
+	}
+	return roles, nil
+}
+
+func validClientRoles(value interface{}) error {
+	config := EnvConfig{TLSClientRoles: value.(string)}
+	_, err := config.ClientRoles()
+	return err
+}
+
+func validRateLimit(value interface{}) error {
+	_, err := ratelimit.ParseLimit(value.(string))
+	return err
+}
+
+func validRouteRateLimits(value interface{}) error {
+	_, err := ratelimit.ParseRoutes(value.(string))
+	return err
+}
+
+// requiredWhen makes a field required only if the condition holds.
+func requiredWhen(condition bool) validation.Rule {
+	if condition {
+		return validation.Required
+	}
+	return validation.Skip
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// Assignment is a period in which a computer was assigned to an employee. The
+// current assignment of a computer has no UnassignedAt.
+type Assignment struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	ComputerID   uint       `json:"computer_id" gorm:"not null"`
+	EmployeeID   uint       `json:"employee_id" gorm:"not null"`
+	AssignedAt   time.Time  `json:"assigned_at" gorm:"not null"`
+	UnassignedAt *time.Time `json:"unassigned_at,omitempty"`
+}
+
+func (Assignment) TableName() string {
+	return "assignment_history"
+}
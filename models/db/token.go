@@ -23,6 +23,8 @@ type Token struct {
 	Type        string    `json:"type" bson:"type"`
 	ExpiresAt   time.Time `json:"expires_at" bson:"expires_at"`
 	Blacklisted bool      `json:"blacklisted" bson:"blacklisted"`
+	// Email is the email claim of a verified token; it is not stored.
+	Email string `json:"-" bson:"-" gorm:"-"`
 }
 
 func (model Token) GetResponseJson() gin.H {
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Event types sent to webhooks.
+const (
+	EventComputerCreated    = "computer.created"
+	EventComputerReassigned = "computer.reassigned"
+	EventComputerDeleted    = "computer.deleted"
+)
+
+// EventTypes are all event types a webhook can subscribe to.
+var EventTypes = []string{EventComputerCreated, EventComputerReassigned, EventComputerDeleted}
+
+// Statuses of a webhook delivery.
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryDead      = "dead"
+)
+
+// Webhook is a subscription of a URL to inventory events. The payloads are
+// signed with Secret.
+type Webhook struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	URL       string    `json:"url" gorm:"not null"`
+	Secret    string    `json:"-" gorm:"not null"`
+	// EventTypes is a comma separated list of the events the webhook
+	// receives; empty means all.
+	EventTypes string `json:"event_types"`
+	Active     bool   `json:"active" gorm:"not null"`
+}
+
+// Events returns the event types the webhook subscribed to; none means all.
+func (w Webhook) Events() []string {
+	if w.EventTypes == "" {
+		return []string{}
+	}
+	return strings.Split(w.EventTypes, ",")
+}
+
+// MarshalJSON writes the event types as a list, like they are requested.
+func (w Webhook) MarshalJSON() ([]byte, error) {
+	type webhook Webhook
+	return json.Marshal(struct {
+		webhook
+		EventTypes []string `json:"event_types"`
+	}{webhook(w), w.Events()})
+}
+
+// Subscribes reports whether the webhook receives events of eventType.
+func (w Webhook) Subscribes(eventType string) bool {
+	if !w.Active {
+		return false
+	}
+	if w.EventTypes == "" {
+		return true
+	}
+	for _, subscribed := range w.Events() {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one event to be sent to one webhook.
+type WebhookDelivery struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	WebhookID      uint       `json:"webhook_id" gorm:"not null"`
+	EventID        string     `json:"event_id" gorm:"not null"`
+	EventType      string     `json:"event_type" gorm:"not null"`
+	Payload        string     `json:"payload" gorm:"not null"`
+	Status         string     `json:"status" gorm:"not null"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	LastStatusCode int        `json:"last_status_code,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
+
+// WebhookAttempt is the log of one attempt to send a delivery.
+type WebhookAttempt struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	DeliveryID  uint      `json:"delivery_id" gorm:"not null"`
+	AttemptedAt time.Time `json:"attempted_at" gorm:"not null"`
+	// StatusCode is the HTTP status of the response, 0 if there was none.
+	StatusCode int    `json:"status_code"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms" gorm:"column:duration_ms"`
+}
+
+func (WebhookAttempt) TableName() string {
+	return "webhook_delivery_attempts"
+}
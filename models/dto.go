@@ -0,0 +1,76 @@
+package models
+
+import (
+	db "greenbone-task/models/db"
+	"time"
+)
+
+// ComputerDTO is a computer as returned by the /v2 API.
+type ComputerDTO struct {
+	ID             uint      `json:"id"`
+	MacAddress     string    `json:"mac_address"`
+	ComputerName   string    `json:"computer_name"`
+	IPAddress      string    `json:"ip_address"`
+	EmployeeAbbrev string    `json:"employee_abbrev,omitempty"`
+	Description    string    `json:"description,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func NewComputerDTO(computer db.Computer) ComputerDTO {
+	return ComputerDTO{
+		ID:             computer.ID,
+		MacAddress:     computer.MacAddress,
+		ComputerName:   computer.ComputerName,
+		IPAddress:      computer.IPAddress,
+		EmployeeAbbrev: computer.EmployeeAbbrev,
+		Description:    computer.Description,
+		CreatedAt:      computer.CreatedAt,
+		UpdatedAt:      computer.UpdatedAt,
+	}
+}
+
+func NewComputerDTOs(computers []db.Computer) []ComputerDTO {
+	dtos := make([]ComputerDTO, len(computers))
+	for i, computer := range computers {
+		dtos[i] = NewComputerDTO(computer)
+	}
+	return dtos
+}
+
+// EmployeeDTO is an employee as returned by the /v2 API.
+type EmployeeDTO struct {
+	ID           uint      `json:"id"`
+	FirstName    string    `json:"first_name"`
+	LastName     string    `json:"last_name"`
+	Email        string    `json:"email"`
+	Abbreviation string    `json:"abbreviation"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func NewEmployeeDTO(employee db.Employee) EmployeeDTO {
+	return EmployeeDTO{
+		ID:           employee.ID,
+		FirstName:    employee.FirstName,
+		LastName:     employee.LastName,
+		Email:        employee.Email,
+		Abbreviation: employee.Abbreviation,
+		CreatedAt:    employee.CreatedAt,
+		UpdatedAt:    employee.UpdatedAt,
+	}
+}
+
+// PageDTO is one page of a list returned by the /v2 API. Page starts at 1 and
+// Total is the number of items of all pages.
+type PageDTO[T any] struct {
+	Items    []T   `json:"items"`
+	Page     int   `json:"page"`
+	PageSize int   `json:"page_size"`
+	Total    int64 `json:"total"`
+}
+
+// ImportDTO is the result of a /v2 computer import.
+type ImportDTO struct {
+	ComputerIDs []uint `json:"computer_ids"`
+}
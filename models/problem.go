@@ -0,0 +1,49 @@
+package models
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// ProblemContentType is the media type of Problem responses (RFC 7807).
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem detail. Code is a stable, machine readable
+// error code; Type is derived from it.
+type Problem struct {
+	Type      string            `json:"type"`
+	Title     string            `json:"title"`
+	Status    int               `json:"status"`
+	Detail    string            `json:"detail,omitempty"`
+	Instance  string            `json:"instance,omitempty"`
+	Code      string            `json:"code"`
+	RequestID string            `json:"request_id,omitempty"`
+	Errors    map[string]string `json:"errors,omitempty"`
+}
+
+// NewProblem returns a problem for code with the standard title of status.
+func NewProblem(status int, code string, detail string) *Problem {
+	return &Problem{
+		Type:   "/problems/" + code,
+		Title:  statusTitle(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// SendProblem aborts the request with problem as application/problem+json.
+func SendProblem(c *gin.Context, problem *Problem) {
+	if problem.Instance == "" {
+		problem.Instance = c.Request.URL.Path
+	}
+	c.Header("Content-Type", ProblemContentType)
+	c.AbortWithStatusJSON(problem.Status, problem)
+}
+
+func statusTitle(status int) string {
+	if title := http.StatusText(status); title != "" {
+		return title
+	}
+	return "Error"
+}
@@ -1,10 +1,8 @@
 package models
 
 import (
-	"fmt"
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/go-ozzo/ozzo-validation/is"
-	"github.com/pkg/errors"
 	db "greenbone-task/models/db"
 	"regexp"
 )
@@ -57,30 +55,52 @@ type CPaymentResponse struct {
 
 func ValidateComputerRequest(computerReq db.Computer) error {
 	// Check if required fields are present
-	if computerReq.MacAddress == "" {
-		return errors.New("mac_address is required")
-	}
-	if computerReq.ComputerName == "" {
-		return errors.New("computer_name is required")
-	}
-	if computerReq.IPAddress == "" {
-		return errors.New("ip_address is required")
-	}
-	return nil
+	return validation.Errors{
+		"mac_address":   validation.Validate(computerReq.MacAddress, validation.Required),
+		"computer_name": validation.Validate(computerReq.ComputerName, validation.Required),
+		"ip_address":    validation.Validate(computerReq.IPAddress, validation.Required),
+	}.Filter()
 }
 
 func ValidateEmployeeRequest(req EmployeeRequest) error {
-	if req.FirstName == "" {
-		return fmt.Errorf("missing required field 'first_name'")
-	}
-	if req.LastName == "" {
-		return fmt.Errorf("missing required field 'last_name'")
-	}
-	if req.Email == "" {
-		return fmt.Errorf("missing required field 'email'")
-	}
-	if req.Abbreviation == "" {
-		return fmt.Errorf("missing required field 'abbreviation'")
+	return validation.Errors{
+		"first_name":   validation.Validate(req.FirstName, validation.Required),
+		"last_name":    validation.Validate(req.LastName, validation.Required),
+		"email":        validation.Validate(req.Email, validation.Required),
+		"abbreviation": validation.Validate(req.Abbreviation, validation.Required),
+	}.Filter()
+}
+
+type LogLevelRequest struct {
+	Level string `json:"level"`
+}
+
+func (a LogLevelRequest) Validate() error {
+	return validation.ValidateStruct(&a,
+		validation.Field(&a.Level, validation.Required, validation.In("debug", "info", "warn", "error")),
+	)
+}
+
+// WebhookRequest subscribes a URL to inventory events.
+type WebhookRequest struct {
+	URL string `json:"url"`
+	// EventTypes are the events to send; empty sends all.
+	EventTypes []string `json:"event_types,omitempty"`
+	// Secret signs the payloads. One is generated if it is empty.
+	Secret string `json:"secret,omitempty"`
+	// Active defaults to true.
+	Active *bool `json:"active,omitempty"`
+}
+
+func (a WebhookRequest) Validate() error {
+	eventTypes := make([]interface{}, len(db.EventTypes))
+	for i, eventType := range db.EventTypes {
+		eventTypes[i] = eventType
 	}
-	return nil
+	return validation.ValidateStruct(&a,
+		validation.Field(&a.URL, validation.Required, is.URL,
+			validation.Match(regexp.MustCompile("^https?://")).Error("must be an http or https URL")),
+		validation.Field(&a.EventTypes, validation.Each(validation.In(eventTypes...))),
+		validation.Field(&a.Secret, validation.Length(16, 0)),
+	)
 }
@@ -26,15 +26,6 @@ func SendResponseData(c *gin.Context, data gin.H) {
 	response.SendResponse(c)
 }
 
-func SendErrorResponse(c *gin.Context, status int, message string) {
-	response := &Response{
-		StatusCode: status,
-		Success:    false,
-		Message:    message,
-	}
-	response.SendResponse(c)
-}
-
 type ComputerRequestResponse struct {
 	EmployeeID           uint   `json:"employee_id"`
 	EmployeeAbbreviation string `json:"employee_abbreviation"`
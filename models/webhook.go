@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// WebhookEvent is the body sent to webhooks. A replayed delivery sends the
+// same event with the same ID again, so receivers can drop duplicates.
+type WebhookEvent struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+// ComputerEventData is the data of the computer events.
+type ComputerEventData struct {
+	Computer ComputerDTO `json:"computer"`
+	// PreviousEmployeeAbbrev is the employee a reassigned computer was
+	// assigned to before.
+	PreviousEmployeeAbbrev string `json:"previous_employee_abbrev,omitempty"`
+}
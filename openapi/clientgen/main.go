@@ -0,0 +1,33 @@
+// Command clientgen generates the client package from the OpenAPI document.
+// It is run by go generate ./openapi.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"greenbone-task/openapi"
+	"os"
+)
+
+func main() {
+	out := flag.String("out", "client.gen.go", "file to write the client to")
+	pkg := flag.String("package", "client", "package of the client")
+	flag.Parse()
+
+	if err := generate(*out, *pkg); err != nil {
+		fmt.Fprintln(os.Stderr, "clientgen:", err)
+		os.Exit(1)
+	}
+}
+
+func generate(out string, pkg string) error {
+	doc, err := openapi.Load(openapi.Spec)
+	if err != nil {
+		return err
+	}
+	source, err := openapi.GenerateClient(doc, pkg)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(out, source, 0644)
+}
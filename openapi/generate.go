@@ -0,0 +1,440 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// initialisms are written in upper case in Go names.
+var initialisms = map[string]bool{"api": true, "http": true, "id": true, "ip": true, "json": true, "ttl": true, "url": true}
+
+// GenerateClient returns the source of a client package named pkg with a type
+// for every schema of doc and a Client method for every operation. The
+// package must also contain the hand-written Client, do and doText.
+func GenerateClient(doc *Document, pkg string) ([]byte, error) {
+	g := &generator{doc: doc, imports: map[string]bool{}}
+
+	var types bytes.Buffer
+	names := make([]string, 0, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := g.writeType(&types, name, doc.Components.Schemas[name]); err != nil {
+			return nil, fmt.Errorf("schema %s: %w", name, err)
+		}
+	}
+
+	routes, err := doc.Routes()
+	if err != nil {
+		return nil, err
+	}
+	var methods bytes.Buffer
+	for _, route := range routes {
+		if err := g.writeMethod(&methods, route); err != nil {
+			return nil, fmt.Errorf("%s %s: %w", route.Method, route.Path, err)
+		}
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintln(&out, "// Code generated by go generate ./openapi from openapi/openapi.yaml. DO NOT EDIT.")
+	fmt.Fprintln(&out)
+	fmt.Fprintf(&out, "package %s\n\n", pkg)
+	imports := make([]string, 0, len(g.imports))
+	for path := range g.imports {
+		imports = append(imports, strconv.Quote(path))
+	}
+	sort.Strings(imports)
+	fmt.Fprintf(&out, "import (\n%s\n)\n\n", strings.Join(imports, "\n"))
+	out.Write(types.Bytes())
+	out.Write(methods.Bytes())
+
+	source, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated client is not valid Go: %w", err)
+	}
+	return source, nil
+}
+
+type generator struct {
+	doc     *Document
+	imports map[string]bool
+}
+
+func (g *generator) writeType(out *bytes.Buffer, name string, schema *Schema) error {
+	if schema.Description != "" {
+		writeComment(out, "", name+" is "+lowerFirst(schema.Description))
+	} else {
+		writeComment(out, "", name+" is the "+name+" schema of the API.")
+	}
+	if schema.Type != "object" || len(schema.Properties) == 0 {
+		goType, err := g.goType(schema)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "type %s %s\n\n", name, goType)
+		return nil
+	}
+
+	required := map[string]bool{}
+	for _, property := range schema.Required {
+		required[property] = true
+	}
+	fmt.Fprintf(out, "type %s struct {\n", name)
+	for _, property := range schema.Properties {
+		goType, err := g.goType(property.Schema)
+		if err != nil {
+			return fmt.Errorf("property %s: %w", property.Name, err)
+		}
+		tag := property.Name
+		if !required[property.Name] {
+			tag += ",omitempty"
+		}
+		if property.Schema.Description != "" {
+			writeComment(out, "\t", property.Schema.Description)
+		}
+		fmt.Fprintf(out, "\t%s %s `json:%q`\n", goName(property.Name), goType, tag)
+	}
+	fmt.Fprint(out, "}\n\n")
+	return nil
+}
+
+// goType returns the Go type of a schema.
+func (g *generator) goType(schema *Schema) (string, error) {
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		if _, ok := g.doc.Components.Schemas[name]; !ok {
+			return "", fmt.Errorf("unknown schema %s", schema.Ref)
+		}
+		return name, nil
+	}
+
+	var goType string
+	switch schema.Type {
+	case "string":
+		goType = "string"
+		if schema.Format == "date-time" {
+			g.imports["time"] = true
+			goType = "time.Time"
+		}
+	case "integer":
+		goType = "int64"
+		if schema.Format == "int32" {
+			goType = "int32"
+		}
+	case "number":
+		goType = "float64"
+	case "boolean":
+		goType = "bool"
+	case "array":
+		if schema.Items == nil {
+			return "", fmt.Errorf("array without items")
+		}
+		items, err := g.goType(schema.Items)
+		if err != nil {
+			return "", err
+		}
+		goType = "[]" + items
+	case "object":
+		switch {
+		case schema.AdditionalProperties != nil:
+			values, err := g.goType(schema.AdditionalProperties)
+			if err != nil {
+				return "", err
+			}
+			goType = "map[string]" + values
+		case schema.AnyAdditionalProperties:
+			goType = "map[string]any"
+		default:
+			return "", fmt.Errorf("inline objects need to be component schemas")
+		}
+	default:
+		return "", fmt.Errorf("unsupported type %q", schema.Type)
+	}
+	if schema.Nullable {
+		goType = "*" + goType
+	}
+	return goType, nil
+}
+
+func (g *generator) writeMethod(out *bytes.Buffer, route Route) error {
+	operation := route.Operation
+	if operation.OperationID == "" {
+		return fmt.Errorf("operationId is missing")
+	}
+	name := goName(operation.OperationID)
+
+	// the arguments are the path parameters in order, then the query
+	// parameters, then the body
+	arguments := []string{"ctx context.Context"}
+	g.imports["context"] = true
+	g.imports["net/http"] = true
+	path, err := g.pathExpression(route, &arguments)
+	if err != nil {
+		return err
+	}
+	query, err := g.queryStatements(route, &arguments)
+	if err != nil {
+		return err
+	}
+	if query != "" {
+		path = "withQuery(" + path + ", values)"
+	}
+	body := "nil"
+	if operation.RequestBody != nil {
+		media, ok := operation.RequestBody.Content["application/json"]
+		if !ok {
+			return fmt.Errorf("only JSON request bodies are supported")
+		}
+		bodyType, err := g.goType(media.Schema)
+		if err != nil {
+			return err
+		}
+		arguments = append(arguments, "body "+bodyType)
+		body = "body"
+	}
+
+	result, contentType, statuses, err := g.result(operation)
+	if err != nil {
+		return err
+	}
+
+	comment := fmt.Sprintf("%s sends %s %s", name, route.Method, route.Path)
+	if operation.Summary != "" {
+		comment += ": " + lowerFirst(strings.TrimSuffix(operation.Summary, "."))
+	}
+	writeComment(out, "", comment+".")
+	if operation.Description != "" {
+		writeComment(out, "", "")
+		writeComment(out, "", operation.Description)
+	}
+	if query != "" {
+		writeComment(out, "", "")
+		writeComment(out, "", "Query parameters with the zero value are not sent.")
+	}
+
+	authenticated := strconv.FormatBool(len(operation.Security) > 0)
+	method := "http.Method" + string(route.Method[0]) + strings.ToLower(route.Method[1:])
+	switch {
+	case result == "" && contentType == "":
+		fmt.Fprintf(out, "func (c *Client) %s(%s) error {\n%s", name, strings.Join(arguments, ", "), query)
+		fmt.Fprintf(out, "\treturn c.do(ctx, %s, %s, %s, %s, nil, %s)\n}\n\n", method, path, authenticated, body, statuses)
+	case result == "":
+		fmt.Fprintf(out, "func (c *Client) %s(%s) (string, error) {\n%s", name, strings.Join(arguments, ", "), query)
+		fmt.Fprintf(out, "\treturn c.doText(ctx, %s, %s, %s, %q, %s)\n}\n\n", method, path, authenticated, contentType, statuses)
+	default:
+		fmt.Fprintf(out, "func (c *Client) %s(%s) (*%s, error) {\n%s", name, strings.Join(arguments, ", "), result, query)
+		fmt.Fprintf(out, "\tvar response %s\n", result)
+		fmt.Fprintf(out, "\tif err := c.do(ctx, %s, %s, %s, %s, &response, %s); err != nil {\n", method, path, authenticated, body, statuses)
+		fmt.Fprint(out, "\t\treturn nil, err\n\t}\n\treturn &response, nil\n}\n\n")
+	}
+	return nil
+}
+
+// queryStatements returns the statements collecting the query parameters of
+// route into values, adding them to arguments.
+func (g *generator) queryStatements(route Route, arguments *[]string) (string, error) {
+	var statements strings.Builder
+	for _, parameter := range route.Parameters {
+		if parameter.In != "query" {
+			continue
+		}
+		argument := lowerFirst(goName(parameter.Name))
+		argumentType, err := g.goType(parameter.Schema)
+		if err != nil {
+			return "", err
+		}
+		*arguments = append(*arguments, argument+" "+argumentType)
+
+		if statements.Len() == 0 {
+			g.imports["net/url"] = true
+			statements.WriteString("\tvalues := url.Values{}\n")
+		}
+		switch argumentType {
+		case "string":
+			fmt.Fprintf(&statements, "\tif %s != \"\" {\n\t\tvalues.Set(%q, %s)\n\t}\n", argument, parameter.Name, argument)
+		case "int64":
+			g.imports["strconv"] = true
+			fmt.Fprintf(&statements, "\tif %s != 0 {\n\t\tvalues.Set(%q, strconv.FormatInt(%s, 10))\n\t}\n", argument, parameter.Name, argument)
+		default:
+			return "", fmt.Errorf("query parameter %s: unsupported type %s", parameter.Name, argumentType)
+		}
+	}
+	return statements.String(), nil
+}
+
+// pathExpression returns the Go expression of the path of route, adding the
+// path parameters to arguments.
+func (g *generator) pathExpression(route Route, arguments *[]string) (string, error) {
+	parameters := map[string]*Parameter{}
+	for _, parameter := range route.Parameters {
+		switch parameter.In {
+		case "path":
+			parameters[parameter.Name] = parameter
+		case "query":
+		default:
+			return "", fmt.Errorf("parameter %s: only path and query parameters are supported", parameter.Name)
+		}
+	}
+
+	var parts []string
+	literal := ""
+	for _, segment := range strings.Split(route.Path, "/")[1:] {
+		literal += "/"
+		if !strings.HasPrefix(segment, "{") {
+			literal += segment
+			continue
+		}
+		parameter, ok := parameters[strings.Trim(segment, "{}")]
+		if !ok {
+			return "", fmt.Errorf("path parameter %s is not declared", segment)
+		}
+		argument := lowerFirst(goName(parameter.Name))
+		argumentType, err := g.goType(parameter.Schema)
+		if err != nil {
+			return "", err
+		}
+		*arguments = append(*arguments, argument+" "+argumentType)
+
+		parts = append(parts, strconv.Quote(literal))
+		literal = ""
+		switch argumentType {
+		case "string":
+			g.imports["net/url"] = true
+			parts = append(parts, "url.PathEscape("+argument+")")
+		case "int64":
+			parts = append(parts, "strconv.FormatInt("+argument+", 10)")
+			g.imports["strconv"] = true
+		default:
+			return "", fmt.Errorf("path parameter %s: unsupported type %s", parameter.Name, argumentType)
+		}
+	}
+	if literal != "" {
+		parts = append(parts, strconv.Quote(literal))
+	}
+	return strings.Join(parts, " + "), nil
+}
+
+// result returns the type of the successful responses of an operation, or ""
+// and the content type for text, or "" and "" if they have no content, and the
+// expression listing their statuses. Other responses with the schema of the
+// first successful one, e.g. the 503 of a readiness probe, are successful too.
+func (g *generator) result(operation *Operation) (string, string, string, error) {
+	codes := make([]string, 0, len(operation.Responses))
+	for code := range operation.Responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	var result, contentType string
+	var statuses []string
+	for _, code := range codes {
+		response, err := g.doc.Response(operation.Responses[code])
+		if err != nil {
+			return "", "", "", err
+		}
+		if len(response.Content) == 0 && strings.HasPrefix(code, "2") && contentType == "" {
+			statuses = append(statuses, code)
+			continue
+		}
+		for mediaType, media := range response.Content {
+			var schemaType string
+			if media.Schema.Type == "string" {
+				schemaType = ""
+			} else if schemaType, err = g.goType(media.Schema); err != nil {
+				return "", "", "", err
+			}
+			success := strings.HasPrefix(code, "2")
+			if contentType == "" && success {
+				result, contentType = schemaType, mediaType
+			} else if contentType == "" || mediaType != contentType || schemaType != result {
+				continue
+			}
+			status, err := strconv.Atoi(code)
+			if err != nil {
+				return "", "", "", fmt.Errorf("invalid status %q", code)
+			}
+			statuses = append(statuses, strconv.Itoa(status))
+		}
+	}
+	if len(statuses) == 0 {
+		return "", "", "", fmt.Errorf("no successful response")
+	}
+	return result, contentType, strings.Join(statuses, ", "), nil
+}
+
+// goName returns the exported Go name of a JSON or operation name, e.g.
+// ComputerID for "Computer ID" or "computer_id" and ComputerIDs for
+// "computer_ids".
+func goName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+	var out strings.Builder
+	for _, word := range words {
+		lower := strings.ToLower(word)
+		if initialisms[lower] {
+			out.WriteString(strings.ToUpper(word))
+			continue
+		}
+		if singular := strings.TrimSuffix(lower, "s"); singular != lower && initialisms[singular] {
+			// e.g. IDs for "ids"
+			out.WriteString(strings.ToUpper(singular) + "s")
+			continue
+		}
+		out.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return out.String()
+}
+
+// lowerFirst lowers the first word of s, keeping initialisms like ID whole.
+func lowerFirst(s string) string {
+	end := 0
+	for end < len(s) && unicode.IsUpper(rune(s[end])) {
+		end++
+	}
+	switch {
+	case end == 0:
+		return s
+	case end == 1, end == len(s):
+		return strings.ToLower(s[:end]) + s[end:]
+	case initialisms[strings.ToLower(s[:end])]:
+		return strings.ToLower(s[:end]) + s[end:]
+	case initialisms[strings.ToLower(s[:end-1])]:
+		// e.g. IDs or the ID of IDNumber
+		return strings.ToLower(s[:end-1]) + s[end-1:]
+	default:
+		return strings.ToLower(s[:1]) + s[1:]
+	}
+}
+
+func writeComment(out *bytes.Buffer, indent string, text string) {
+	if text == "" {
+		fmt.Fprintf(out, "%s//\n", indent)
+		return
+	}
+	for _, line := range wrap(strings.TrimSpace(text), 76) {
+		fmt.Fprintf(out, "%s// %s\n", indent, line)
+	}
+}
+
+// wrap splits text into lines of at most width characters where possible.
+func wrap(text string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	return append(lines, line)
+}
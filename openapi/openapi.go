@@ -0,0 +1,232 @@
+// Package openapi holds the OpenAPI 3 document of the API, which the router
+// serves at /openapi.yaml and the client package is generated from. A test
+// keeps it in sync with the routes registered in gin.
+package openapi
+
+import (
+	_ "embed"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"sort"
+	"strings"
+)
+
+//go:generate go run ./clientgen -out ../client/client.gen.go
+
+// Spec is the OpenAPI document in YAML.
+//
+//go:embed openapi.yaml
+var Spec []byte
+
+// Document is the part of an OpenAPI 3 document that the router test and the
+// client generator need.
+type Document struct {
+	Paths      map[string]PathItem `yaml:"paths"`
+	Components Components          `yaml:"components"`
+}
+
+// Components are the reusable parts of a document.
+type Components struct {
+	Schemas    map[string]*Schema    `yaml:"schemas"`
+	Parameters map[string]*Parameter `yaml:"parameters"`
+	Responses  map[string]*Response  `yaml:"responses"`
+}
+
+// PathItem is the operations of one path.
+type PathItem struct {
+	Parameters []*Parameter `yaml:"parameters"`
+	Get        *Operation   `yaml:"get"`
+	Post       *Operation   `yaml:"post"`
+	Put        *Operation   `yaml:"put"`
+	Patch      *Operation   `yaml:"patch"`
+	Delete     *Operation   `yaml:"delete"`
+}
+
+// Operation is one method of a path.
+type Operation struct {
+	OperationID string                `yaml:"operationId"`
+	Summary     string                `yaml:"summary"`
+	Description string                `yaml:"description"`
+	Parameters  []*Parameter          `yaml:"parameters"`
+	RequestBody *RequestBody          `yaml:"requestBody"`
+	Responses   map[string]*Response  `yaml:"responses"`
+	Security    []map[string][]string `yaml:"security"`
+}
+
+// Parameter is a path, query or header parameter.
+type Parameter struct {
+	Ref         string  `yaml:"$ref"`
+	Name        string  `yaml:"name"`
+	In          string  `yaml:"in"`
+	Description string  `yaml:"description"`
+	Required    bool    `yaml:"required"`
+	Schema      *Schema `yaml:"schema"`
+}
+
+// RequestBody is the body of an operation.
+type RequestBody struct {
+	Required bool                  `yaml:"required"`
+	Content  map[string]*MediaType `yaml:"content"`
+}
+
+// Response is a response of an operation.
+type Response struct {
+	Ref         string                `yaml:"$ref"`
+	Description string                `yaml:"description"`
+	Content     map[string]*MediaType `yaml:"content"`
+}
+
+// MediaType is the schema of a body in one content type.
+type MediaType struct {
+	Schema *Schema `yaml:"schema"`
+}
+
+// Schema is a JSON schema. The properties keep the order of the document.
+type Schema struct {
+	Ref                  string     `yaml:"$ref"`
+	Type                 string     `yaml:"type"`
+	Format               string     `yaml:"format"`
+	Description          string     `yaml:"description"`
+	Nullable             bool       `yaml:"nullable"`
+	Enum                 []string   `yaml:"enum"`
+	Required             []string   `yaml:"required"`
+	Properties           Properties `yaml:"properties"`
+	Items                *Schema    `yaml:"items"`
+	AdditionalProperties *Schema    `yaml:"-"`
+	// AnyAdditionalProperties is set by additionalProperties: true.
+	AnyAdditionalProperties bool `yaml:"-"`
+}
+
+// Property is a named property of an object schema.
+type Property struct {
+	Name   string
+	Schema *Schema
+}
+
+// Properties are the properties of an object schema in document order.
+type Properties []Property
+
+func (p *Properties) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: properties must be a mapping", node.Line)
+	}
+	for i := 0; i < len(node.Content); i += 2 {
+		var schema Schema
+		if err := node.Content[i+1].Decode(&schema); err != nil {
+			return err
+		}
+		*p = append(*p, Property{Name: node.Content[i].Value, Schema: &schema})
+	}
+	return nil
+}
+
+func (s *Schema) UnmarshalYAML(node *yaml.Node) error {
+	type plain Schema
+	if err := node.Decode((*plain)(s)); err != nil {
+		return err
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value != "additionalProperties" {
+			continue
+		}
+		value := node.Content[i+1]
+		if value.Kind == yaml.ScalarNode {
+			return value.Decode(&s.AnyAdditionalProperties)
+		}
+		s.AdditionalProperties = &Schema{}
+		return value.Decode(s.AdditionalProperties)
+	}
+	return nil
+}
+
+// Route is an operation with its method and path.
+type Route struct {
+	Method    string
+	Path      string
+	Operation *Operation
+	// Parameters are the parameters of the path and the operation, with
+	// references resolved.
+	Parameters []*Parameter
+}
+
+// Load parses an OpenAPI document.
+func Load(spec []byte) (*Document, error) {
+	var doc Document
+	if err := yaml.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("error parsing the OpenAPI document: %w", err)
+	}
+	return &doc, nil
+}
+
+// Routes returns the operations of the document sorted by path and method.
+func (d *Document) Routes() ([]Route, error) {
+	var routes []Route
+	for path, item := range d.Paths {
+		for _, method := range []struct {
+			name      string
+			operation *Operation
+		}{
+			{"GET", item.Get}, {"POST", item.Post}, {"PUT", item.Put}, {"PATCH", item.Patch}, {"DELETE", item.Delete},
+		} {
+			if method.operation == nil {
+				continue
+			}
+			var parameters []*Parameter
+			for _, parameter := range append(append([]*Parameter{}, item.Parameters...), method.operation.Parameters...) {
+				resolved, err := d.parameter(parameter)
+				if err != nil {
+					return nil, fmt.Errorf("%s %s: %w", method.name, path, err)
+				}
+				parameters = append(parameters, resolved)
+			}
+			routes = append(routes, Route{Method: method.name, Path: path, Operation: method.operation, Parameters: parameters})
+		}
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return methodOrder(routes[i].Method) < methodOrder(routes[j].Method)
+	})
+	return routes, nil
+}
+
+// Response resolves a reference to a response of the components.
+func (d *Document) Response(response *Response) (*Response, error) {
+	if response.Ref == "" {
+		return response, nil
+	}
+	name := strings.TrimPrefix(response.Ref, "#/components/responses/")
+	resolved, ok := d.Components.Responses[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown response %s", response.Ref)
+	}
+	return resolved, nil
+}
+
+func (d *Document) parameter(parameter *Parameter) (*Parameter, error) {
+	if parameter.Ref == "" {
+		return parameter, nil
+	}
+	name := strings.TrimPrefix(parameter.Ref, "#/components/parameters/")
+	resolved, ok := d.Components.Parameters[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown parameter %s", parameter.Ref)
+	}
+	return resolved, nil
+}
+
+// GinPath returns path with the {name} parameters written like gin's :name.
+func GinPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			segments[i] = ":" + segment[1:len(segment)-1]
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func methodOrder(method string) int {
+	return strings.Index("GET POST PUT PATCH DELETE", method)
+}
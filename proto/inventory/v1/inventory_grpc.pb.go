@@ -0,0 +1,433 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: inventory/v1/inventory.proto
+
+// The inventory of the computers of the company, the employees they are
+// assigned to and the history of the assignments. The service shares the
+// service layer and the authentication with the REST API: send an access
+// token from /v1/auth/generate_access_token in the bearer-token metadata, or
+// a client certificate over mutual TLS.
+
+package inventoryv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	InventoryService_CreateEmployee_FullMethodName  = "/inventory.v1.InventoryService/CreateEmployee"
+	InventoryService_GetEmployee_FullMethodName     = "/inventory.v1.InventoryService/GetEmployee"
+	InventoryService_ListEmployees_FullMethodName   = "/inventory.v1.InventoryService/ListEmployees"
+	InventoryService_CreateComputer_FullMethodName  = "/inventory.v1.InventoryService/CreateComputer"
+	InventoryService_GetComputer_FullMethodName     = "/inventory.v1.InventoryService/GetComputer"
+	InventoryService_ListComputers_FullMethodName   = "/inventory.v1.InventoryService/ListComputers"
+	InventoryService_AssignComputer_FullMethodName  = "/inventory.v1.InventoryService/AssignComputer"
+	InventoryService_DeleteComputer_FullMethodName  = "/inventory.v1.InventoryService/DeleteComputer"
+	InventoryService_ListAssignments_FullMethodName = "/inventory.v1.InventoryService/ListAssignments"
+)
+
+// InventoryServiceClient is the client API for InventoryService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type InventoryServiceClient interface {
+	// CreateEmployee creates an employee. It fails with ALREADY_EXISTS if the
+	// abbreviation or the email is taken.
+	CreateEmployee(ctx context.Context, in *CreateEmployeeRequest, opts ...grpc.CallOption) (*CreateEmployeeResponse, error)
+	// GetEmployee returns an employee by abbreviation.
+	GetEmployee(ctx context.Context, in *GetEmployeeRequest, opts ...grpc.CallOption) (*GetEmployeeResponse, error)
+	// ListEmployees returns all employees.
+	ListEmployees(ctx context.Context, in *ListEmployeesRequest, opts ...grpc.CallOption) (*ListEmployeesResponse, error)
+	// CreateComputer creates a computer assigned to an employee. The
+	// administrator is notified when the employee reaches the quota.
+	CreateComputer(ctx context.Context, in *CreateComputerRequest, opts ...grpc.CallOption) (*CreateComputerResponse, error)
+	// GetComputer returns a computer by ID.
+	GetComputer(ctx context.Context, in *GetComputerRequest, opts ...grpc.CallOption) (*GetComputerResponse, error)
+	// ListComputers returns a page of the computers ordered by ID.
+	ListComputers(ctx context.Context, in *ListComputersRequest, opts ...grpc.CallOption) (*ListComputersResponse, error)
+	// AssignComputer assigns a computer to another employee.
+	AssignComputer(ctx context.Context, in *AssignComputerRequest, opts ...grpc.CallOption) (*AssignComputerResponse, error)
+	// DeleteComputer deletes a computer and its assignment.
+	DeleteComputer(ctx context.Context, in *DeleteComputerRequest, opts ...grpc.CallOption) (*DeleteComputerResponse, error)
+	// ListAssignments returns the assignment history, oldest first.
+	ListAssignments(ctx context.Context, in *ListAssignmentsRequest, opts ...grpc.CallOption) (*ListAssignmentsResponse, error)
+}
+
+type inventoryServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewInventoryServiceClient(cc grpc.ClientConnInterface) InventoryServiceClient {
+	return &inventoryServiceClient{cc}
+}
+
+func (c *inventoryServiceClient) CreateEmployee(ctx context.Context, in *CreateEmployeeRequest, opts ...grpc.CallOption) (*CreateEmployeeResponse, error) {
+	out := new(CreateEmployeeResponse)
+	err := c.cc.Invoke(ctx, InventoryService_CreateEmployee_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) GetEmployee(ctx context.Context, in *GetEmployeeRequest, opts ...grpc.CallOption) (*GetEmployeeResponse, error) {
+	out := new(GetEmployeeResponse)
+	err := c.cc.Invoke(ctx, InventoryService_GetEmployee_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) ListEmployees(ctx context.Context, in *ListEmployeesRequest, opts ...grpc.CallOption) (*ListEmployeesResponse, error) {
+	out := new(ListEmployeesResponse)
+	err := c.cc.Invoke(ctx, InventoryService_ListEmployees_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) CreateComputer(ctx context.Context, in *CreateComputerRequest, opts ...grpc.CallOption) (*CreateComputerResponse, error) {
+	out := new(CreateComputerResponse)
+	err := c.cc.Invoke(ctx, InventoryService_CreateComputer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) GetComputer(ctx context.Context, in *GetComputerRequest, opts ...grpc.CallOption) (*GetComputerResponse, error) {
+	out := new(GetComputerResponse)
+	err := c.cc.Invoke(ctx, InventoryService_GetComputer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) ListComputers(ctx context.Context, in *ListComputersRequest, opts ...grpc.CallOption) (*ListComputersResponse, error) {
+	out := new(ListComputersResponse)
+	err := c.cc.Invoke(ctx, InventoryService_ListComputers_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) AssignComputer(ctx context.Context, in *AssignComputerRequest, opts ...grpc.CallOption) (*AssignComputerResponse, error) {
+	out := new(AssignComputerResponse)
+	err := c.cc.Invoke(ctx, InventoryService_AssignComputer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) DeleteComputer(ctx context.Context, in *DeleteComputerRequest, opts ...grpc.CallOption) (*DeleteComputerResponse, error) {
+	out := new(DeleteComputerResponse)
+	err := c.cc.Invoke(ctx, InventoryService_DeleteComputer_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *inventoryServiceClient) ListAssignments(ctx context.Context, in *ListAssignmentsRequest, opts ...grpc.CallOption) (*ListAssignmentsResponse, error) {
+	out := new(ListAssignmentsResponse)
+	err := c.cc.Invoke(ctx, InventoryService_ListAssignments_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InventoryServiceServer is the server API for InventoryService service.
+// All implementations must embed UnimplementedInventoryServiceServer
+// for forward compatibility
+type InventoryServiceServer interface {
+	// CreateEmployee creates an employee. It fails with ALREADY_EXISTS if the
+	// abbreviation or the email is taken.
+	CreateEmployee(context.Context, *CreateEmployeeRequest) (*CreateEmployeeResponse, error)
+	// GetEmployee returns an employee by abbreviation.
+	GetEmployee(context.Context, *GetEmployeeRequest) (*GetEmployeeResponse, error)
+	// ListEmployees returns all employees.
+	ListEmployees(context.Context, *ListEmployeesRequest) (*ListEmployeesResponse, error)
+	// CreateComputer creates a computer assigned to an employee. The
+	// administrator is notified when the employee reaches the quota.
+	CreateComputer(context.Context, *CreateComputerRequest) (*CreateComputerResponse, error)
+	// GetComputer returns a computer by ID.
+	GetComputer(context.Context, *GetComputerRequest) (*GetComputerResponse, error)
+	// ListComputers returns a page of the computers ordered by ID.
+	ListComputers(context.Context, *ListComputersRequest) (*ListComputersResponse, error)
+	// AssignComputer assigns a computer to another employee.
+	AssignComputer(context.Context, *AssignComputerRequest) (*AssignComputerResponse, error)
+	// DeleteComputer deletes a computer and its assignment.
+	DeleteComputer(context.Context, *DeleteComputerRequest) (*DeleteComputerResponse, error)
+	// ListAssignments returns the assignment history, oldest first.
+	ListAssignments(context.Context, *ListAssignmentsRequest) (*ListAssignmentsResponse, error)
+	mustEmbedUnimplementedInventoryServiceServer()
+}
+
+// UnimplementedInventoryServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedInventoryServiceServer struct {
+}
+
+func (UnimplementedInventoryServiceServer) CreateEmployee(context.Context, *CreateEmployeeRequest) (*CreateEmployeeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateEmployee not implemented")
+}
+func (UnimplementedInventoryServiceServer) GetEmployee(context.Context, *GetEmployeeRequest) (*GetEmployeeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEmployee not implemented")
+}
+func (UnimplementedInventoryServiceServer) ListEmployees(context.Context, *ListEmployeesRequest) (*ListEmployeesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEmployees not implemented")
+}
+func (UnimplementedInventoryServiceServer) CreateComputer(context.Context, *CreateComputerRequest) (*CreateComputerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateComputer not implemented")
+}
+func (UnimplementedInventoryServiceServer) GetComputer(context.Context, *GetComputerRequest) (*GetComputerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetComputer not implemented")
+}
+func (UnimplementedInventoryServiceServer) ListComputers(context.Context, *ListComputersRequest) (*ListComputersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListComputers not implemented")
+}
+func (UnimplementedInventoryServiceServer) AssignComputer(context.Context, *AssignComputerRequest) (*AssignComputerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignComputer not implemented")
+}
+func (UnimplementedInventoryServiceServer) DeleteComputer(context.Context, *DeleteComputerRequest) (*DeleteComputerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteComputer not implemented")
+}
+func (UnimplementedInventoryServiceServer) ListAssignments(context.Context, *ListAssignmentsRequest) (*ListAssignmentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAssignments not implemented")
+}
+func (UnimplementedInventoryServiceServer) mustEmbedUnimplementedInventoryServiceServer() {}
+
+// UnsafeInventoryServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to InventoryServiceServer will
+// result in compilation errors.
+type UnsafeInventoryServiceServer interface {
+	mustEmbedUnimplementedInventoryServiceServer()
+}
+
+func RegisterInventoryServiceServer(s grpc.ServiceRegistrar, srv InventoryServiceServer) {
+	s.RegisterService(&InventoryService_ServiceDesc, srv)
+}
+
+func _InventoryService_CreateEmployee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateEmployeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).CreateEmployee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_CreateEmployee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).CreateEmployee(ctx, req.(*CreateEmployeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_GetEmployee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEmployeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).GetEmployee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_GetEmployee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).GetEmployee(ctx, req.(*GetEmployeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_ListEmployees_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEmployeesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).ListEmployees(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_ListEmployees_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).ListEmployees(ctx, req.(*ListEmployeesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_CreateComputer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateComputerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).CreateComputer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_CreateComputer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).CreateComputer(ctx, req.(*CreateComputerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_GetComputer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetComputerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).GetComputer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_GetComputer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).GetComputer(ctx, req.(*GetComputerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_ListComputers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListComputersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).ListComputers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_ListComputers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).ListComputers(ctx, req.(*ListComputersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_AssignComputer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignComputerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).AssignComputer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_AssignComputer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).AssignComputer(ctx, req.(*AssignComputerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_DeleteComputer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteComputerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).DeleteComputer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_DeleteComputer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).DeleteComputer(ctx, req.(*DeleteComputerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _InventoryService_ListAssignments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAssignmentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(InventoryServiceServer).ListAssignments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: InventoryService_ListAssignments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(InventoryServiceServer).ListAssignments(ctx, req.(*ListAssignmentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// InventoryService_ServiceDesc is the grpc.ServiceDesc for InventoryService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var InventoryService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "inventory.v1.InventoryService",
+	HandlerType: (*InventoryServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateEmployee",
+			Handler:    _InventoryService_CreateEmployee_Handler,
+		},
+		{
+			MethodName: "GetEmployee",
+			Handler:    _InventoryService_GetEmployee_Handler,
+		},
+		{
+			MethodName: "ListEmployees",
+			Handler:    _InventoryService_ListEmployees_Handler,
+		},
+		{
+			MethodName: "CreateComputer",
+			Handler:    _InventoryService_CreateComputer_Handler,
+		},
+		{
+			MethodName: "GetComputer",
+			Handler:    _InventoryService_GetComputer_Handler,
+		},
+		{
+			MethodName: "ListComputers",
+			Handler:    _InventoryService_ListComputers_Handler,
+		},
+		{
+			MethodName: "AssignComputer",
+			Handler:    _InventoryService_AssignComputer_Handler,
+		},
+		{
+			MethodName: "DeleteComputer",
+			Handler:    _InventoryService_DeleteComputer_Handler,
+		},
+		{
+			MethodName: "ListAssignments",
+			Handler:    _InventoryService_ListAssignments_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "inventory/v1/inventory.proto",
+}
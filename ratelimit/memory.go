@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepEvery is the number of calls between removals of idle buckets.
+const sweepEvery = 1024
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	full    time.Time // when the bucket will be full again and can be dropped
+}
+
+// MemoryLimiter keeps the buckets in the process. It is used on its own for a
+// single instance and as the fallback of RedisLimiter.
+type MemoryLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	calls   int
+	now     func() time.Time
+}
+
+func NewMemoryLimiter() *MemoryLimiter {
+	return NewMemoryLimiterWithClock(time.Now)
+}
+
+// NewMemoryLimiterWithClock returns a limiter reading the time from now.
+func NewMemoryLimiterWithClock(now func() time.Time) *MemoryLimiter {
+	return &MemoryLimiter{buckets: make(map[string]*bucket), now: now}
+}
+
+func (l *MemoryLimiter) Allow(_ context.Context, key string, limit Limit) (Result, error) {
+	if limit.Disabled() {
+		return Result{Allowed: true, Remaining: math.MaxInt32}, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.updated).Seconds()*limit.Rate)
+	b.updated = now
+
+	result := Result{}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+	}
+	result.Remaining = int(b.tokens)
+	b.full = now.Add(time.Duration((float64(limit.Burst) - b.tokens) / limit.Rate * float64(time.Second)))
+	return result, nil
+}
+
+// sweep drops the buckets that have refilled completely, as a new bucket
+// would start in the same state.
+func (l *MemoryLimiter) sweep(now time.Time) {
+	l.calls++
+	if l.calls%sweepEvery != 0 {
+		return
+	}
+	for key, b := range l.buckets {
+		if !now.Before(b.full) {
+			delete(l.buckets, key)
+		}
+	}
+}
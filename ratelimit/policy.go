@@ -0,0 +1,39 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Policy sets the limits applied to a request. Every limit that applies has
+// to allow the request.
+type Policy struct {
+	IP     Limit            // per client IP
+	User   Limit            // per authenticated user
+	APIKey Limit            // per API key
+	Routes map[string]Limit // per client IP on a route, by gin route template
+}
+
+// ParseRoutes reads route policies written as comma separated
+// "<route>=<limit>" pairs, e.g. "/v1/auth/refresh=30/m".
+func ParseRoutes(value string) (map[string]Limit, error) {
+	routes := make(map[string]Limit)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		route, limitValue, found := strings.Cut(entry, "=")
+		route = strings.TrimSpace(route)
+		if !found || !strings.HasPrefix(route, "/") {
+			return nil, fmt.Errorf("invalid route rate limit %q, expected <route>=<limit>", entry)
+		}
+		limit, err := ParseLimit(limitValue)
+		if err != nil {
+			return nil, err
+		}
+		routes[route] = limit
+	}
+	return routes, nil
+}
@@ -0,0 +1,83 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit is a token bucket holding up to Burst tokens that refills at Rate
+// tokens per second. Each request takes one token.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Disabled reports whether the limit lets every request through.
+func (l Limit) Disabled() bool {
+	return l.Rate <= 0 || l.Burst <= 0
+}
+
+// String formats the limit the way ParseLimit reads it.
+func (l Limit) String() string {
+	if l.Disabled() {
+		return ""
+	}
+	return fmt.Sprintf("%d/%s", l.Burst, time.Duration(float64(l.Burst)/l.Rate*float64(time.Second)))
+}
+
+// ParseLimit reads a limit written as "<requests>/<period>", e.g. "10/m" or
+// "100/1h", allowing bursts of up to <requests>. An empty string or "0/..."
+// disables the limit.
+func ParseLimit(value string) (Limit, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return Limit{}, nil
+	}
+
+	count, period, found := strings.Cut(value, "/")
+	if !found {
+		return Limit{}, fmt.Errorf("invalid rate limit %q, expected <requests>/<period>", value)
+	}
+	requests, err := strconv.Atoi(strings.TrimSpace(count))
+	if err != nil || requests < 0 {
+		return Limit{}, fmt.Errorf("invalid number of requests in rate limit %q", value)
+	}
+	duration, err := parsePeriod(strings.TrimSpace(period))
+	if err != nil || duration <= 0 {
+		return Limit{}, fmt.Errorf("invalid period in rate limit %q", value)
+	}
+	if requests == 0 {
+		return Limit{}, nil
+	}
+
+	return Limit{Rate: float64(requests) / duration.Seconds(), Burst: requests}, nil
+}
+
+// parsePeriod accepts the units s, m and h on their own or any
+// time.ParseDuration value.
+func parsePeriod(period string) (time.Duration, error) {
+	switch period {
+	case "s":
+		return time.Second, nil
+	case "m":
+		return time.Minute, nil
+	case "h":
+		return time.Hour, nil
+	}
+	return time.ParseDuration(period)
+}
+
+// Result is the outcome of taking a token.
+type Result struct {
+	Allowed    bool
+	Remaining  int           // whole tokens left in the bucket
+	RetryAfter time.Duration // until the next token is available if not allowed
+}
+
+// Limiter takes tokens from the bucket identified by key.
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
@@ -0,0 +1,98 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-redis/redis/v8"
+	"math"
+	"strconv"
+	"time"
+)
+
+// keyPrefix namespaces the buckets in Redis.
+const keyPrefix = "ratelimit:"
+
+// tokenBucket refills and takes from the bucket in KEYS[1] atomically, using
+// the clock of the Redis server so that all instances agree on the time.
+// ARGV holds the rate in tokens per second and the burst. It returns whether
+// the request is allowed, the tokens left and the seconds until the next
+// token, the latter two as strings since Lua numbers are truncated.
+var tokenBucket = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local time = redis.call("TIME")
+local now = tonumber(time[1]) + tonumber(time[2]) / 1000000
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "updated")
+local tokens = tonumber(state[1]) or burst
+local updated = tonumber(state[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - updated) * rate)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = (1 - tokens) / rate
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "updated", tostring(now))
+redis.call("PEXPIRE", KEYS[1], math.ceil((burst - tokens) / rate * 1000) + 1000)
+return {allowed, tostring(tokens), tostring(retry)}
+`)
+
+// RedisLimiter keeps the buckets in Redis so that they are shared by all
+// instances of the service.
+type RedisLimiter struct {
+	client redis.Scripter
+}
+
+func NewRedisLimiter(client redis.Scripter) *RedisLimiter {
+	return &RedisLimiter{client: client}
+}
+
+func (l *RedisLimiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	if limit.Disabled() {
+		return Result{Allowed: true, Remaining: math.MaxInt32}, nil
+	}
+
+	values, err := tokenBucket.Run(ctx, l.client, []string{keyPrefix + key}, limit.Rate, limit.Burst).Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	if len(values) != 3 {
+		return Result{}, fmt.Errorf("unexpected rate limit result %v", values)
+	}
+	allowed, _ := values[0].(int64)
+	tokensValue, _ := values[1].(string)
+	retryValue, _ := values[2].(string)
+	tokens, _ := strconv.ParseFloat(tokensValue, 64)
+	retry, _ := strconv.ParseFloat(retryValue, 64)
+	return Result{
+		Allowed:    allowed == 1,
+		Remaining:  int(tokens),
+		RetryAfter: time.Duration(retry * float64(time.Second)),
+	}, nil
+}
+
+// FallbackLimiter uses Primary and switches to Fallback for the calls in
+// which Primary fails, e.g. while Redis is down. OnFallback, if set, is
+// told about each failure.
+type FallbackLimiter struct {
+	Primary    Limiter
+	Fallback   Limiter
+	OnFallback func(ctx context.Context, err error)
+}
+
+func (l *FallbackLimiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	result, err := l.Primary.Allow(ctx, key, limit)
+	if err == nil {
+		return result, nil
+	}
+	if l.OnFallback != nil {
+		l.OnFallback(ctx, err)
+	}
+	return l.Fallback.Allow(ctx, key, limit)
+}
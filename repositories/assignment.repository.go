@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"context"
+	"gorm.io/gorm"
+	db "greenbone-task/models/db"
+	"time"
+)
+
+type gormAssignmentRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormAssignmentRepository) FindAll(ctx context.Context) ([]db.Assignment, error) {
+	var assignments []db.Assignment
+	err := r.db.WithContext(ctx).Order("assigned_at, id").Find(&assignments).Error
+	return assignments, translate(err)
+}
+
+func (r *gormAssignmentRepository) FindByComputerIDs(ctx context.Context, computerIDs []uint) ([]db.Assignment, error) {
+	var assignments []db.Assignment
+	err := r.db.WithContext(ctx).Where("computer_id IN ?", computerIDs).Order("assigned_at, id").Find(&assignments).Error
+	return assignments, translate(err)
+}
+
+func (r *gormAssignmentRepository) FindByEmployeeIDs(ctx context.Context, employeeIDs []uint) ([]db.Assignment, error) {
+	var assignments []db.Assignment
+	err := r.db.WithContext(ctx).Where("employee_id IN ?", employeeIDs).Order("assigned_at, id").Find(&assignments).Error
+	return assignments, translate(err)
+}
+
+// startAssignment records that a computer is assigned to an employee from at
+// on, ending its previous assignment. Assigning a computer to its current
+// employee again keeps the history as it is.
+func startAssignment(tx *gorm.DB, employeeID uint, computerID uint, at time.Time) error {
+	var current []db.Assignment
+	if err := tx.Where("computer_id = ? AND unassigned_at IS NULL", computerID).Limit(1).Find(&current).Error; err != nil {
+		return err
+	}
+	if len(current) > 0 && current[0].EmployeeID == employeeID {
+		return nil
+	}
+
+	if err := endAssignment(tx, computerID, at); err != nil {
+		return err
+	}
+	return tx.Create(&db.Assignment{ComputerID: computerID, EmployeeID: employeeID, AssignedAt: at}).Error
+}
+
+// endAssignment records that the current assignment of a computer ended at at.
+func endAssignment(tx *gorm.DB, computerID uint, at time.Time) error {
+	return tx.Model(&db.Assignment{}).
+		Where("computer_id = ? AND unassigned_at IS NULL", computerID).
+		Update("unassigned_at", at).Error
+}
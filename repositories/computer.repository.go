@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"context"
+	"gorm.io/gorm"
+	db "greenbone-task/models/db"
+	"time"
+)
+
+type gormComputerRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormComputerRepository) Create(ctx context.Context, computer *db.Computer) error {
+	return translate(r.db.WithContext(ctx).Create(computer).Error)
+}
+
+func (r *gormComputerRepository) Update(ctx context.Context, computer *db.Computer) error {
+	return translate(r.db.WithContext(ctx).Save(computer).Error)
+}
+
+func (r *gormComputerRepository) FindAll(ctx context.Context) ([]db.Computer, error) {
+	var computers []db.Computer
+	err := r.db.WithContext(ctx).Find(&computers).Error
+	return computers, translate(err)
+}
+
+func (r *gormComputerRepository) FindPage(ctx context.Context, offset int, limit int) ([]db.Computer, int64, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&db.Computer{}).Count(&total).Error; err != nil {
+		return nil, 0, translate(err)
+	}
+	var computers []db.Computer
+	err := r.db.WithContext(ctx).Order("id").Offset(offset).Limit(limit).Find(&computers).Error
+	return computers, total, translate(err)
+}
+
+func (r *gormComputerRepository) FindByID(ctx context.Context, id uint) (*db.Computer, error) {
+	var computer db.Computer
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&computer).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &computer, nil
+}
+
+func (r *gormComputerRepository) FindByIDs(ctx context.Context, ids []uint) ([]db.Computer, error) {
+	var computers []db.Computer
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Order("id").Find(&computers).Error
+	return computers, translate(err)
+}
+
+func (r *gormComputerRepository) FindByEmployeeIDs(ctx context.Context, employeeIDs []uint) (map[uint][]db.Computer, error) {
+	var rows []struct {
+		db.Computer
+		EmployeeID uint
+	}
+	err := r.db.WithContext(ctx).Model(&db.Computer{}).
+		Select("computers.*, employee_computers.employee_id").
+		Joins("JOIN employee_computers ON computers.id = employee_computers.computer_id").
+		Where("employee_computers.employee_id IN ?", employeeIDs).
+		Order("computers.id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+
+	computers := make(map[uint][]db.Computer, len(employeeIDs))
+	for _, row := range rows {
+		computers[row.EmployeeID] = append(computers[row.EmployeeID], row.Computer)
+	}
+	return computers, nil
+}
+
+func (r *gormComputerRepository) FindByEmployeeID(ctx context.Context, employeeID uint) ([]db.Computer, error) {
+	var computers []db.Computer
+	err := r.db.WithContext(ctx).
+		Joins("JOIN employee_computers ON computers.id = employee_computers.computer_id").
+		Where("employee_computers.employee_id = ?", employeeID).
+		Find(&computers).Error
+	return computers, translate(err)
+}
+
+func (r *gormComputerRepository) CountByEmployeeAbbrev(ctx context.Context, abbrev string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&db.Computer{}).Where("employee_abbrev = ?", abbrev).Count(&count).Error
+	return count, translate(err)
+}
+
+func (r *gormComputerRepository) CountPerEmployee(ctx context.Context) (map[string]int64, error) {
+	var rows []struct {
+		EmployeeAbbrev string
+		Count          int64
+	}
+	err := r.db.WithContext(ctx).Model(&db.Computer{}).
+		Select("employee_abbrev, COUNT(*) AS count").
+		Where("employee_abbrev <> ''").
+		Group("employee_abbrev").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.EmployeeAbbrev] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *gormComputerRepository) Delete(ctx context.Context, id uint) error {
+	return translate(r.db.WithContext(ctx).Delete(&db.Computer{}, id).Error)
+}
+
+func (r *gormComputerRepository) FindAssignment(ctx context.Context, computerID uint) (*db.EmployeeComputer, error) {
+	var assignment db.EmployeeComputer
+	if err := r.db.WithContext(ctx).Where("computer_id = ?", computerID).First(&assignment).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &assignment, nil
+}
+
+func (r *gormComputerRepository) FindAssignments(ctx context.Context, computerIDs []uint) ([]db.EmployeeComputer, error) {
+	var assignments []db.EmployeeComputer
+	err := r.db.WithContext(ctx).Where("computer_id IN ?", computerIDs).Find(&assignments).Error
+	return assignments, translate(err)
+}
+
+func (r *gormComputerRepository) Assign(ctx context.Context, employeeID uint, computerID uint) error {
+	tx := r.db.WithContext(ctx)
+	result := tx.Model(&db.EmployeeComputer{}).
+		Where("computer_id = ?", computerID).
+		Update("employee_id", employeeID)
+	if result.Error != nil {
+		return translate(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		err := tx.Create(&db.EmployeeComputer{EmployeeID: employeeID, ComputerID: computerID}).Error
+		if err != nil {
+			return translate(err)
+		}
+	}
+
+	return translate(startAssignment(tx, employeeID, computerID, time.Now()))
+}
+
+func (r *gormComputerRepository) Unassign(ctx context.Context, computerID uint) error {
+	tx := r.db.WithContext(ctx)
+	if err := tx.Where("computer_id = ?", computerID).Delete(&db.EmployeeComputer{}).Error; err != nil {
+		return translate(err)
+	}
+	return translate(endAssignment(tx, computerID, time.Now()))
+}
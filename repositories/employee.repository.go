@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	db "greenbone-task/models/db"
+)
+
+type gormEmployeeRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormEmployeeRepository) Create(ctx context.Context, employee *db.Employee) error {
+	return translate(r.db.WithContext(ctx).Create(employee).Error)
+}
+
+func (r *gormEmployeeRepository) FindAll(ctx context.Context) ([]db.Employee, error) {
+	var employees []db.Employee
+	err := r.db.WithContext(ctx).Order("id").Find(&employees).Error
+	return employees, translate(err)
+}
+
+func (r *gormEmployeeRepository) FindByIDs(ctx context.Context, ids []uint) ([]db.Employee, error) {
+	var employees []db.Employee
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Order("id").Find(&employees).Error
+	return employees, translate(err)
+}
+
+func (r *gormEmployeeRepository) FindByID(ctx context.Context, id uint) (*db.Employee, error) {
+	var employee db.Employee
+	if err := r.db.WithContext(ctx).First(&employee, id).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &employee, nil
+}
+
+func (r *gormEmployeeRepository) FindByAbbrev(ctx context.Context, abbrev string) (*db.Employee, error) {
+	var employee db.Employee
+	if err := r.db.WithContext(ctx).Where("abbreviation = ?", abbrev).First(&employee).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &employee, nil
+}
+
+// LockByAbbrev uses SELECT ... FOR UPDATE. SQLite has no row locks; there the
+// single shared connection already serializes transactions.
+func (r *gormEmployeeRepository) LockByAbbrev(ctx context.Context, abbrev string) (*db.Employee, error) {
+	var employee db.Employee
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("abbreviation = ?", abbrev).
+		First(&employee).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+	return &employee, nil
+}
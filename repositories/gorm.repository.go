@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+)
+
+type gormStore struct {
+	db *gorm.DB
+}
+
+// NewGormStore returns a Store backed by the given gorm connection.
+func NewGormStore(db *gorm.DB) Store {
+	return &gormStore{db: db}
+}
+
+func (s *gormStore) Computers() ComputerRepository {
+	return &gormComputerRepository{db: s.db}
+}
+
+func (s *gormStore) Employees() EmployeeRepository {
+	return &gormEmployeeRepository{db: s.db}
+}
+
+func (s *gormStore) Assignments() AssignmentRepository {
+	return &gormAssignmentRepository{db: s.db}
+}
+
+func (s *gormStore) Webhooks() WebhookRepository {
+	return &gormWebhookRepository{db: s.db}
+}
+
+func (s *gormStore) Tokens() TokenRepository {
+	return &gormTokenRepository{db: s.db}
+}
+
+func (s *gormStore) Transaction(ctx context.Context, fn func(tx Store) error) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&gormStore{db: tx})
+	})
+}
+
+// translate maps gorm errors onto the repository sentinel errors.
+func translate(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey), isSQLiteUniqueViolation(err):
+		return ErrDuplicate
+	}
+	return err
+}
+
+// SQLite extended result codes of unique and primary key violations.
+const (
+	sqliteConstraintPrimaryKey = 1555
+	sqliteConstraintUnique     = 2067
+)
+
+// isSQLiteUniqueViolation reports unique violations of the SQLite driver,
+// which, unlike the postgres one, gorm does not translate.
+func isSQLiteUniqueViolation(err error) bool {
+	var coded interface{ Code() int }
+	if !errors.As(err, &coded) {
+		return false
+	}
+	return coded.Code() == sqliteConstraintUnique || coded.Code() == sqliteConstraintPrimaryKey
+}
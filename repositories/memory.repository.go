@@ -0,0 +1,709 @@
+package repositories
+
+import (
+	"context"
+	db "greenbone-task/models/db"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-memory Store used by unit tests in place of a database.
+type MemoryStore struct {
+	txMu           sync.Mutex
+	mu             sync.RWMutex
+	computers      map[uint]db.Computer
+	employees      map[uint]db.Employee
+	tokens         map[int64]db.Token
+	assignments    map[uint]uint // computer ID -> employee ID
+	history        []db.Assignment
+	webhooks       map[uint]db.Webhook
+	deliveries     map[uint]db.WebhookDelivery
+	attempts       []db.WebhookAttempt
+	nextComputerID uint
+	nextEmployeeID uint
+	nextWebhookID  uint
+	nextDeliveryID uint
+	nextAttemptID  uint
+}
+
+// NewMemoryStore returns an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		computers:   map[uint]db.Computer{},
+		employees:   map[uint]db.Employee{},
+		tokens:      map[int64]db.Token{},
+		assignments: map[uint]uint{},
+		webhooks:    map[uint]db.Webhook{},
+		deliveries:  map[uint]db.WebhookDelivery{},
+	}
+}
+
+func (s *MemoryStore) Computers() ComputerRepository {
+	return &memoryComputerRepository{s}
+}
+
+func (s *MemoryStore) Employees() EmployeeRepository {
+	return &memoryEmployeeRepository{s}
+}
+
+func (s *MemoryStore) Assignments() AssignmentRepository {
+	return &memoryAssignmentRepository{s}
+}
+
+func (s *MemoryStore) Webhooks() WebhookRepository {
+	return &memoryWebhookRepository{s}
+}
+
+func (s *MemoryStore) Tokens() TokenRepository {
+	return &memoryTokenRepository{s}
+}
+
+// Transaction serializes transactions and restores a snapshot of the store
+// when fn fails, mirroring a database rollback.
+func (s *MemoryStore) Transaction(_ context.Context, fn func(tx Store) error) error {
+	s.txMu.Lock()
+	defer s.txMu.Unlock()
+
+	snapshot := s.snapshot()
+	if err := fn(&memoryTx{s}); err != nil {
+		s.restore(snapshot)
+		return err
+	}
+	return nil
+}
+
+func (s *MemoryStore) snapshot() *MemoryStore {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	copied := &MemoryStore{
+		computers:      make(map[uint]db.Computer, len(s.computers)),
+		employees:      make(map[uint]db.Employee, len(s.employees)),
+		tokens:         make(map[int64]db.Token, len(s.tokens)),
+		assignments:    make(map[uint]uint, len(s.assignments)),
+		history:        append([]db.Assignment(nil), s.history...),
+		webhooks:       make(map[uint]db.Webhook, len(s.webhooks)),
+		deliveries:     make(map[uint]db.WebhookDelivery, len(s.deliveries)),
+		attempts:       append([]db.WebhookAttempt(nil), s.attempts...),
+		nextComputerID: s.nextComputerID,
+		nextEmployeeID: s.nextEmployeeID,
+		nextWebhookID:  s.nextWebhookID,
+		nextDeliveryID: s.nextDeliveryID,
+		nextAttemptID:  s.nextAttemptID,
+	}
+	for k, v := range s.computers {
+		copied.computers[k] = v
+	}
+	for k, v := range s.employees {
+		copied.employees[k] = v
+	}
+	for k, v := range s.tokens {
+		copied.tokens[k] = v
+	}
+	for k, v := range s.assignments {
+		copied.assignments[k] = v
+	}
+	for k, v := range s.webhooks {
+		copied.webhooks[k] = v
+	}
+	for k, v := range s.deliveries {
+		copied.deliveries[k] = v
+	}
+	return copied
+}
+
+func (s *MemoryStore) restore(snapshot *MemoryStore) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.computers = snapshot.computers
+	s.employees = snapshot.employees
+	s.tokens = snapshot.tokens
+	s.assignments = snapshot.assignments
+	s.history = snapshot.history
+	s.webhooks = snapshot.webhooks
+	s.deliveries = snapshot.deliveries
+	s.attempts = snapshot.attempts
+	s.nextComputerID = snapshot.nextComputerID
+	s.nextEmployeeID = snapshot.nextEmployeeID
+	s.nextWebhookID = snapshot.nextWebhookID
+	s.nextDeliveryID = snapshot.nextDeliveryID
+	s.nextAttemptID = snapshot.nextAttemptID
+}
+
+// memoryTx is the Store handed to a transaction; nested transactions join it.
+type memoryTx struct {
+	*MemoryStore
+}
+
+func (tx *memoryTx) Transaction(_ context.Context, fn func(tx Store) error) error {
+	return fn(tx)
+}
+
+type memoryComputerRepository struct {
+	s *MemoryStore
+}
+
+func (r *memoryComputerRepository) Create(_ context.Context, computer *db.Computer) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, existing := range r.s.computers {
+		if existing.MacAddress == computer.MacAddress {
+			return ErrDuplicate
+		}
+	}
+
+	r.s.nextComputerID++
+	computer.ID = r.s.nextComputerID
+	computer.CreatedAt = time.Now()
+	computer.UpdatedAt = computer.CreatedAt
+	r.s.computers[computer.ID] = *computer
+	return nil
+}
+
+func (r *memoryComputerRepository) Update(_ context.Context, computer *db.Computer) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.computers[computer.ID]; !ok {
+		return ErrNotFound
+	}
+	for id, existing := range r.s.computers {
+		if id != computer.ID && existing.MacAddress == computer.MacAddress {
+			return ErrDuplicate
+		}
+	}
+
+	computer.UpdatedAt = time.Now()
+	r.s.computers[computer.ID] = *computer
+	return nil
+}
+
+func (r *memoryComputerRepository) FindAll(_ context.Context) ([]db.Computer, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	computers := make([]db.Computer, 0, len(r.s.computers))
+	for _, computer := range r.s.computers {
+		computers = append(computers, computer)
+	}
+	sortComputers(computers)
+	return computers, nil
+}
+
+func (r *memoryComputerRepository) FindPage(ctx context.Context, offset int, limit int) ([]db.Computer, int64, error) {
+	computers, _ := r.FindAll(ctx)
+	total := int64(len(computers))
+	if offset > len(computers) {
+		offset = len(computers)
+	}
+	if offset+limit < len(computers) {
+		computers = computers[:offset+limit]
+	}
+	return computers[offset:], total, nil
+}
+
+func (r *memoryComputerRepository) FindByID(_ context.Context, id uint) (*db.Computer, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	computer, ok := r.s.computers[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &computer, nil
+}
+
+func (r *memoryComputerRepository) FindByIDs(_ context.Context, ids []uint) ([]db.Computer, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var computers []db.Computer
+	for _, id := range ids {
+		if computer, ok := r.s.computers[id]; ok {
+			computers = append(computers, computer)
+		}
+	}
+	sortComputers(computers)
+	return computers, nil
+}
+
+func (r *memoryComputerRepository) FindByEmployeeIDs(ctx context.Context, employeeIDs []uint) (map[uint][]db.Computer, error) {
+	computers := make(map[uint][]db.Computer, len(employeeIDs))
+	for _, employeeID := range employeeIDs {
+		found, _ := r.FindByEmployeeID(ctx, employeeID)
+		if len(found) > 0 {
+			computers[employeeID] = found
+		}
+	}
+	return computers, nil
+}
+
+func (r *memoryComputerRepository) FindByEmployeeID(_ context.Context, employeeID uint) ([]db.Computer, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var computers []db.Computer
+	for computerID, assignee := range r.s.assignments {
+		if computer, ok := r.s.computers[computerID]; ok && assignee == employeeID {
+			computers = append(computers, computer)
+		}
+	}
+	sortComputers(computers)
+	return computers, nil
+}
+
+func (r *memoryComputerRepository) CountByEmployeeAbbrev(_ context.Context, abbrev string) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var count int64
+	for _, computer := range r.s.computers {
+		if computer.EmployeeAbbrev == abbrev {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memoryComputerRepository) CountPerEmployee(_ context.Context) (map[string]int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	counts := map[string]int64{}
+	for _, computer := range r.s.computers {
+		if computer.EmployeeAbbrev != "" {
+			counts[computer.EmployeeAbbrev]++
+		}
+	}
+	return counts, nil
+}
+
+func (r *memoryComputerRepository) Delete(_ context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.computers, id)
+	return nil
+}
+
+func (r *memoryComputerRepository) FindAssignment(_ context.Context, computerID uint) (*db.EmployeeComputer, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	employeeID, ok := r.s.assignments[computerID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &db.EmployeeComputer{EmployeeID: employeeID, ComputerID: computerID}, nil
+}
+
+func (r *memoryComputerRepository) FindAssignments(_ context.Context, computerIDs []uint) ([]db.EmployeeComputer, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var assignments []db.EmployeeComputer
+	for _, computerID := range computerIDs {
+		if employeeID, ok := r.s.assignments[computerID]; ok {
+			assignments = append(assignments, db.EmployeeComputer{EmployeeID: employeeID, ComputerID: computerID})
+		}
+	}
+	return assignments, nil
+}
+
+func (r *memoryComputerRepository) Assign(_ context.Context, employeeID uint, computerID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	previous, assigned := r.s.assignments[computerID]
+	r.s.assignments[computerID] = employeeID
+	if assigned && previous == employeeID {
+		return nil
+	}
+	now := time.Now()
+	r.s.endAssignment(computerID, now)
+	r.s.history = append(r.s.history, db.Assignment{
+		ID:         uint(len(r.s.history) + 1),
+		ComputerID: computerID,
+		EmployeeID: employeeID,
+		AssignedAt: now,
+	})
+	return nil
+}
+
+func (r *memoryComputerRepository) Unassign(_ context.Context, computerID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	delete(r.s.assignments, computerID)
+	r.s.endAssignment(computerID, time.Now())
+	return nil
+}
+
+// endAssignment ends the current assignment of a computer in the history.
+// The caller holds the write lock.
+func (s *MemoryStore) endAssignment(computerID uint, at time.Time) {
+	for i := range s.history {
+		if s.history[i].ComputerID == computerID && s.history[i].UnassignedAt == nil {
+			s.history[i].UnassignedAt = &at
+		}
+	}
+}
+
+type memoryEmployeeRepository struct {
+	s *MemoryStore
+}
+
+func (r *memoryEmployeeRepository) Create(_ context.Context, employee *db.Employee) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, existing := range r.s.employees {
+		if existing.Abbreviation == employee.Abbreviation || existing.Email == employee.Email {
+			return ErrDuplicate
+		}
+	}
+
+	r.s.nextEmployeeID++
+	employee.ID = r.s.nextEmployeeID
+	employee.CreatedAt = time.Now()
+	employee.UpdatedAt = employee.CreatedAt
+	r.s.employees[employee.ID] = *employee
+	return nil
+}
+
+func (r *memoryEmployeeRepository) FindAll(_ context.Context) ([]db.Employee, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	employees := make([]db.Employee, 0, len(r.s.employees))
+	for _, employee := range r.s.employees {
+		employees = append(employees, employee)
+	}
+	sort.Slice(employees, func(i, j int) bool { return employees[i].ID < employees[j].ID })
+	return employees, nil
+}
+
+func (r *memoryEmployeeRepository) FindByIDs(ctx context.Context, ids []uint) ([]db.Employee, error) {
+	var employees []db.Employee
+	for _, id := range ids {
+		if employee, err := r.FindByID(ctx, id); err == nil {
+			employees = append(employees, *employee)
+		}
+	}
+	sort.Slice(employees, func(i, j int) bool { return employees[i].ID < employees[j].ID })
+	return employees, nil
+}
+
+func (r *memoryEmployeeRepository) FindByID(_ context.Context, id uint) (*db.Employee, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	employee, ok := r.s.employees[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &employee, nil
+}
+
+func (r *memoryEmployeeRepository) FindByAbbrev(_ context.Context, abbrev string) (*db.Employee, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, employee := range r.s.employees {
+		if employee.Abbreviation == abbrev {
+			return &employee, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// LockByAbbrev relies on MemoryStore transactions being serialized.
+func (r *memoryEmployeeRepository) LockByAbbrev(ctx context.Context, abbrev string) (*db.Employee, error) {
+	return r.FindByAbbrev(ctx, abbrev)
+}
+
+type memoryAssignmentRepository struct {
+	s *MemoryStore
+}
+
+func (r *memoryAssignmentRepository) FindAll(_ context.Context) ([]db.Assignment, error) {
+	return r.find(func(db.Assignment) bool { return true }), nil
+}
+
+func (r *memoryAssignmentRepository) FindByComputerIDs(_ context.Context, computerIDs []uint) ([]db.Assignment, error) {
+	ids := idSet(computerIDs)
+	return r.find(func(assignment db.Assignment) bool { return ids[assignment.ComputerID] }), nil
+}
+
+func (r *memoryAssignmentRepository) FindByEmployeeIDs(_ context.Context, employeeIDs []uint) ([]db.Assignment, error) {
+	ids := idSet(employeeIDs)
+	return r.find(func(assignment db.Assignment) bool { return ids[assignment.EmployeeID] }), nil
+}
+
+// find returns the assignments matching keep in the order they started.
+func (r *memoryAssignmentRepository) find(keep func(db.Assignment) bool) []db.Assignment {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var assignments []db.Assignment
+	for _, assignment := range r.s.history {
+		if keep(assignment) {
+			assignments = append(assignments, assignment)
+		}
+	}
+	return assignments
+}
+
+func idSet(ids []uint) map[uint]bool {
+	set := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
+
+type memoryWebhookRepository struct {
+	s *MemoryStore
+}
+
+func (r *memoryWebhookRepository) Create(_ context.Context, webhook *db.Webhook) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.nextWebhookID++
+	webhook.ID = r.s.nextWebhookID
+	webhook.CreatedAt = time.Now()
+	webhook.UpdatedAt = webhook.CreatedAt
+	r.s.webhooks[webhook.ID] = *webhook
+	return nil
+}
+
+func (r *memoryWebhookRepository) Update(_ context.Context, webhook *db.Webhook) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.webhooks[webhook.ID]; !ok {
+		return ErrNotFound
+	}
+	webhook.UpdatedAt = time.Now()
+	r.s.webhooks[webhook.ID] = *webhook
+	return nil
+}
+
+func (r *memoryWebhookRepository) FindAll(_ context.Context) ([]db.Webhook, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	webhooks := make([]db.Webhook, 0, len(r.s.webhooks))
+	for _, webhook := range r.s.webhooks {
+		webhooks = append(webhooks, webhook)
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].ID < webhooks[j].ID })
+	return webhooks, nil
+}
+
+func (r *memoryWebhookRepository) FindByID(_ context.Context, id uint) (*db.Webhook, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	webhook, ok := r.s.webhooks[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &webhook, nil
+}
+
+func (r *memoryWebhookRepository) Delete(_ context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.webhooks[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.s.webhooks, id)
+	deleted := map[uint]bool{}
+	for deliveryID, delivery := range r.s.deliveries {
+		if delivery.WebhookID == id {
+			delete(r.s.deliveries, deliveryID)
+			deleted[deliveryID] = true
+		}
+	}
+	attempts := r.s.attempts[:0]
+	for _, attempt := range r.s.attempts {
+		if !deleted[attempt.DeliveryID] {
+			attempts = append(attempts, attempt)
+		}
+	}
+	r.s.attempts = attempts
+	return nil
+}
+
+func (r *memoryWebhookRepository) CreateDelivery(_ context.Context, delivery *db.WebhookDelivery) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.webhooks[delivery.WebhookID]; !ok {
+		return ErrNotFound
+	}
+	r.s.nextDeliveryID++
+	delivery.ID = r.s.nextDeliveryID
+	delivery.CreatedAt = time.Now()
+	delivery.UpdatedAt = delivery.CreatedAt
+	r.s.deliveries[delivery.ID] = *delivery
+	return nil
+}
+
+func (r *memoryWebhookRepository) UpdateDelivery(_ context.Context, delivery *db.WebhookDelivery, claimedUntil time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, ok := r.s.deliveries[delivery.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	if stored.NextAttemptAt == nil || !stored.NextAttemptAt.Equal(claimedUntil) {
+		return ErrStale
+	}
+	delivery.UpdatedAt = time.Now()
+	r.s.deliveries[delivery.ID] = *delivery
+	return nil
+}
+
+func (r *memoryWebhookRepository) FindDelivery(_ context.Context, id uint) (*db.WebhookDelivery, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	delivery, ok := r.s.deliveries[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &delivery, nil
+}
+
+func (r *memoryWebhookRepository) FindDeliveries(_ context.Context, webhookID uint, status string, limit int) ([]db.WebhookDelivery, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var deliveries []db.WebhookDelivery
+	for _, delivery := range r.s.deliveries {
+		if delivery.WebhookID == webhookID && (status == "" || delivery.Status == status) {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].ID > deliveries[j].ID })
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
+
+func (r *memoryWebhookRepository) ClaimDueDeliveries(_ context.Context, now time.Time, until time.Time, limit int) ([]db.WebhookDelivery, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var due []db.WebhookDelivery
+	for _, delivery := range r.s.deliveries {
+		if delivery.Status == db.DeliveryPending && delivery.NextAttemptAt != nil && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		if !due[i].NextAttemptAt.Equal(*due[j].NextAttemptAt) {
+			return due[i].NextAttemptAt.Before(*due[j].NextAttemptAt)
+		}
+		return due[i].ID < due[j].ID
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	for i := range due {
+		claimed := until
+		due[i].NextAttemptAt = &claimed
+		r.s.deliveries[due[i].ID] = due[i]
+	}
+	return due, nil
+}
+
+func (r *memoryWebhookRepository) CreateAttempt(_ context.Context, attempt *db.WebhookAttempt) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.deliveries[attempt.DeliveryID]; !ok {
+		return ErrNotFound
+	}
+	r.s.nextAttemptID++
+	attempt.ID = r.s.nextAttemptID
+	r.s.attempts = append(r.s.attempts, *attempt)
+	return nil
+}
+
+func (r *memoryWebhookRepository) FindAttempts(_ context.Context, deliveryID uint) ([]db.WebhookAttempt, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var attempts []db.WebhookAttempt
+	for _, attempt := range r.s.attempts {
+		if attempt.DeliveryID == deliveryID {
+			attempts = append(attempts, attempt)
+		}
+	}
+	return attempts, nil
+}
+
+type memoryTokenRepository struct {
+	s *MemoryStore
+}
+
+func (r *memoryTokenRepository) Create(_ context.Context, token *db.Token) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.tokens[token.ID]; ok {
+		return ErrDuplicate
+	}
+	r.s.tokens[token.ID] = *token
+	return nil
+}
+
+func (r *memoryTokenRepository) FindActive(_ context.Context, id int64, tokenType string) (*db.Token, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	token, ok := r.s.tokens[id]
+	if !ok || token.Type != tokenType || token.Blacklisted {
+		return nil, ErrNotFound
+	}
+	return &token, nil
+}
+
+func (r *memoryTokenRepository) Delete(_ context.Context, id int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.tokens[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.s.tokens, id)
+	return nil
+}
+
+func (r *memoryTokenRepository) DeleteExpired(_ context.Context, before time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var deleted int64
+	for id, token := range r.s.tokens {
+		if token.ExpiresAt.Before(before) {
+			delete(r.s.tokens, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func sortComputers(computers []db.Computer) {
+	sort.Slice(computers, func(i, j int) bool { return computers[i].ID < computers[j].ID })
+}
@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	db "greenbone-task/models/db"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when a requested record does not exist.
+	ErrNotFound = errors.New("record not found")
+	// ErrDuplicate is returned when a unique constraint would be violated.
+	ErrDuplicate = errors.New("record already exists")
+	// ErrStale is returned when a record changed since it was read.
+	ErrStale = errors.New("record was changed concurrently")
+)
+
+// ComputerRepository persists computers and their employee assignments.
+type ComputerRepository interface {
+	Create(ctx context.Context, computer *db.Computer) error
+	Update(ctx context.Context, computer *db.Computer) error
+	FindAll(ctx context.Context) ([]db.Computer, error)
+	// FindPage returns limit computers ordered by ID after skipping offset,
+	// and the number of all computers.
+	FindPage(ctx context.Context, offset int, limit int) ([]db.Computer, int64, error)
+	FindByID(ctx context.Context, id uint) (*db.Computer, error)
+	FindByIDs(ctx context.Context, ids []uint) ([]db.Computer, error)
+	FindByEmployeeID(ctx context.Context, employeeID uint) ([]db.Computer, error)
+	// FindByEmployeeIDs returns the computers assigned to each of the
+	// employees.
+	FindByEmployeeIDs(ctx context.Context, employeeIDs []uint) (map[uint][]db.Computer, error)
+	CountByEmployeeAbbrev(ctx context.Context, abbrev string) (int64, error)
+	// CountPerEmployee returns the number of computers of every employee
+	// abbreviation that has at least one.
+	CountPerEmployee(ctx context.Context) (map[string]int64, error)
+	Delete(ctx context.Context, id uint) error
+
+	// FindAssignment returns the employee_computers row of a computer.
+	FindAssignment(ctx context.Context, computerID uint) (*db.EmployeeComputer, error)
+	// FindAssignments returns the employee_computers rows of the computers
+	// that are assigned.
+	FindAssignments(ctx context.Context, computerIDs []uint) ([]db.EmployeeComputer, error)
+	// Assign links a computer to an employee, replacing any previous
+	// assignment, and records it in the assignment history.
+	Assign(ctx context.Context, employeeID uint, computerID uint) error
+	// Unassign removes the employee_computers row of a computer, if any, and
+	// ends its assignment in the history.
+	Unassign(ctx context.Context, computerID uint) error
+}
+
+// EmployeeRepository persists employees.
+type EmployeeRepository interface {
+	Create(ctx context.Context, employee *db.Employee) error
+	FindAll(ctx context.Context) ([]db.Employee, error)
+	FindByID(ctx context.Context, id uint) (*db.Employee, error)
+	FindByIDs(ctx context.Context, ids []uint) ([]db.Employee, error)
+	FindByAbbrev(ctx context.Context, abbrev string) (*db.Employee, error)
+	// LockByAbbrev finds an employee and locks its row until the surrounding
+	// transaction ends, serializing concurrent assignments to that employee.
+	LockByAbbrev(ctx context.Context, abbrev string) (*db.Employee, error)
+}
+
+// AssignmentRepository reads the assignment history, which the computer
+// repository records as computers are assigned and unassigned. Assignments
+// are ordered by the time they started.
+type AssignmentRepository interface {
+	FindAll(ctx context.Context) ([]db.Assignment, error)
+	FindByComputerIDs(ctx context.Context, computerIDs []uint) ([]db.Assignment, error)
+	FindByEmployeeIDs(ctx context.Context, employeeIDs []uint) ([]db.Assignment, error)
+}
+
+// WebhookRepository persists webhooks, their deliveries and the log of the
+// attempts to send them.
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *db.Webhook) error
+	Update(ctx context.Context, webhook *db.Webhook) error
+	FindAll(ctx context.Context) ([]db.Webhook, error)
+	FindByID(ctx context.Context, id uint) (*db.Webhook, error)
+	// Delete removes a webhook with its deliveries.
+	Delete(ctx context.Context, id uint) error
+
+	CreateDelivery(ctx context.Context, delivery *db.WebhookDelivery) error
+	// UpdateDelivery saves a delivery claimed until claimedUntil. It returns
+	// ErrStale if the claim expired and another worker claimed the delivery.
+	UpdateDelivery(ctx context.Context, delivery *db.WebhookDelivery, claimedUntil time.Time) error
+	FindDelivery(ctx context.Context, id uint) (*db.WebhookDelivery, error)
+	// FindDeliveries returns the last limit deliveries of a webhook, newest
+	// first, only those with status unless it is empty.
+	FindDeliveries(ctx context.Context, webhookID uint, status string, limit int) ([]db.WebhookDelivery, error)
+	// ClaimDueDeliveries returns up to limit pending deliveries whose next
+	// attempt is due at now and moves that attempt to until, so that other
+	// workers skip them while they are sent.
+	ClaimDueDeliveries(ctx context.Context, now time.Time, until time.Time, limit int) ([]db.WebhookDelivery, error)
+
+	CreateAttempt(ctx context.Context, attempt *db.WebhookAttempt) error
+	// FindAttempts returns the attempts of a delivery, oldest first.
+	FindAttempts(ctx context.Context, deliveryID uint) ([]db.WebhookAttempt, error)
+}
+
+// TokenRepository persists issued JWT tokens.
+type TokenRepository interface {
+	Create(ctx context.Context, token *db.Token) error
+	// FindActive returns a non-blacklisted token of the given type.
+	FindActive(ctx context.Context, id int64, tokenType string) (*db.Token, error)
+	Delete(ctx context.Context, id int64) error
+	// DeleteExpired removes tokens that expired before the given time.
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Store gives access to all repositories backed by the same storage.
+type Store interface {
+	Computers() ComputerRepository
+	Employees() EmployeeRepository
+	Assignments() AssignmentRepository
+	Webhooks() WebhookRepository
+	Tokens() TokenRepository
+
+	// Transaction runs fn with a Store whose repositories share a single
+	// database transaction. The transaction is committed when fn returns nil
+	// and rolled back otherwise.
+	Transaction(ctx context.Context, fn func(tx Store) error) error
+}
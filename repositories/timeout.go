@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"time"
+)
+
+const cancelKey = "timeout:cancel"
+
+// TimeoutPlugin bounds every statement by Timeout, in addition to the
+// deadline and cancellation of the caller's context. Zero disables it.
+type TimeoutPlugin struct {
+	Timeout time.Duration
+}
+
+func (TimeoutPlugin) Name() string {
+	return "timeout"
+}
+
+// Initialize wraps the statement context of each gorm callback chain. Row
+// is left out since its rows are read after the chain has finished.
+func (p TimeoutPlugin) Initialize(db *gorm.DB) error {
+	if p.Timeout <= 0 {
+		return nil
+	}
+
+	cb := db.Callback()
+	for _, err := range []error{
+		cb.Create().Before("gorm:begin_transaction").Register("timeout:before_create", p.start),
+		cb.Create().After("gorm:commit_or_rollback_transaction").Register("timeout:after_create", cancel),
+		cb.Query().Before("gorm:query").Register("timeout:before_query", p.start),
+		cb.Query().After("gorm:after_query").Register("timeout:after_query", cancel),
+		cb.Update().Before("gorm:begin_transaction").Register("timeout:before_update", p.start),
+		cb.Update().After("gorm:commit_or_rollback_transaction").Register("timeout:after_update", cancel),
+		cb.Delete().Before("gorm:begin_transaction").Register("timeout:before_delete", p.start),
+		cb.Delete().After("gorm:commit_or_rollback_transaction").Register("timeout:after_delete", cancel),
+		cb.Raw().Before("gorm:raw").Register("timeout:before_raw", p.start),
+		cb.Raw().After("gorm:raw").Register("timeout:after_raw", cancel),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p TimeoutPlugin) start(db *gorm.DB) {
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, p.Timeout)
+	db.Statement.Context = ctx
+	db.InstanceSet(cancelKey, cancel)
+}
+
+func cancel(db *gorm.DB) {
+	if value, ok := db.InstanceGet(cancelKey); ok {
+		if cancel, ok := value.(context.CancelFunc); ok {
+			cancel()
+		}
+	}
+}
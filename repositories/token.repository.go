@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+	"gorm.io/gorm"
+	db "greenbone-task/models/db"
+	"time"
+)
+
+type gormTokenRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormTokenRepository) Create(ctx context.Context, token *db.Token) error {
+	return translate(r.db.WithContext(ctx).Create(token).Error)
+}
+
+func (r *gormTokenRepository) FindActive(ctx context.Context, id int64, tokenType string) (*db.Token, error) {
+	var token db.Token
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND type = ? AND blacklisted = ?", id, tokenType, false).
+		First(&token).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+	return &token, nil
+}
+
+func (r *gormTokenRepository) Delete(ctx context.Context, id int64) error {
+	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&db.Token{})
+	if result.Error != nil {
+		return translate(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *gormTokenRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at < ?", before).Delete(&db.Token{})
+	return result.RowsAffected, translate(result.Error)
+}
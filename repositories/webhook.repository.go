@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	db "greenbone-task/models/db"
+	"time"
+)
+
+type gormWebhookRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormWebhookRepository) Create(ctx context.Context, webhook *db.Webhook) error {
+	return translate(r.db.WithContext(ctx).Create(webhook).Error)
+}
+
+func (r *gormWebhookRepository) Update(ctx context.Context, webhook *db.Webhook) error {
+	return translate(r.db.WithContext(ctx).Save(webhook).Error)
+}
+
+func (r *gormWebhookRepository) FindAll(ctx context.Context) ([]db.Webhook, error) {
+	var webhooks []db.Webhook
+	err := r.db.WithContext(ctx).Order("id").Find(&webhooks).Error
+	return webhooks, translate(err)
+}
+
+func (r *gormWebhookRepository) FindByID(ctx context.Context, id uint) (*db.Webhook, error) {
+	var webhook db.Webhook
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&webhook).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &webhook, nil
+}
+
+func (r *gormWebhookRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&db.Webhook{})
+	if result.Error != nil {
+		return translate(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *gormWebhookRepository) CreateDelivery(ctx context.Context, delivery *db.WebhookDelivery) error {
+	return translate(r.db.WithContext(ctx).Create(delivery).Error)
+}
+
+func (r *gormWebhookRepository) UpdateDelivery(ctx context.Context, delivery *db.WebhookDelivery, claimedUntil time.Time) error {
+	result := r.db.WithContext(ctx).Model(delivery).Where("next_attempt_at = ?", claimedUntil).Select("*").Updates(delivery)
+	if result.Error != nil {
+		return translate(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrStale
+	}
+	return nil
+}
+
+func (r *gormWebhookRepository) FindDelivery(ctx context.Context, id uint) (*db.WebhookDelivery, error) {
+	var delivery db.WebhookDelivery
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&delivery).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &delivery, nil
+}
+
+func (r *gormWebhookRepository) FindDeliveries(ctx context.Context, webhookID uint, status string, limit int) ([]db.WebhookDelivery, error) {
+	query := r.db.WithContext(ctx).Where("webhook_id = ?", webhookID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var deliveries []db.WebhookDelivery
+	err := query.Order("id DESC").Limit(limit).Find(&deliveries).Error
+	return deliveries, translate(err)
+}
+
+// ClaimDueDeliveries locks the due rows with SKIP LOCKED, so that concurrent
+// workers on Postgres claim different deliveries. SQLite has no row locks;
+// there the single shared connection serializes the claims.
+func (r *gormWebhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, until time.Time, limit int) ([]db.WebhookDelivery, error) {
+	var deliveries []db.WebhookDelivery
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", db.DeliveryPending, now).
+			Order("next_attempt_at, id").Limit(limit).
+			Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+
+		ids := make([]uint, len(deliveries))
+		for i := range deliveries {
+			ids[i] = deliveries[i].ID
+			deliveries[i].NextAttemptAt = &until
+		}
+		return tx.Model(&db.WebhookDelivery{}).Where("id IN ?", ids).Update("next_attempt_at", until).Error
+	})
+	if err != nil {
+		return nil, translate(err)
+	}
+	return deliveries, nil
+}
+
+func (r *gormWebhookRepository) CreateAttempt(ctx context.Context, attempt *db.WebhookAttempt) error {
+	return translate(r.db.WithContext(ctx).Create(attempt).Error)
+}
+
+func (r *gormWebhookRepository) FindAttempts(ctx context.Context, deliveryID uint) ([]db.WebhookAttempt, error) {
+	var attempts []db.WebhookAttempt
+	err := r.db.WithContext(ctx).Where("delivery_id = ?", deliveryID).Order("attempted_at, id").Find(&attempts).Error
+	return attempts, translate(err)
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"greenbone-task/controllers"
+	"greenbone-task/middlewares"
+)
+
+func Admin(router *gin.RouterGroup) {
+	admin := router.Group("/admin", middlewares.AuthMiddleware(), middlewares.AdminMiddleware())
+	{
+		admin.GET("/log-level", controllers.GetLogLevel)
+		admin.PUT("/log-level", controllers.SetLogLevel)
+		admin.GET("/config", controllers.GetConfig)
+	}
+}
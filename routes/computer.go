@@ -11,28 +11,46 @@ func Computer(router *gin.RouterGroup) {
 	{
 		auth.POST(
 			"/computers",
-			middlewares.JWTMiddleware(),
+			middlewares.AuthMiddleware(),
 			controllers.CreateComputer,
 		)
+		auth.POST(
+			"/computers/import",
+			middlewares.AuthMiddleware(),
+			controllers.ImportComputers,
+		)
 		auth.GET(
 			"/computers",
-			middlewares.JWTMiddleware(),
+			middlewares.AuthMiddleware(),
 			controllers.GetAllComputers,
 		)
 		auth.GET(
 			"/computers/:computer_id",
-			middlewares.JWTMiddleware(),
+			middlewares.AuthMiddleware(),
 			controllers.GetComputerByID,
 		)
 		auth.PUT(
 			"/computers/:computer_id/:employee_abbrev",
-			middlewares.JWTMiddleware(),
+			middlewares.AuthMiddleware(),
 			controllers.UpdateComputer,
 		)
 		auth.DELETE(
 			"/computers/:computer_id",
-			middlewares.JWTMiddleware(),
+			middlewares.AuthMiddleware(),
 			controllers.DeleteComputer,
 		)
 	}
 }
+
+// ComputerV2 registers the computer endpoints of /v2, which return DTOs.
+func ComputerV2(router *gin.RouterGroup) {
+	auth := router.Group("/computers", middlewares.AuthMiddleware())
+	{
+		auth.POST("", controllers.CreateComputerV2)
+		auth.POST("/import", controllers.ImportComputersV2)
+		auth.GET("", controllers.ListComputersV2)
+		auth.GET("/:computer_id", controllers.GetComputerV2)
+		auth.PUT("/:computer_id/:employee_abbrev", controllers.AssignComputerV2)
+		auth.DELETE("/:computer_id", controllers.DeleteComputerV2)
+	}
+}
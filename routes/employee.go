@@ -12,18 +12,29 @@ func Employee(router *gin.RouterGroup) {
 
 		auth.POST(
 			"/employees/",
-			middlewares.JWTMiddleware(),
+			middlewares.AuthMiddleware(),
 			controllers.CreateEmployee,
 		)
 		auth.GET(
 			"/employees/computers/:employee_abbrev",
-			middlewares.JWTMiddleware(),
+			middlewares.AuthMiddleware(),
 			controllers.GetEmployeeComputers,
 		)
 		auth.DELETE(
 			"/employees/computers/:computer_id/:employee_abbrev",
-			middlewares.JWTMiddleware(),
+			middlewares.AuthMiddleware(),
 			controllers.DeleteEmployeeComputer,
 		)
 	}
 }
+
+// EmployeeV2 registers the employee endpoints of /v2, which return DTOs.
+func EmployeeV2(router *gin.RouterGroup) {
+	auth := router.Group("/employees", middlewares.AuthMiddleware())
+	{
+		auth.POST("", controllers.CreateEmployeeV2)
+		auth.GET("/:employee_abbrev", controllers.GetEmployeeV2)
+		auth.GET("/:employee_abbrev/computers", controllers.ListEmployeeComputersV2)
+		auth.DELETE("/:employee_abbrev/computers/:computer_id", controllers.DeleteEmployeeComputerV2)
+	}
+}
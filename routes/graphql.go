@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"greenbone-task/controllers"
+	"greenbone-task/middlewares"
+)
+
+// GraphQL registers the GraphQL endpoint. It is authenticated like the REST
+// routes; read-only clients may send queries with either method, and their
+// mutations are refused field by field.
+func GraphQL(router *gin.Engine) {
+	router.GET("/graphql", middlewares.QueryAuthMiddleware(), controllers.GraphQL)
+	router.POST("/graphql", middlewares.QueryAuthMiddleware(), controllers.GraphQL)
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"greenbone-task/controllers"
+)
+
+// Health registers the unauthenticated liveness and readiness probes.
+func Health(router *gin.Engine) {
+	router.GET("/healthz", controllers.Healthz)
+	router.GET("/readyz", controllers.Readyz)
+}
@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"greenbone-task/metrics"
+)
+
+// Metrics registers the unauthenticated Prometheus scrape endpoint.
+func Metrics(router *gin.Engine) {
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+}
@@ -2,9 +2,6 @@ package routes
 
 import (
 	"github.com/gin-gonic/gin"
-	swaggerfiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
-	"greenbone-task/docs"
 	"greenbone-task/middlewares"
 	"greenbone-task/models"
 	"greenbone-task/services"
@@ -15,21 +12,36 @@ func New() *gin.Engine {
 	r := gin.New()
 	initRoute(r)
 
-	r.Use(gin.LoggerWithWriter(middlewares.LogWriter()))
+	r.Use(middlewares.TracingMiddleware())
+	r.Use(middlewares.MetricsMiddleware())
+	r.Use(middlewares.RequestIDMiddleware())
+	r.Use(middlewares.AccessLogger(middlewares.LogWriter()))
 	r.Use(gin.CustomRecovery(middlewares.AppRecovery()))
+	r.Use(middlewares.ErrorMiddleware())
+	r.Use(middlewares.SecurityHeadersMiddleware())
 	r.Use(middlewares.CORSMiddleware())
+	r.Use(middlewares.RateLimitMiddleware())
+
+	Health(r)
+	Metrics(r)
+	Docs(r)
+	GraphQL(r)
 
 	v1 := r.Group("/v1")
 	{
 		AuthRoute(v1)
 		Computer(v1)
 		Employee(v1)
-
+		Admin(v1)
+		Webhook(v1)
 	}
 
-	docs.SwaggerInfo.BasePath = v1.BasePath()
+	v2 := r.Group("/v2")
+	{
+		ComputerV2(v2)
+		EmployeeV2(v2)
+	}
 
-	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
 	return r
 }
 
@@ -39,11 +51,15 @@ func initRoute(r *gin.Engine) {
 	r.HandleMethodNotAllowed = true
 
 	r.NoRoute(func(c *gin.Context) {
-		models.SendErrorResponse(c, http.StatusNotFound, c.Request.RequestURI+" not found")
+		problem := models.NewProblem(http.StatusNotFound, "route_not_found", c.Request.URL.Path+" not found")
+		problem.RequestID = c.GetString(middlewares.RequestIDKey)
+		models.SendProblem(c, problem)
 	})
 
 	r.NoMethod(func(c *gin.Context) {
-		models.SendErrorResponse(c, http.StatusMethodNotAllowed, c.Request.Method+" is not allowed here")
+		problem := models.NewProblem(http.StatusMethodNotAllowed, "method_not_allowed", c.Request.Method+" is not allowed here")
+		problem.RequestID = c.GetString(middlewares.RequestIDKey)
+		models.SendProblem(c, problem)
 	})
 }
 
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"greenbone-task/controllers"
+	"greenbone-task/middlewares"
+)
+
+func Webhook(router *gin.RouterGroup) {
+	webhooks := router.Group("/webhooks", middlewares.AuthMiddleware(), middlewares.AdminMiddleware())
+	{
+		webhooks.POST("", controllers.CreateWebhook)
+		webhooks.GET("", controllers.GetWebhooks)
+		webhooks.GET("/:webhook_id", controllers.GetWebhook)
+		webhooks.PUT("/:webhook_id", controllers.UpdateWebhook)
+		webhooks.DELETE("/:webhook_id", controllers.DeleteWebhook)
+		webhooks.GET("/:webhook_id/deliveries", controllers.GetWebhookDeliveries)
+		webhooks.GET("/:webhook_id/deliveries/:delivery_id", controllers.GetWebhookDelivery)
+		webhooks.POST("/:webhook_id/deliveries/:delivery_id/replay", controllers.ReplayWebhookDelivery)
+	}
+}
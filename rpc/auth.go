@@ -0,0 +1,69 @@
+package rpc
+
+import (
+	"context"
+	"crypto/x509"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"greenbone-task/constants"
+	"greenbone-task/logger"
+	db "greenbone-task/models/db"
+	inventoryv1 "greenbone-task/proto/inventory/v1"
+	"greenbone-task/services"
+)
+
+// TokenMetadataKey is the metadata key of the access token, like the
+// Bearer-Token header of the REST API.
+const TokenMetadataKey = "bearer-token"
+
+// readOnlyMethods are the methods that read-only client certificates may call.
+var readOnlyMethods = map[string]bool{
+	inventoryv1.InventoryService_GetEmployee_FullMethodName:     true,
+	inventoryv1.InventoryService_ListEmployees_FullMethodName:   true,
+	inventoryv1.InventoryService_GetComputer_FullMethodName:     true,
+	inventoryv1.InventoryService_ListComputers_FullMethodName:   true,
+	inventoryv1.InventoryService_ListAssignments_FullMethodName: true,
+}
+
+// authInterceptor authenticates a call like the REST AuthMiddleware: with a
+// verified client certificate if the client sent one over mutual TLS, and
+// otherwise with the access token in the bearer-token metadata.
+func authInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if certificate := verifiedClientCertificate(ctx); certificate != nil {
+		identity, err := services.ClientIdentity(certificate)
+		if err != nil {
+			return nil, statusFor(ctx, err)
+		}
+		if identity.Role == constants.RoleReadOnly && !readOnlyMethods[info.FullMethod] {
+			return nil, statusFor(ctx, services.ForbiddenError(services.CodeInsufficientRole, "client %s may only read", identity.Name))
+		}
+		return handler(logger.With(ctx, zap.String("client", identity.Name)), req)
+	}
+
+	var token string
+	if values := metadata.ValueFromIncomingContext(ctx, TokenMetadataKey); len(values) > 0 {
+		token = values[0]
+	}
+	tokenModel, err := services.VerifyToken(ctx, token, db.TokenTypeAccess)
+	if err != nil {
+		return nil, statusFor(ctx, err)
+	}
+	return handler(logger.With(ctx, zap.Int64("user_id", tokenModel.ID)), req)
+}
+
+// verifiedClientCertificate returns the client certificate that the TLS
+// handshake verified against the client CA, if any.
+func verifiedClientCertificate(ctx context.Context) *x509.Certificate {
+	client, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	info, ok := client.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return info.State.VerifiedChains[0][0]
+}
@@ -0,0 +1,73 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"greenbone-task/logger"
+	"greenbone-task/services"
+	"sort"
+)
+
+// ErrorDomain is the domain of the ErrorInfo details of the statuses. Their
+// reason is the stable code of the error, as in the problems of REST.
+const ErrorDomain = "inventory.greenbone.net"
+
+// codeByKind maps the kinds of domain errors to gRPC status codes.
+var codeByKind = map[services.ErrorKind]codes.Code{
+	services.KindNotFound:      codes.NotFound,
+	services.KindConflict:      codes.AlreadyExists,
+	services.KindValidation:    codes.InvalidArgument,
+	services.KindQuotaExceeded: codes.ResourceExhausted,
+	services.KindUpstream:      codes.Unavailable,
+	services.KindUnauthorized:  codes.Unauthenticated,
+	services.KindForbidden:     codes.PermissionDenied,
+}
+
+// statusFor maps err to a status. Domain errors keep their message and their
+// code in an ErrorInfo, and the problems with the fields of a validation
+// error are sent as a BadRequest. Any other error is an internal error.
+func statusFor(ctx context.Context, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	domainErr, ok := services.AsError(err)
+	if !ok {
+		logger.FromContext(ctx).Error("call failed", zap.Error(err))
+		return status.Error(codes.Internal, "the server could not process the request")
+	}
+
+	code, ok := codeByKind[domainErr.Kind]
+	if !ok {
+		code = codes.Internal
+	}
+	if code == codes.Internal || code == codes.Unavailable {
+		logger.FromContext(ctx).Error("call failed", zap.String("code", domainErr.Code), zap.Error(err))
+	}
+
+	st, detailErr := status.New(code, domainErr.Message).WithDetails(&errdetails.ErrorInfo{Reason: domainErr.Code, Domain: ErrorDomain})
+	if detailErr != nil {
+		return status.Error(code, domainErr.Message)
+	}
+	var fieldErrs validation.Errors
+	if errors.As(domainErr, &fieldErrs) {
+		fields := make([]string, 0, len(fieldErrs))
+		for field := range fieldErrs {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		badRequest := &errdetails.BadRequest{}
+		for _, field := range fields {
+			badRequest.FieldViolations = append(badRequest.FieldViolations,
+				&errdetails.BadRequest_FieldViolation{Field: field, Description: fieldErrs[field].Error()})
+		}
+		if withFields, err := st.WithDetails(badRequest); err == nil {
+			st = withFields
+		}
+	}
+	return st.Err()
+}
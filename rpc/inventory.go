@@ -0,0 +1,224 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"greenbone-task/constants"
+	"greenbone-task/models"
+	db "greenbone-task/models/db"
+	inventoryv1 "greenbone-task/proto/inventory/v1"
+	"greenbone-task/services"
+	"time"
+)
+
+// inventoryServer implements the inventory service with the service layer.
+type inventoryServer struct {
+	inventoryv1.UnimplementedInventoryServiceServer
+}
+
+func (s *inventoryServer) CreateEmployee(ctx context.Context, req *inventoryv1.CreateEmployeeRequest) (*inventoryv1.CreateEmployeeResponse, error) {
+	request := models.EmployeeRequest{
+		FirstName:    req.GetFirstName(),
+		LastName:     req.GetLastName(),
+		Email:        req.GetEmail(),
+		Abbreviation: req.GetAbbreviation(),
+	}
+	if err := models.ValidateEmployeeRequest(request); err != nil {
+		return nil, invalidRequest(err)
+	}
+
+	if err := services.CreateEmployee(ctx, request); err != nil {
+		return nil, err
+	}
+	employee, err := services.FindByEmployeeAbbrev(ctx, request.Abbreviation)
+	if err != nil {
+		return nil, err
+	}
+	return &inventoryv1.CreateEmployeeResponse{Employee: employeeMessage(employee)}, nil
+}
+
+func (s *inventoryServer) GetEmployee(ctx context.Context, req *inventoryv1.GetEmployeeRequest) (*inventoryv1.GetEmployeeResponse, error) {
+	employee, err := services.FindByEmployeeAbbrev(ctx, req.GetAbbreviation())
+	if err != nil {
+		return nil, err
+	}
+	return &inventoryv1.GetEmployeeResponse{Employee: employeeMessage(employee)}, nil
+}
+
+func (s *inventoryServer) ListEmployees(ctx context.Context, _ *inventoryv1.ListEmployeesRequest) (*inventoryv1.ListEmployeesResponse, error) {
+	employees, err := services.GetAllEmployees(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp := &inventoryv1.ListEmployeesResponse{Employees: make([]*inventoryv1.Employee, len(employees))}
+	for i, employee := range employees {
+		resp.Employees[i] = employeeMessage(employee)
+	}
+	return resp, nil
+}
+
+func (s *inventoryServer) CreateComputer(ctx context.Context, req *inventoryv1.CreateComputerRequest) (*inventoryv1.CreateComputerResponse, error) {
+	computer := db.Computer{
+		MacAddress:     req.GetMacAddress(),
+		ComputerName:   req.GetName(),
+		IPAddress:      req.GetIpAddress(),
+		EmployeeAbbrev: req.GetEmployeeAbbreviation(),
+		Description:    req.GetDescription(),
+	}
+	if err := models.ValidateComputerRequest(computer); err != nil {
+		return nil, invalidRequest(err)
+	}
+
+	id, err := services.CreateComputer(ctx, computer)
+	if err != nil {
+		return nil, err
+	}
+	created, err := services.GetComputerByID(ctx, int64(id))
+	if err != nil {
+		return nil, err
+	}
+	return &inventoryv1.CreateComputerResponse{Computer: computerMessage(*created)}, nil
+}
+
+func (s *inventoryServer) GetComputer(ctx context.Context, req *inventoryv1.GetComputerRequest) (*inventoryv1.GetComputerResponse, error) {
+	id, err := computerID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	computer, err := services.GetComputerByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &inventoryv1.GetComputerResponse{Computer: computerMessage(*computer)}, nil
+}
+
+func (s *inventoryServer) ListComputers(ctx context.Context, req *inventoryv1.ListComputersRequest) (*inventoryv1.ListComputersResponse, error) {
+	page, pageSize := int(req.GetPage()), int(req.GetPageSize())
+	if page == 0 {
+		page = 1
+	}
+	if pageSize == 0 {
+		pageSize = constants.DefaultPageSize
+	}
+	problems := validation.Errors{}
+	if page < 1 {
+		problems["page"] = errors.New("must be a positive integer")
+	}
+	if pageSize < 1 || pageSize > constants.MaxPageSize {
+		problems["page_size"] = fmt.Errorf("must be an integer from 1 to %d", constants.MaxPageSize)
+	}
+	if len(problems) > 0 {
+		return nil, services.ValidationError(services.CodeValidationFailed, problems, "invalid pagination")
+	}
+
+	computers, total, err := services.ListComputers(ctx, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	resp := &inventoryv1.ListComputersResponse{Computers: make([]*inventoryv1.Computer, len(computers)), Total: total}
+	for i, computer := range computers {
+		resp.Computers[i] = computerMessage(computer)
+	}
+	return resp, nil
+}
+
+func (s *inventoryServer) AssignComputer(ctx context.Context, req *inventoryv1.AssignComputerRequest) (*inventoryv1.AssignComputerResponse, error) {
+	id, err := computerID("computer_id", req.GetComputerId())
+	if err != nil {
+		return nil, err
+	}
+	computer, err := services.AssignComputerToEmployee(ctx, id, req.GetEmployeeAbbreviation())
+	if err != nil {
+		return nil, err
+	}
+	return &inventoryv1.AssignComputerResponse{Computer: computerMessage(*computer)}, nil
+}
+
+func (s *inventoryServer) DeleteComputer(ctx context.Context, req *inventoryv1.DeleteComputerRequest) (*inventoryv1.DeleteComputerResponse, error) {
+	id, err := computerID("id", req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := services.DeleteComputer(ctx, id); err != nil {
+		return nil, err
+	}
+	return &inventoryv1.DeleteComputerResponse{}, nil
+}
+
+func (s *inventoryServer) ListAssignments(ctx context.Context, req *inventoryv1.ListAssignmentsRequest) (*inventoryv1.ListAssignmentsResponse, error) {
+	assignments, err := services.FindAssignments(ctx, services.AssignmentFilter{
+		ComputerID:     uint(req.GetComputerId()),
+		EmployeeAbbrev: req.GetEmployeeAbbreviation(),
+		CurrentOnly:    req.GetCurrentOnly(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := &inventoryv1.ListAssignmentsResponse{Assignments: make([]*inventoryv1.Assignment, len(assignments))}
+	for i, assignment := range assignments {
+		resp.Assignments[i] = assignmentMessage(assignment)
+	}
+	return resp, nil
+}
+
+func employeeMessage(employee db.Employee) *inventoryv1.Employee {
+	return &inventoryv1.Employee{
+		Id:           uint64(employee.ID),
+		FirstName:    employee.FirstName,
+		LastName:     employee.LastName,
+		Email:        employee.Email,
+		Abbreviation: employee.Abbreviation,
+		CreatedAt:    timestamp(employee.CreatedAt),
+		UpdatedAt:    timestamp(employee.UpdatedAt),
+	}
+}
+
+func computerMessage(computer db.Computer) *inventoryv1.Computer {
+	return &inventoryv1.Computer{
+		Id:                   uint64(computer.ID),
+		MacAddress:           computer.MacAddress,
+		Name:                 computer.ComputerName,
+		IpAddress:            computer.IPAddress,
+		EmployeeAbbreviation: computer.EmployeeAbbrev,
+		Description:          computer.Description,
+		CreatedAt:            timestamp(computer.CreatedAt),
+		UpdatedAt:            timestamp(computer.UpdatedAt),
+	}
+}
+
+func assignmentMessage(assignment db.Assignment) *inventoryv1.Assignment {
+	message := &inventoryv1.Assignment{
+		Id:         uint64(assignment.ID),
+		ComputerId: uint64(assignment.ComputerID),
+		EmployeeId: uint64(assignment.EmployeeID),
+		AssignedAt: timestamp(assignment.AssignedAt),
+	}
+	if assignment.UnassignedAt != nil {
+		message.UnassignedAt = timestamp(*assignment.UnassignedAt)
+	}
+	return message
+}
+
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// computerID checks the ID of a computer in the field of a request.
+func computerID(field string, id uint64) (int64, error) {
+	if id == 0 || id > 1<<63-1 {
+		return 0, services.ValidationError(services.CodeValidationFailed,
+			validation.Errors{field: errors.New("must be a positive integer")}, "invalid computer ID %d", id)
+	}
+	return int64(id), nil
+}
+
+// invalidRequest reports a request that fails validation.
+func invalidRequest(err error) error {
+	return services.ValidationError(services.CodeValidationFailed, err, "invalid request")
+}
@@ -0,0 +1,64 @@
+// Package rpc serves the inventory over gRPC. It shares the service layer
+// and the authentication with the REST API and maps the domain errors to
+// gRPC status codes.
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"greenbone-task/logger"
+	inventoryv1 "greenbone-task/proto/inventory/v1"
+)
+
+//go:generate buf generate --template ../proto/buf.gen.yaml ../proto
+
+// NewServer returns a gRPC server with the inventory service registered.
+// Every call is authenticated, and a panic fails the call instead of the
+// process.
+func NewServer(options ...grpc.ServerOption) *grpc.Server {
+	options = append(options, grpc.ChainUnaryInterceptor(recoverInterceptor, authInterceptor, errorInterceptor))
+	server := grpc.NewServer(options...)
+	inventoryv1.RegisterInventoryServiceServer(server, &inventoryServer{})
+	return server
+}
+
+// Stop stops server gracefully, letting the running calls finish, unless ctx
+// ends first.
+func Stop(ctx context.Context, server *grpc.Server) error {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		server.Stop()
+		return ctx.Err()
+	}
+}
+
+// recoverInterceptor turns a panic of a handler into an internal error.
+func recoverInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (_ any, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			logger.FromContext(ctx).Error("panic recovered", zap.String("method", info.FullMethod), zap.String("panic", fmt.Sprint(recovered)), zap.Stack("stack"))
+			err = status.Error(codes.Internal, "the server could not process the request")
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// errorInterceptor maps the errors of the handlers to statuses.
+func errorInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, statusFor(ctx, err)
+	}
+	return resp, nil
+}
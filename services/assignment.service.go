@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"go.opentelemetry.io/otel/attribute"
+	db "greenbone-task/models/db"
+	"greenbone-task/tracing"
+)
+
+// AssignmentFilter selects assignments from the history. Zero fields match
+// every assignment.
+type AssignmentFilter struct {
+	ComputerID     uint
+	EmployeeAbbrev string
+	CurrentOnly    bool
+}
+
+// FindAssignments returns the assignments of the history matching filter in
+// the order they started
+func FindAssignments(ctx context.Context, filter AssignmentFilter) (_ []db.Assignment, err error) {
+	ctx, span := tracing.Start(ctx, "services.FindAssignments")
+	defer tracing.End(span, &err)
+
+	var assignments []db.Assignment
+	switch {
+	case filter.EmployeeAbbrev != "":
+		var employee *db.Employee
+		if employee, err = findEmployee(ctx, Store, filter.EmployeeAbbrev); err != nil {
+			return nil, err
+		}
+		assignments, err = Store.Assignments().FindByEmployeeIDs(ctx, []uint{employee.ID})
+	case filter.ComputerID != 0:
+		assignments, err = Store.Assignments().FindByComputerIDs(ctx, []uint{filter.ComputerID})
+	default:
+		assignments, err = Store.Assignments().FindAll(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error finding assignments: %w", err)
+	}
+
+	matching := assignments[:0]
+	for _, assignment := range assignments {
+		if filter.ComputerID != 0 && assignment.ComputerID != filter.ComputerID {
+			continue
+		}
+		if filter.CurrentOnly && assignment.UnassignedAt != nil {
+			continue
+		}
+		matching = append(matching, assignment)
+	}
+	return matching, nil
+}
+
+// AssignmentsOfComputers returns the assignment history of each computer
+func AssignmentsOfComputers(ctx context.Context, computerIDs []uint) (_ map[uint][]db.Assignment, err error) {
+	ctx, span := tracing.Start(ctx, "services.AssignmentsOfComputers", attribute.Int("computer.count", len(computerIDs)))
+	defer tracing.End(span, &err)
+
+	assignments, err := Store.Assignments().FindByComputerIDs(ctx, computerIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error finding assignments: %w", err)
+	}
+	byComputer := make(map[uint][]db.Assignment, len(computerIDs))
+	for _, assignment := range assignments {
+		byComputer[assignment.ComputerID] = append(byComputer[assignment.ComputerID], assignment)
+	}
+	return byComputer, nil
+}
+
+// AssignmentsOfEmployees returns the assignment history of each employee
+func AssignmentsOfEmployees(ctx context.Context, employeeIDs []uint) (_ map[uint][]db.Assignment, err error) {
+	ctx, span := tracing.Start(ctx, "services.AssignmentsOfEmployees", attribute.Int("employee.count", len(employeeIDs)))
+	defer tracing.End(span, &err)
+
+	assignments, err := Store.Assignments().FindByEmployeeIDs(ctx, employeeIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error finding assignments: %w", err)
+	}
+	byEmployee := make(map[uint][]db.Assignment, len(employeeIDs))
+	for _, assignment := range assignments {
+		byEmployee[assignment.EmployeeID] = append(byEmployee[assignment.EmployeeID], assignment)
+	}
+	return byEmployee, nil
+}
+
+// AssigneesOfComputers returns the ID of the employee each assigned computer
+// is assigned to
+func AssigneesOfComputers(ctx context.Context, computerIDs []uint) (_ map[uint]uint, err error) {
+	ctx, span := tracing.Start(ctx, "services.AssigneesOfComputers", attribute.Int("computer.count", len(computerIDs)))
+	defer tracing.End(span, &err)
+
+	assignments, err := Store.Computers().FindAssignments(ctx, computerIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error finding assignments: %w", err)
+	}
+	assignees := make(map[uint]uint, len(assignments))
+	for _, assignment := range assignments {
+		assignees[assignment.ComputerID] = assignment.EmployeeID
+	}
+	return assignees, nil
+}
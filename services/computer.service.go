@@ -1,199 +1,313 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/go-redis/redis/v8"
-	"github.com/jinzhu/gorm"
-	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
-	"golang.org/x/net/context"
+	"greenbone-task/constants"
 	"greenbone-task/logger"
+	"greenbone-task/metrics"
 	db "greenbone-task/models/db"
-	"reflect"
-	"time"
+	"greenbone-task/repositories"
+	"greenbone-task/tracing"
 )
 
-// CreateComputer function creates a new computer and assigns it to an employee.
-func CreateComputer(computer db.Computer) (uint, error) {
-	// Store computer details in database
-	if err := DbConnection.Create(&computer).Error; err != nil {
-		logger.Error("failed to save computer", zap.Error(err))
-		return 0, err
+// computerQuota returns the configured COMPUTER_QUOTA.
+func computerQuota() int64 {
+	if config := LiveConfig(); config != nil && config.ComputerQuota > 0 {
+		return int64(config.ComputerQuota)
 	}
+	return constants.DefaultComputerQuota
+}
 
-	// check if the employee exists
-	// Assign computer to employee
-	employee, err := FindByEmployeeAbbrev(computer.EmployeeAbbrev)
+// CreateComputer function creates a new computer and assigns it to an employee.
+func CreateComputer(ctx context.Context, computer db.Computer) (_ uint, err error) {
+	ctx, span := tracing.Start(ctx, "services.CreateComputer", attribute.String("employee.abbreviation", computer.EmployeeAbbrev))
+	defer tracing.End(span, &err)
+
+	err = runUnitOfWork(ctx, func(uow *unitOfWork) error {
+		return createComputer(ctx, uow, &computer)
+	})
 	if err != nil {
-		logger.Error("failed to assign computer to employee", zap.Error(err))
-		return 0, fmt.Errorf("error assigning computer to employee: %w", err)
+		return 0, err
 	}
+	return computer.ID, nil
+}
 
-	if reflect.DeepEqual(employee, db.Employee{}) {
-		logger.Error("failed to assign computer to employee", zap.Error(err))
-		return 0, fmt.Errorf("error assigning computer to employee: employee not found")
+// ImportComputers creates and assigns several computers at once. Either all
+// of them are imported or, if one fails, none is.
+func ImportComputers(ctx context.Context, computers []db.Computer) (_ []uint, err error) {
+	ctx, span := tracing.Start(ctx, "services.ImportComputers", attribute.Int("computers.count", len(computers)))
+	defer tracing.End(span, &err)
+
+	ids := make([]uint, 0, len(computers))
+	err = runUnitOfWork(ctx, func(uow *unitOfWork) error {
+		for i := range computers {
+			if err := createComputer(ctx, uow, &computers[i]); err != nil {
+				return fmt.Errorf("error importing computer %s: %w", computers[i].MacAddress, err)
+			}
+			ids = append(ids, computers[i].ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return ids, nil
+}
 
-	// check if the employee already has 3 computers assigned
-	count, err := CountComputersByEmployeeAbbreviation(computer.EmployeeAbbrev)
+// createComputer stores a computer and assigns it to its employee within uow.
+// The employee row is locked first so that concurrent creates for the same
+// employee count and assign one after another.
+func createComputer(ctx context.Context, uow *unitOfWork, computer *db.Computer) error {
+	// check if the employee exists
+	employee, err := lockEmployee(ctx, uow, computer.EmployeeAbbrev)
 	if err != nil {
-		return 0, fmt.Errorf("error assigning computer to employee: %w", err)
+		logger.FromContext(ctx).Error("failed to assign computer to employee", zap.Error(err))
+		return fmt.Errorf("error assigning computer to employee: %w", err)
 	}
-	if count >= 3 {
-		// notify system administrator about the assignment
-		ns := NewNotificationService()
-		message := fmt.Sprintf("Employee %s already has %d computers assigned.", computer.EmployeeAbbrev, count)
-		err := ns.NotifySystemAdministrator(employee.Abbreviation, message)
-		if err != nil {
-			return 0, fmt.Errorf("error assigning computer to employee: %w", err)
+
+	// Store computer details in database
+	if err := uow.Computers().Create(ctx, computer); err != nil {
+		if errors.Is(err, repositories.ErrDuplicate) {
+			return ConflictError(CodeDuplicateMacAddress, err, "a computer with MAC address %s already exists", computer.MacAddress)
 		}
+		logger.FromContext(ctx).Error("failed to save computer", zap.Error(err))
+		return err
 	}
 
-	// assign the computer to the employee
-	computer.EmployeeAbbrev = computer.EmployeeAbbrev
-	err = DbConnection.Save(computer).Error
+	// count the computers of the employee for the quota notification
+	count, err := uow.Computers().CountByEmployeeAbbrev(ctx, computer.EmployeeAbbrev)
 	if err != nil {
-		return 0, fmt.Errorf("error assigning computer to employee: %w", err)
+		return fmt.Errorf("error assigning computer to employee: %w", err)
 	}
 
 	// create an entry in the employee_computer junction table
-	err = DbConnection.Model(&employee).Association("Computers").Append(&computer).Error
+	err = uow.Computers().Assign(ctx, employee.ID, computer.ID)
 	if err != nil {
-		return 0, fmt.Errorf("error assigning computer to employee: %w", err)
+		return fmt.Errorf("error assigning computer to employee: %w", err)
 	}
 
-	return computer.ID, nil
+	return uow.Publish(ctx, ComputerCreated{Computer: *computer, EmployeeComputers: count})
 }
 
 // GetAllComputers fetch all computers information
-func GetAllComputers() ([]db.Computer, error) {
-	var computers []db.Computer
-	err := DbConnection.Find(&computers).Error
+func GetAllComputers(ctx context.Context) (_ []db.Computer, err error) {
+	ctx, span := tracing.Start(ctx, "services.GetAllComputers")
+	defer tracing.End(span, &err)
+
+	computers, err := Store.Computers().FindAll(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error getting all computers: %w", err)
 	}
 	return computers, nil
 }
 
+// ListComputers fetches a page of computers ordered by ID and the number of
+// all computers
+func ListComputers(ctx context.Context, offset int, limit int) (_ []db.Computer, _ int64, err error) {
+	ctx, span := tracing.Start(ctx, "services.ListComputers",
+		attribute.Int("page.offset", offset), attribute.Int("page.limit", limit))
+	defer tracing.End(span, &err)
+
+	computers, total, err := Store.Computers().FindPage(ctx, offset, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error listing computers: %w", err)
+	}
+	return computers, total, nil
+}
+
+// FindComputersByIDs fetches the computers with the IDs that exist
+func FindComputersByIDs(ctx context.Context, ids []uint) (_ []db.Computer, err error) {
+	ctx, span := tracing.Start(ctx, "services.FindComputersByIDs", attribute.Int("computer.count", len(ids)))
+	defer tracing.End(span, &err)
+
+	computers, err := Store.Computers().FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("error finding computers: %w", err)
+	}
+	return computers, nil
+}
+
 // GetComputerByID function get computer information from id
-func GetComputerByID(id int64) (*db.Computer, error) {
+func GetComputerByID(ctx context.Context, id int64) (_ *db.Computer, err error) {
+	ctx, span := tracing.Start(ctx, "services.GetComputerByID", attribute.Int64("computer.id", id))
+	defer tracing.End(span, &err)
+
 	// First, try to get the computer from the cache.
 	computerKey := fmt.Sprintf("computer:%d", id)
-	ctx := context.Background()
-	cachedComputer, err := GetRedisDefaultClient().Get(ctx, computerKey).Result()
-	if err == nil {
-		var computer db.Computer
-		if err := json.Unmarshal([]byte(cachedComputer), &computer); err != nil {
-			return nil, fmt.Errorf("error unmarshaling cached computer data: %w", err)
+	if cacheEnabled() {
+		cachedComputer, err := GetRedisDefaultClient().Get(ctx, computerKey).Result()
+		if err == nil || err == redis.Nil {
+			metrics.ObserveCache(metrics.CacheComputer, err == nil)
+		}
+		if err == nil {
+			var computer db.Computer
+			if err := json.Unmarshal([]byte(cachedComputer), &computer); err != nil {
+				return nil, fmt.Errorf("error unmarshaling cached computer data: %w", err)
+			}
+			return &computer, nil
+		} else if err != redis.Nil {
+			return nil, UpstreamError(CodeCacheUnavailable, err, "error getting computer from Redis cache")
 		}
-		return &computer, nil
-	} else if err != redis.Nil {
-		return nil, fmt.Errorf("error getting computer from Redis cache: %w", err)
 	}
 
 	// If the computer is not in the cache, query it from the database.
-	var computer db.Computer
-	err = DbConnection.Where("id = ?", id).First(&computer).Error
+	computer, err := Store.Computers().FindByID(ctx, uint(id))
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("no computer found with ID: %d", id)
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, NotFoundError(CodeComputerNotFound, "no computer found with ID: %d", id)
 		}
 		return nil, fmt.Errorf("error getting computer by ID: %w", err)
 	}
 
 	// Store the computer in the cache.
-	computerJSON, err := json.Marshal(computer)
-	if err != nil {
-		return nil, fmt.Errorf("error marshaling computer data: %w", err)
-	}
-	if err := GetRedisDefaultClient().Set(ctx, computerKey, computerJSON, time.Minute).Err(); err != nil {
-		return nil, fmt.Errorf("error setting computer in Redis cache: %w", err)
+	if cacheEnabled() {
+		computerJSON, err := json.Marshal(computer)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling computer data: %w", err)
+		}
+		if err := GetRedisDefaultClient().Set(ctx, computerKey, computerJSON, Config.ComputerCacheTTL).Err(); err != nil {
+			return nil, UpstreamError(CodeCacheUnavailable, err, "error setting computer in Redis cache")
+		}
 	}
 
-	return &computer, nil
+	return computer, nil
 }
 
 // DeleteComputer delete computer from the database from computer id
-func DeleteComputer(id int64) error {
-	err := DbConnection.Delete(&db.Computer{}, id).Error
+func DeleteComputer(ctx context.Context, id int64) (err error) {
+	ctx, span := tracing.Start(ctx, "services.DeleteComputer", attribute.Int64("computer.id", id))
+	defer tracing.End(span, &err)
+
+	err = runUnitOfWork(ctx, func(uow *unitOfWork) error {
+		return deleteComputer(ctx, uow, uint(id))
+	})
 	if err != nil {
 		return fmt.Errorf("error deleting computer: %w", err)
 	}
 	return nil
 }
 
-// AssignComputerToEmployee assign employee computer to another employee
-func AssignComputerToEmployee(computerID int64, newEmployeeAbbreviation string) error {
-	// Get the computer record by ID
-	computer, err := GetComputerByID(computerID)
+// deleteComputer removes a computer together with its assignment within uow.
+func deleteComputer(ctx context.Context, uow *unitOfWork, id uint) error {
+	computer, err := uow.Computers().FindByID(ctx, id)
 	if err != nil {
-		return fmt.Errorf("error getting computer by ID: %w", err)
+		if errors.Is(err, repositories.ErrNotFound) {
+			return NotFoundError(CodeComputerNotFound, "no computer found with ID: %d", id)
+		}
+		return err
 	}
-	if computer == nil {
-		return fmt.Errorf("computer not found with ID %d", computerID)
+	if err := uow.Computers().Unassign(ctx, id); err != nil {
+		return err
 	}
-
-	// Get the new employee record by abbreviation
-	newEmployee, err := FindByEmployeeAbbrev(newEmployeeAbbreviation)
-	if err != nil {
-		return fmt.Errorf("error getting employee by abbreviation: %w", err)
-	}
-	if reflect.DeepEqual(newEmployee, db.Employee{}) {
-		return fmt.Errorf("employee not found with abbreviation %s", newEmployeeAbbreviation)
+	if err := uow.Computers().Delete(ctx, id); err != nil {
+		return err
 	}
+	return uow.Publish(ctx, ComputerDeleted{Computer: *computer})
+}
 
-	// Check if the record already exists in the employee_computers table
-	var existingEmployeeComputer db.EmployeeComputer
-	err = DbConnection.Where("computer_id = ?", computer.ID).First(&existingEmployeeComputer).Error
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		return fmt.Errorf("error checking employee_computers table: %w", err)
-	}
+// AssignComputerToEmployee assign employee computer to another employee and
+// returns the computer as assigned
+func AssignComputerToEmployee(ctx context.Context, computerID int64, newEmployeeAbbreviation string) (_ *db.Computer, err error) {
+	ctx, span := tracing.Start(ctx, "services.AssignComputerToEmployee",
+		attribute.Int64("computer.id", computerID), attribute.String("employee.abbreviation", newEmployeeAbbreviation))
+	defer tracing.End(span, &err)
 
-	// If the existing record has the same employee ID as the new employee, there's nothing to do
-	if existingEmployeeComputer.EmployeeID == newEmployee.ID {
-		return nil
-	}
+	var computer *db.Computer
+	err = runUnitOfWork(ctx, func(uow *unitOfWork) error {
+		// Get the computer record by ID
+		var err error
+		computer, err = uow.Computers().FindByID(ctx, uint(computerID))
+		if errors.Is(err, repositories.ErrNotFound) {
+			return NotFoundError(CodeComputerNotFound, "computer not found with ID %d", computerID)
+		}
+		if err != nil {
+			return fmt.Errorf("error getting computer by ID: %w", err)
+		}
 
-	// If the existing record has a different employee ID, update it with the new employee ID
-	if existingEmployeeComputer.EmployeeID != 0 {
-		existingEmployeeComputer.EmployeeID = newEmployee.ID
-		err = DbConnection.Save(&existingEmployeeComputer).Error
+		// Get and lock the new employee record by abbreviation
+		newEmployee, err := lockEmployee(ctx, uow, newEmployeeAbbreviation)
 		if err != nil {
-			return fmt.Errorf("error updating employee_computers record: %w", err)
+			return err
 		}
-	} else {
-		// If the record does not exist, insert a new record in the employee_computers junction table
-		newEmployeeComputer := &db.EmployeeComputer{
-			EmployeeID: newEmployee.ID,
-			ComputerID: computer.ID,
+
+		// Check if the record already exists in the employee_computers table
+		existingEmployeeComputer, err := uow.Computers().FindAssignment(ctx, computer.ID)
+		if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+			return fmt.Errorf("error checking employee_computers table: %w", err)
+		}
+
+		// If the existing record has the same employee ID as the new employee, there's nothing to do
+		if existingEmployeeComputer != nil && existingEmployeeComputer.EmployeeID == newEmployee.ID {
+			return nil
 		}
-		err = DbConnection.Create(newEmployeeComputer).Error
+
+		// Insert a new record in the employee_computers junction table, or update the existing one
+		err = uow.Computers().Assign(ctx, newEmployee.ID, computer.ID)
 		if err != nil {
-			return fmt.Errorf("error inserting employee_computers record: %w", err)
+			return fmt.Errorf("error updating employee_computers record: %w", err)
 		}
-	}
 
-	return nil
+		// Keep the computer's employee abbreviation in line with the assignment
+		previousEmployeeAbbrev := computer.EmployeeAbbrev
+		computer.EmployeeAbbrev = newEmployee.Abbreviation
+		err = uow.Computers().Update(ctx, computer)
+		if err != nil {
+			return fmt.Errorf("error updating computer: %w", err)
+		}
+
+		return uow.Publish(ctx, ComputerReassigned{Computer: *computer, PreviousEmployeeAbbrev: previousEmployeeAbbrev})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return computer, nil
 }
 
 // FindByEmployeeAbbrev fetch data from employee table using abbreviation
-func FindByEmployeeAbbrev(abbrev string) (db.Employee, error) {
-	var employee db.Employee
-	if err := DbConnection.Where("abbreviation = ?", abbrev).First(&employee).Error; err != nil {
-		fmt.Println("Error finding card:", err)
-		return db.Employee{}, errors.New("failed to find computers by employee abbreviation")
+func FindByEmployeeAbbrev(ctx context.Context, abbrev string) (db.Employee, error) {
+	employee, err := findEmployee(ctx, Store, abbrev)
+	if err != nil {
+		return db.Employee{}, err
+	}
+	return *employee, nil
+}
+
+// findEmployee looks up an employee by abbreviation in the given store.
+func findEmployee(ctx context.Context, store repositories.Store, abbrev string) (*db.Employee, error) {
+	employee, err := store.Employees().FindByAbbrev(ctx, abbrev)
+	if err != nil {
+		return nil, employeeLookupError(ctx, abbrev, err)
 	}
 
 	return employee, nil
 }
 
-// CountComputersByEmployeeAbbreviation count no of computer assign to employee
-func CountComputersByEmployeeAbbreviation(abbreviation string) (int64, error) {
-	var count int64
-	result := DbConnection.Model(&db.Computer{}).Where("employee_abbrev = ?", abbreviation).Count(&count)
-	if result.Error != nil {
-		return 0, result.Error
+// lockEmployee looks up an employee and locks it for the rest of the transaction.
+func lockEmployee(ctx context.Context, uow *unitOfWork, abbrev string) (*db.Employee, error) {
+	employee, err := uow.Employees().LockByAbbrev(ctx, abbrev)
+	if err != nil {
+		return nil, employeeLookupError(ctx, abbrev, err)
 	}
-	return count, nil
+
+	return employee, nil
+}
+
+// employeeLookupError turns a failed employee lookup into a domain error.
+func employeeLookupError(ctx context.Context, abbrev string, err error) error {
+	if errors.Is(err, repositories.ErrNotFound) {
+		return NotFoundError(CodeEmployeeNotFound, "no employee found with abbreviation %s", abbrev)
+	}
+	logger.FromContext(ctx).Error("failed to find employee", zap.String("abbreviation", abbrev), zap.Error(err))
+	return fmt.Errorf("error finding employee: %w", err)
+}
+
+// CountComputersByEmployeeAbbreviation count no of computer assign to employee
+func CountComputersByEmployeeAbbreviation(ctx context.Context, abbreviation string) (int64, error) {
+	return Store.Computers().CountByEmployeeAbbrev(ctx, abbreviation)
 }
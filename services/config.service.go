@@ -1,30 +1,349 @@
 package services
 
 import (
+	"errors"
+	"fmt"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/cast"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"greenbone-task/constants"
 	"greenbone-task/models"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
 )
 
 var Config *models.EnvConfig
 
-func LoadConfig() {
+// configArgs are the command-line arguments the configuration was loaded
+// with, and configCommandFlags the flags of the command, to read it again on
+// reload.
+var configArgs []string
+var configCommandFlags []*pflag.FlagSet
+
+// configFiles are the files the configuration was read from.
+var configFiles []string
+
+// ConfigFileEnv names a YAML or TOML configuration file if --config is not given.
+const ConfigFileEnv = "CONFIG_FILE"
+
+// dotenvFile is read, if it exists, before the configuration file.
+const dotenvFile = ".env.local"
+
+// ConfigError lists every problem found while loading the configuration.
+type ConfigError struct {
+	Problems []string
+}
+
+func (e *ConfigError) Error() string {
+	return "invalid configuration: " + strings.Join(e.Problems, "; ")
+}
+
+// LoadConfig loads the configuration and returns the positional arguments
+// left after the flags. Each layer overrides the previous one:
+//
+//  1. the defaults
+//  2. .env.local in the working directory
+//  3. the YAML or TOML file given with --config or CONFIG_FILE
+//  4. the environment, where <KEY>_FILE reads the value of KEY from a file
+//  5. the command-line flags, e.g. --server-port for SERVER_PORT
+//
+// commandFlags are parsed from args together with the configuration flags,
+// for subcommands that take flags of their own. Invalid configurations are
+// reported with a *ConfigError.
+func LoadConfig(args []string, commandFlags ...*pflag.FlagSet) ([]string, error) {
+	config, rest, files, err := readConfig(args, commandFlags)
+	if err != nil {
+		return nil, err
+	}
+	Config = config
+	configArgs, configCommandFlags, configFiles = args, commandFlags, files
+	return rest, nil
+}
+
+// readConfig reads and validates the configuration layers. It returns the
+// configuration, the positional arguments and the files it was read from.
+func readConfig(args []string, commandFlags []*pflag.FlagSet) (*models.EnvConfig, []string, []string, error) {
 	v := viper.New()
-	v.AutomaticEnv()
+	setConfigDefaults(v)
+
+	flags, err := configFlags(v, args, commandFlags)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var problems []string
+	for _, field := range configFields() {
+		key := field.Tag.Get("mapstructure")
+		if err := v.BindEnv(key); err != nil {
+			return nil, nil, nil, err
+		}
+		if err := v.BindPFlag(key, flags.Lookup(flagName(key))); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	files, err := readConfigFiles(v, flags)
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+	// keys whose value could not be read are not validated
+	unreadable := map[string]bool{}
+	problems = append(problems, readSecretFiles(v, flags, unreadable)...)
+
+	var config models.EnvConfig
+	if err := v.Unmarshal(&config); err != nil {
+		var decodeErr *mapstructure.Error
+		if !errors.As(err, &decodeErr) {
+			decodeErr = &mapstructure.Error{Errors: []string{err.Error()}}
+		}
+		for _, problem := range decodeErr.Errors {
+			// report "error decoding 'KEY': reason" as "KEY: reason"
+			if match := decodedKey.FindStringSubmatch(problem); match != nil {
+				key := match[1]
+				unreadable[key] = true
+				reason := strings.TrimPrefix(problem, "error decoding '"+key+"': ")
+				problem = key + ": " + strings.Replace(reason, "'"+key+"'", "value", 1)
+			}
+			problems = append(problems, problem)
+		}
+	}
+
+	if err := config.Validate(); err != nil {
+		var fieldErrs validation.Errors
+		if !errors.As(err, &fieldErrs) {
+			fieldErrs = validation.Errors{"": err}
+		}
+		keys := configKeys()
+		for field, fieldErr := range fieldErrs {
+			key := keys[field]
+			if unreadable[key] {
+				continue
+			}
+			if key == "" {
+				problems = append(problems, fieldErr.Error())
+			} else {
+				problems = append(problems, fmt.Sprintf("%s: %v", key, fieldErr))
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, nil, nil, &ConfigError{Problems: problems}
+	}
+	return &config, flags.Args(), files, nil
+}
+
+func setConfigDefaults(v *viper.Viper) {
+	v.SetDefault("SERVER_HOST", "")
 	v.SetDefault("SERVER_PORT", "8000")
+	v.SetDefault("GRPC_PORT", "9000")
+	v.SetDefault("SERVER_READ_TIMEOUT", "30s")
+	v.SetDefault("SERVER_READ_HEADER_TIMEOUT", "10s")
+	v.SetDefault("SERVER_WRITE_TIMEOUT", "30s")
+	v.SetDefault("SERVER_IDLE_TIMEOUT", "30s")
+	v.SetDefault("SHUTDOWN_TIMEOUT", "15s")
 	v.SetDefault("MODE", "debug")
-	v.SetConfigType("dotenv")
-	v.SetConfigName(".env.local")
-	v.AddConfigPath("./")
+	v.SetDefault("DB_DRIVER", "postgres")
+	v.SetDefault("SQLITE_PATH", "greenbone.db")
+	v.SetDefault("POSTGRES_HOST", "")
+	v.SetDefault("POSTGRES_USER", "")
+	v.SetDefault("POSTGRES_PASSWORD", "")
+	v.SetDefault("POSTGRES_DB", "")
+	v.SetDefault("POSTGRES_PORT", "5432")
+	v.SetDefault("DB_AUTO_MIGRATE", true)
+	v.SetDefault("DB_MAX_OPEN_CONNS", 0)
+	v.SetDefault("DB_MAX_IDLE_CONNS", 2)
+	v.SetDefault("DB_CONN_MAX_LIFETIME", "0s")
+	v.SetDefault("STARTUP_RETRIES", 5)
+	v.SetDefault("HEALTH_CHECK_NOTIFICATION", false)
+	v.SetDefault("USE_REDIS", false)
+	v.SetDefault("REDIS_DEFAULT_ADDR", "")
+	v.SetDefault("REDIS_PASSWORD", "")
+	v.SetDefault("REDIS_POOL_SIZE", 0)
+	v.SetDefault("JWT_SECRET", "")
+	v.SetDefault("JWT_ACCESS_EXPIRATION_MINUTES", 0)
+	v.SetDefault("JWT_REFRESH_EXPIRATION_DAYS", 0)
+	v.SetDefault("DB_QUERY_TIMEOUT", "5s")
+	v.SetDefault("REDIS_TIMEOUT", "500ms")
+	v.SetDefault("NOTIFICATION_TIMEOUT", "5s")
+	v.SetDefault("NOTIFICATION_URL", constants.DefaultNotificationURL)
+	v.SetDefault("NOTIFICATION_QUEUE_SIZE", 100)
+	v.SetDefault("EVENT_WORKERS", 4)
+	v.SetDefault("EVENT_QUEUE_SIZE", 1000)
+	v.SetDefault("COMPUTER_QUOTA", constants.DefaultComputerQuota)
+	v.SetDefault("CACHE_TTL", "30m")
+	v.SetDefault("COMPUTER_CACHE_TTL", "1m")
+	v.SetDefault("LOCAL_CACHE_SIZE", 1000)
+	v.SetDefault("LOCAL_CACHE_TTL", "1m")
+	v.SetDefault("TOKEN_CLEANUP_INTERVAL", "1h")
+	v.SetDefault("WEBHOOK_TIMEOUT", "10s")
+	v.SetDefault("WEBHOOK_MAX_ATTEMPTS", 8)
+	v.SetDefault("WEBHOOK_RETRY_BACKOFF", "30s")
+	v.SetDefault("WEBHOOK_MAX_BACKOFF", "1h")
+	v.SetDefault("WEBHOOK_POLL_INTERVAL", "5s")
+	v.SetDefault("GRAPHQL_MAX_OBJECTS", 10000)
+	v.SetDefault("RATE_LIMIT_ENABLED", true)
+	v.SetDefault("RATE_LIMIT_IP", "300/m")
+	v.SetDefault("RATE_LIMIT_USER", "600/m")
+	v.SetDefault("RATE_LIMIT_API_KEY", "1200/m")
+	v.SetDefault("RATE_LIMIT_ROUTES", "/v1/auth/generate_access_token=10/m,/v1/auth/refresh=30/m")
+	v.SetDefault("CORS_ALLOWED_ORIGINS", "")
+	v.SetDefault("CORS_ALLOW_CREDENTIALS", true)
+	v.SetDefault("CORS_MAX_AGE", "12h")
+	v.SetDefault("HSTS_MAX_AGE", "8760h")
+	v.SetDefault("TLS_CERT_FILE", "")
+	v.SetDefault("TLS_KEY_FILE", "")
+	v.SetDefault("TLS_CLIENT_CA_FILE", "")
+	v.SetDefault("TLS_CLIENT_AUTH", "none")
+	v.SetDefault("TLS_CLIENT_ROLES", "")
+	v.SetDefault("TRACING_EXPORTER", "none")
+	v.SetDefault("TRACING_FILE", "logs/traces.json")
+	v.SetDefault("TRACING_OTLP_ENDPOINT", "")
+	v.SetDefault("TRACING_OTLP_INSECURE", false)
+	v.SetDefault("TRACING_SAMPLE_RATIO", 1.0)
+	v.SetDefault("LOG_LEVEL", "info")
+	v.SetDefault("LOG_FORMAT", "json")
+	v.SetDefault("LOG_FILE", "")
+	v.SetDefault("LOG_MAX_SIZE_MB", 100)
+	v.SetDefault("LOG_MAX_BACKUPS", 7)
+	v.SetDefault("LOG_MAX_AGE_DAYS", 30)
+	v.SetDefault("LOG_COMPRESS", true)
+	v.SetDefault("LOG_ROTATE_INTERVAL", "24h")
+	v.SetDefault("LOG_SYSLOG", false)
+	v.SetDefault("LOG_SYSLOG_NETWORK", "")
+	v.SetDefault("LOG_SYSLOG_ADDRESS", "")
+}
+
+// configFlags parses args with commandFlags and a flag for every
+// configuration key, showing the defaults set in v.
+func configFlags(v *viper.Viper, args []string, commandFlags []*pflag.FlagSet) (*pflag.FlagSet, error) {
+	flags := pflag.NewFlagSet("greenbone-task", pflag.ContinueOnError)
+	flags.SortFlags = false
+	flags.String("config", "", "YAML or TOML configuration file (env "+ConfigFileEnv+")")
+
+	for _, field := range configFields() {
+		key := field.Tag.Get("mapstructure")
+		name, usage := flagName(key), "overrides "+key
+		switch field.Type {
+		case reflect.TypeOf(time.Duration(0)):
+			flags.Duration(name, cast.ToDuration(v.Get(key)), usage)
+		case reflect.TypeOf(""):
+			flags.String(name, cast.ToString(v.Get(key)), usage)
+		case reflect.TypeOf(false):
+			flags.Bool(name, cast.ToBool(v.Get(key)), usage)
+		case reflect.TypeOf(0):
+			flags.Int(name, cast.ToInt(v.Get(key)), usage)
+		case reflect.TypeOf(0.0):
+			flags.Float64(name, cast.ToFloat64(v.Get(key)), usage)
+		default:
+			return nil, fmt.Errorf("unsupported type %s of configuration key %s", field.Type, key)
+		}
+	}
+	for _, commandFlagSet := range commandFlags {
+		flags.AddFlagSet(commandFlagSet)
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	return flags, nil
+}
+
+// readConfigFiles merges .env.local and the configuration file into v and
+// returns the files it read.
+func readConfigFiles(v *viper.Viper, flags *pflag.FlagSet) ([]string, error) {
+	var files []string
+	if _, err := os.Stat(dotenvFile); err == nil {
+		v.SetConfigFile(dotenvFile)
+		v.SetConfigType("dotenv")
+		if err := v.MergeInConfig(); err != nil {
+			return files, fmt.Errorf("%s: %w", dotenvFile, err)
+		}
+		files = append(files, dotenvFile)
+	}
+
+	path, _ := flags.GetString("config")
+	if path == "" {
+		path = os.Getenv(ConfigFileEnv)
+	}
+	if path == "" {
+		return files, nil
+	}
+
+	format := strings.TrimPrefix(filepath.Ext(path), ".")
+	switch format {
+	case "yaml", "yml", "toml":
+	default:
+		return files, fmt.Errorf("%s: unsupported configuration format %q, expected yaml or toml", path, format)
+	}
+	v.SetConfigFile(path)
+	v.SetConfigType(format)
+	if err := v.MergeInConfig(); err != nil {
+		return files, fmt.Errorf("%s: %w", path, err)
+	}
+	return append(files, path), nil
+}
 
-	if err := v.ReadInConfig(); err != nil {
-		panic(err)
+// readSecretFiles sets every string key KEY whose KEY_FILE is set in the
+// environment or a configuration file to the content of that file, unless
+// KEY is given as a flag. Keys whose file cannot be read are added to unreadable.
+func readSecretFiles(v *viper.Viper, flags *pflag.FlagSet, unreadable map[string]bool) []string {
+	var problems []string
+	for _, field := range configFields() {
+		key := field.Tag.Get("mapstructure")
+		if field.Type.Kind() != reflect.String || flags.Changed(flagName(key)) {
+			continue
+		}
+
+		fileKey := key + "_FILE"
+		_ = v.BindEnv(fileKey)
+		path := v.GetString(fileKey)
+		if path == "" {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", fileKey, err))
+			unreadable[key] = true
+			continue
+		}
+		v.Set(key, strings.TrimRight(string(content), "\r\n"))
 	}
+	return problems
+}
+
+// decodedKey extracts the key from the errors of mapstructure.
+var decodedKey = regexp.MustCompile(`'([A-Z0-9_]+)'`)
 
-	if err := v.Unmarshal(&Config); err != nil {
-		panic(err)
+// configFields returns the fields of models.EnvConfig.
+func configFields() []reflect.StructField {
+	configType := reflect.TypeOf(models.EnvConfig{})
+	fields := make([]reflect.StructField, 0, configType.NumField())
+	for i := 0; i < configType.NumField(); i++ {
+		fields = append(fields, configType.Field(i))
 	}
+	return fields
+}
 
-	if err := Config.Validate(); err != nil {
-		panic(err)
+// configKeys maps the fields of models.EnvConfig to their configuration keys.
+func configKeys() map[string]string {
+	keys := make(map[string]string)
+	for _, field := range configFields() {
+		keys[field.Name] = field.Tag.Get("mapstructure")
 	}
+	return keys
+}
+
+// flagName returns the command-line flag of a configuration key, e.g.
+// --server-port for SERVER_PORT.
+func flagName(key string) string {
+	return strings.ReplaceAll(strings.ToLower(key), "_", "-")
 }
@@ -0,0 +1,298 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+	"greenbone-task/logger"
+	"greenbone-task/metrics"
+	db "greenbone-task/models/db"
+	"greenbone-task/repositories"
+	"greenbone-task/tracing"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Defaults of the webhook settings, used if they are not configured.
+const (
+	DefaultWebhookTimeout      = 10 * time.Second
+	DefaultWebhookMaxAttempts  = 8
+	DefaultWebhookRetryBackoff = 30 * time.Second
+	DefaultWebhookMaxBackoff   = time.Hour
+	DefaultWebhookPollInterval = 5 * time.Second
+)
+
+// webhookSettings are the webhook settings in effect.
+type webhookSettings struct {
+	timeout      time.Duration
+	maxAttempts  int
+	retryBackoff time.Duration
+	maxBackoff   time.Duration
+}
+
+func currentWebhookSettings() webhookSettings {
+	settings := webhookSettings{
+		timeout:      DefaultWebhookTimeout,
+		maxAttempts:  DefaultWebhookMaxAttempts,
+		retryBackoff: DefaultWebhookRetryBackoff,
+		maxBackoff:   DefaultWebhookMaxBackoff,
+	}
+	if config := LiveConfig(); config != nil {
+		if config.WebhookTimeout > 0 {
+			settings.timeout = config.WebhookTimeout
+		}
+		if config.WebhookMaxAttempts > 0 {
+			settings.maxAttempts = config.WebhookMaxAttempts
+		}
+		if config.WebhookRetryBackoff > 0 {
+			settings.retryBackoff = config.WebhookRetryBackoff
+		}
+		if config.WebhookMaxBackoff > 0 {
+			settings.maxBackoff = config.WebhookMaxBackoff
+		}
+	}
+	return settings
+}
+
+// backoff returns how long to wait after the given number of failed
+// attempts: the retry backoff, doubled after every further failure, at most
+// the maximum backoff.
+func (s webhookSettings) backoff(attempts int) time.Duration {
+	wait := s.retryBackoff
+	for i := 1; i < attempts && wait < s.maxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > s.maxBackoff {
+		wait = s.maxBackoff
+	}
+	return wait
+}
+
+// webhookClient sends the webhook requests. Its timeout is set per request.
+var webhookClient = &http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport)}
+
+// DeliverDueWebhooks sends the deliveries whose next attempt is due and
+// returns how many it attempted. A delivery that fails is retried with
+// backoff until it runs out of attempts, when it is dead.
+func DeliverDueWebhooks(ctx context.Context) (_ int, err error) {
+	ctx, span := tracing.Start(ctx, "services.DeliverDueWebhooks")
+	defer tracing.End(span, &err)
+
+	settings := currentWebhookSettings()
+	// only deliveries due when the run starts are sent, so that a failed
+	// delivery is not retried within the same run
+	due := time.Now()
+	sent := 0
+	for sent < webhookDeliveryBatch {
+		// claim one delivery at a time, for longer than sending it may take,
+		// so that the claim does not expire while earlier ones are sent;
+		// the claim is truncated to the precision the databases store
+		until := time.Now().Add(2 * settings.timeout).Truncate(time.Microsecond)
+		deliveries, err := Store.Webhooks().ClaimDueDeliveries(ctx, due, until, 1)
+		if err != nil {
+			return sent, fmt.Errorf("error claiming webhook deliveries: %w", err)
+		}
+		if len(deliveries) == 0 {
+			break
+		}
+		if err := deliverWebhook(ctx, settings, &deliveries[0], until); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// deliverWebhook makes one attempt to send a delivery claimed until
+// claimedUntil and records it, unless another worker claimed the delivery
+// since.
+func deliverWebhook(ctx context.Context, settings webhookSettings, delivery *db.WebhookDelivery, claimedUntil time.Time) (err error) {
+	ctx, span := tracing.Start(ctx, "services.deliverWebhook",
+		attribute.Int64("webhook.id", int64(delivery.WebhookID)), attribute.String("event.type", delivery.EventType))
+	defer tracing.End(span, &err)
+
+	webhook, err := Store.Webhooks().FindByID(ctx, delivery.WebhookID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		// deleted while the delivery was claimed
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	attempt := db.WebhookAttempt{DeliveryID: delivery.ID, AttemptedAt: time.Now()}
+	if webhook.Active {
+		attempt.StatusCode, err = sendWebhook(ctx, settings.timeout, webhook, delivery)
+		if ctx.Err() != nil {
+			// stopped while sending; the delivery is sent again once its
+			// claim expires
+			return ctx.Err()
+		}
+		if err != nil {
+			attempt.Error = err.Error()
+		}
+	} else {
+		attempt.Error = "the webhook is disabled"
+	}
+	attempt.DurationMS = time.Since(attempt.AttemptedAt).Milliseconds()
+
+	delivery.Attempts++
+	delivery.LastStatusCode = attempt.StatusCode
+	delivery.LastError = attempt.Error
+	result := "success"
+	switch {
+	case attempt.Error == "":
+		delivery.Status = db.DeliverySucceeded
+		delivery.DeliveredAt = &attempt.AttemptedAt
+		delivery.NextAttemptAt = nil
+	case delivery.Attempts >= settings.maxAttempts || !webhook.Active:
+		result = "dead"
+		delivery.Status = db.DeliveryDead
+		delivery.NextAttemptAt = nil
+		logger.FromContext(ctx).Warn("webhook delivery is dead",
+			zap.Uint("webhook", webhook.ID), zap.Uint("delivery", delivery.ID), zap.Int("attempts", delivery.Attempts), zap.String("error", attempt.Error))
+	default:
+		result = "retry"
+		next := time.Now().Add(settings.backoff(delivery.Attempts))
+		delivery.NextAttemptAt = &next
+	}
+	metrics.WebhookAttempts.WithLabelValues(result).Inc()
+
+	err = runUnitOfWork(ctx, func(uow *unitOfWork) error {
+		if err := uow.Webhooks().UpdateDelivery(ctx, delivery, claimedUntil); err != nil {
+			return err
+		}
+		return uow.Webhooks().CreateAttempt(ctx, &attempt)
+	})
+	if errors.Is(err, repositories.ErrStale) {
+		// the claim expired while sending; the other worker records its own
+		// attempt
+		logger.FromContext(ctx).Warn("webhook delivery was claimed by another worker",
+			zap.Uint("webhook", webhook.ID), zap.Uint("delivery", delivery.ID))
+		return nil
+	}
+	return err
+}
+
+// sendWebhook posts the payload of a delivery, signed with the secret of the
+// webhook. Any status but 2xx is an error.
+func sendWebhook(ctx context.Context, timeout time.Duration, webhook *db.Webhook, delivery *db.WebhookDelivery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "greenbone-inventory-webhooks")
+	req.Header.Set(WebhookEventHeader, delivery.EventType)
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(webhook.Secret, timestamp, body))
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("the receiver answered %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	return resp.StatusCode, nil
+}
+
+// WebhookDispatcher sends the due webhook deliveries in the background,
+// every poll interval and as soon as new deliveries are committed.
+type WebhookDispatcher struct {
+	interval time.Duration
+	wake     chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	once     sync.Once
+}
+
+// WebhookWorker is the dispatcher woken up when deliveries are stored. It is
+// nil unless the server started one, and then deliveries wait to be sent by
+// the next dispatcher that polls.
+var WebhookWorker *WebhookDispatcher
+
+// NewWebhookDispatcher starts a dispatcher polling every interval.
+func NewWebhookDispatcher(interval time.Duration) *WebhookDispatcher {
+	if interval <= 0 {
+		interval = DefaultWebhookPollInterval
+	}
+	d := &WebhookDispatcher{
+		interval: interval,
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go d.run()
+	return d
+}
+
+// Wake makes the dispatcher look for due deliveries now.
+func (d *WebhookDispatcher) Wake() {
+	if d == nil {
+		return
+	}
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Stop stops the dispatcher after the delivery being sent, if any. The
+// deliveries it claimed but did not send are sent once their claim expires.
+func (d *WebhookDispatcher) Stop(ctx context.Context) error {
+	d.once.Do(func() { close(d.stop) })
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (d *WebhookDispatcher) run() {
+	defer close(d.done)
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-d.stop
+		cancel()
+	}()
+
+	for {
+		select {
+		case <-d.stop:
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+		// keep going while full batches are due
+		for {
+			sent, err := DeliverDueWebhooks(ctx)
+			if err != nil && ctx.Err() == nil {
+				logger.Error("failed to deliver webhooks", zap.Error(err))
+			}
+			if err != nil || sent < webhookDeliveryBatch {
+				break
+			}
+		}
+	}
+}
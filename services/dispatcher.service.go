@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"greenbone-task/logger"
+	"greenbone-task/metrics"
+	"sync"
+)
+
+type queuedNotification struct {
+	ctx                  context.Context
+	employeeAbbreviation string
+	message              string
+}
+
+// NotificationDispatcher is a NotificationService that queues notifications
+// and sends them from a background worker, so a slow notification server
+// does not hold up requests.
+type NotificationDispatcher struct {
+	next    NotificationService
+	queue   chan queuedNotification
+	done    chan struct{}
+	mu      sync.RWMutex
+	stopped bool
+}
+
+// NewNotificationDispatcher starts a dispatcher sending through next with a queue of the given size.
+func NewNotificationDispatcher(next NotificationService, size int) *NotificationDispatcher {
+	d := &NotificationDispatcher{
+		next:  next,
+		queue: make(chan queuedNotification, size),
+		done:  make(chan struct{}),
+	}
+	go d.run()
+	return d
+}
+
+func (d *NotificationDispatcher) NotifySystemAdministrator(ctx context.Context, employeeAbbreviation string, message string) error {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	// notifications that cannot be queued are lost, so count them as failed
+	if d.stopped {
+		err := errors.New("notification dispatcher is stopped")
+		metrics.ObserveNotification(err)
+		return err
+	}
+	select {
+	case d.queue <- queuedNotification{ctx: detach(ctx), employeeAbbreviation: employeeAbbreviation, message: message}:
+		return nil
+	default:
+		err := errors.New("notification queue is full")
+		metrics.ObserveNotification(err)
+		return err
+	}
+}
+
+// SetNext sends the notifications that are not yet sent through next, e.g.
+// after the notification target has been reconfigured.
+func (d *NotificationDispatcher) SetNext(next NotificationService) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.next = next
+}
+
+func (d *NotificationDispatcher) target() NotificationService {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.next
+}
+
+// Stop stops accepting notifications and waits until the queued ones are sent.
+func (d *NotificationDispatcher) Stop(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.stopped {
+		d.stopped = true
+		close(d.queue)
+	}
+	d.mu.Unlock()
+
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (d *NotificationDispatcher) run() {
+	defer close(d.done)
+	for n := range d.queue {
+		if err := d.target().NotifySystemAdministrator(n.ctx, n.employeeAbbreviation, n.message); err != nil {
+			logger.FromContext(n.ctx).Error("failed to notify system administrator", zap.String("employee", n.employeeAbbreviation), zap.Error(err))
+		}
+	}
+}
+
+// detach keeps the trace and logger of ctx but not its cancellation, so a
+// notification still belongs to the request that caused it after that
+// request has ended.
+func detach(ctx context.Context) context.Context {
+	detached := trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(ctx))
+	return logger.WithContext(detached, logger.FromContext(ctx))
+}
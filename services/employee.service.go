@@ -3,18 +3,23 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 	"greenbone-task/logger"
+	"greenbone-task/metrics"
 	"greenbone-task/models"
 	db "greenbone-task/models/db"
-	"time"
+	"greenbone-task/repositories"
+	"greenbone-task/tracing"
 )
 
-var CacheExpiration = 30 * time.Minute
-
 // CreateEmployee function creates a new emplpyee records
-func CreateEmployee(employee models.EmployeeRequest) error {
+func CreateEmployee(ctx context.Context, employee models.EmployeeRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "services.CreateEmployee", attribute.String("employee.abbreviation", employee.Abbreviation))
+	defer tracing.End(span, &err)
+
 	// Store employee details in database
 
 	emp := db.Employee{
@@ -24,16 +29,41 @@ func CreateEmployee(employee models.EmployeeRequest) error {
 		Email:        employee.Email,
 		Computers:    []db.Computer{},
 	}
-	if err := DbConnection.Create(&emp).Error; err != nil {
-		logger.Error("failed to save computer", zap.Error(err))
-		return err
-	}
-	return nil
+	return runUnitOfWork(ctx, func(uow *unitOfWork) error {
+		if err := uow.Employees().Create(ctx, &emp); err != nil {
+			if errors.Is(err, repositories.ErrDuplicate) {
+				return ConflictError(CodeDuplicateEmployee, err, "an employee with abbreviation %s or email %s already exists", emp.Abbreviation, emp.Email)
+			}
+			logger.FromContext(ctx).Error("failed to save employee", zap.Error(err))
+			return err
+		}
+		return uow.Publish(ctx, EmployeeCreated{Employee: emp})
+	})
 }
 
 // DeleteEmployeeComputer delete specific employee computer
-func DeleteEmployeeComputer(computerID int64, abbrev string) error {
-	err := DbConnection.Delete(&db.Computer{}, computerID, abbrev).Error
+func DeleteEmployeeComputer(ctx context.Context, computerID int64, abbrev string) (err error) {
+	ctx, span := tracing.Start(ctx, "services.DeleteEmployeeComputer",
+		attribute.Int64("computer.id", computerID), attribute.String("employee.abbreviation", abbrev))
+	defer tracing.End(span, &err)
+
+	err = runUnitOfWork(ctx, func(uow *unitOfWork) error {
+		employee, err := findEmployee(ctx, uow, abbrev)
+		if err != nil {
+			return fmt.Errorf("error finding employee: %w", err)
+		}
+
+		// only delete the computer if it is assigned to this employee
+		assignment, err := uow.Computers().FindAssignment(ctx, uint(computerID))
+		if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+			return err
+		}
+		if assignment == nil || assignment.EmployeeID != employee.ID {
+			return NotFoundError(CodeComputerNotAssigned, "computer %d is not assigned to employee %s", computerID, abbrev)
+		}
+
+		return deleteComputer(ctx, uow, uint(computerID))
+	})
 	if err != nil {
 		return fmt.Errorf("error deleting computer: %w", err)
 	}
@@ -41,38 +71,85 @@ func DeleteEmployeeComputer(computerID int64, abbrev string) error {
 }
 
 // FindComputersByEmployeeAbbrev fidn the computers from the database using abbrev
-func FindComputersByEmployeeAbbrev(abbrev string) ([]db.Computer, error) {
+func FindComputersByEmployeeAbbrev(ctx context.Context, abbrev string) (_ []db.Computer, err error) {
+	ctx, span := tracing.Start(ctx, "services.FindComputersByEmployeeAbbrev", attribute.String("employee.abbreviation", abbrev))
+	defer tracing.End(span, &err)
+
 	// check cache first
-	cacheKey := fmt.Sprintf("computers_by_employee_%s", abbrev)
-	if cachedResult, err := GetRedisDefaultClient().Get(context.Background(), cacheKey).Result(); err == nil {
-		var cachedComputers []db.Computer
-		if err := json.Unmarshal([]byte(cachedResult), &cachedComputers); err == nil {
-			return cachedComputers, nil
+	cacheKey := employeeComputersCacheKey(abbrev)
+	if cacheEnabled() {
+		if cachedResult, err := GetRedisDefaultClient().Get(ctx, cacheKey).Result(); err == nil {
+			var cachedComputers []db.Computer
+			if err := json.Unmarshal([]byte(cachedResult), &cachedComputers); err == nil {
+				metrics.ObserveCache(metrics.CacheComputersByEmployee, true)
+				return cachedComputers, nil
+			}
+			// cache hit but unmarshal error, fallback to DB query
 		}
-		// cache hit but unmarshal error, fallback to DB query
+		metrics.ObserveCache(metrics.CacheComputersByEmployee, false)
 	}
 
-	var computers []db.Computer
-
 	// find the employee
-	employee, err := FindByEmployeeAbbrev(abbrev)
+	employee, err := FindByEmployeeAbbrev(ctx, abbrev)
 	if err != nil {
 		return nil, fmt.Errorf("error finding employee: %w", err)
 	}
 
-	// fetch the associated computers using explicit join condition
-	if err := DbConnection.Joins("JOIN employee_computers ON computers.id = employee_computers.computer_id").
-		Where("employee_computers.employee_id = ?", employee.ID).
-		Find(&computers).Error; err != nil {
+	// fetch the associated computers through the employee_computers join table
+	computers, err := Store.Computers().FindByEmployeeID(ctx, employee.ID)
+	if err != nil {
 		return nil, fmt.Errorf("error finding computers: %w", err)
 	}
 
 	// cache the result for future use
-	cachedResult, err := json.Marshal(computers)
+	if cacheEnabled() {
+		cachedResult, err := json.Marshal(computers)
+		if err != nil {
+			return computers, nil
+		}
+		GetRedisDefaultClient().Set(ctx, cacheKey, string(cachedResult), Config.CacheTTL)
+	}
+
+	return computers, nil
+}
+
+// GetAllEmployees fetches all employees ordered by ID
+func GetAllEmployees(ctx context.Context) (_ []db.Employee, err error) {
+	ctx, span := tracing.Start(ctx, "services.GetAllEmployees")
+	defer tracing.End(span, &err)
+
+	employees, err := Store.Employees().FindAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting all employees: %w", err)
+	}
+	return employees, nil
+}
+
+// FindEmployeesByIDs fetches the employees with the IDs that exist
+func FindEmployeesByIDs(ctx context.Context, ids []uint) (_ []db.Employee, err error) {
+	ctx, span := tracing.Start(ctx, "services.FindEmployeesByIDs", attribute.Int("employee.count", len(ids)))
+	defer tracing.End(span, &err)
+
+	employees, err := Store.Employees().FindByIDs(ctx, ids)
 	if err != nil {
-		return computers, nil
+		return nil, fmt.Errorf("error finding employees: %w", err)
 	}
-	GetRedisDefaultClient().Set(context.Background(), cacheKey, string(cachedResult), CacheExpiration)
+	return employees, nil
+}
 
+// ComputersOfEmployees fetches the computers assigned to each employee
+func ComputersOfEmployees(ctx context.Context, employeeIDs []uint) (_ map[uint][]db.Computer, err error) {
+	ctx, span := tracing.Start(ctx, "services.ComputersOfEmployees", attribute.Int("employee.count", len(employeeIDs)))
+	defer tracing.End(span, &err)
+
+	computers, err := Store.Computers().FindByEmployeeIDs(ctx, employeeIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error finding computers: %w", err)
+	}
 	return computers, nil
 }
+
+// employeeComputersCacheKey is the cache key of the computers of an employee.
+func employeeComputersCacheKey(abbrev string) string {
+	return fmt.Sprintf("computers_by_employee_%s", abbrev)
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorKind classifies domain errors so that the transport layer can map
+// them to a status code without looking at messages.
+type ErrorKind int
+
+const (
+	KindNotFound ErrorKind = iota + 1
+	KindConflict
+	KindValidation
+	KindQuotaExceeded
+	KindUpstream
+	KindUnauthorized
+	KindForbidden
+)
+
+// Stable error codes returned to clients. They never change once published.
+const (
+	CodeComputerNotFound    = "computer_not_found"
+	CodeEmployeeNotFound    = "employee_not_found"
+	CodeComputerNotAssigned = "computer_not_assigned"
+	CodeDuplicateMacAddress = "duplicate_mac_address"
+	CodeDuplicateEmployee   = "duplicate_employee"
+	CodeValidationFailed    = "validation_failed"
+	CodeInvalidToken        = "invalid_token"
+	CodeTokenExpired        = "token_expired"
+	CodeCacheUnavailable    = "cache_unavailable"
+	CodeRateLimitExceeded   = "rate_limit_exceeded"
+	CodeUnknownClient       = "unknown_client_certificate"
+	CodeInsufficientRole    = "insufficient_role"
+	CodeMutationNotAllowed  = "mutation_not_allowed"
+	CodeQueryTooComplex     = "query_too_complex"
+)
+
+// Error is a domain error with a kind, a stable code and a message that is
+// safe to show to clients. Err keeps the underlying cause, if any.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// AsError returns the domain error in err's chain, if there is one.
+func AsError(err error) (*Error, bool) {
+	var domainErr *Error
+	ok := errors.As(err, &domainErr)
+	return domainErr, ok
+}
+
+// NotFoundError reports that a requested resource does not exist.
+func NotFoundError(code string, format string, args ...any) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// ConflictError reports that a request conflicts with the stored state, e.g.
+// a duplicate unique value.
+func ConflictError(code string, cause error, format string, args ...any) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: fmt.Sprintf(format, args...), Err: cause}
+}
+
+// ValidationError reports invalid input. cause may be an ozzo
+// validation.Errors, whose per-field messages are passed on to the client.
+func ValidationError(code string, cause error, format string, args ...any) *Error {
+	return &Error{Kind: KindValidation, Code: code, Message: fmt.Sprintf(format, args...), Err: cause}
+}
+
+// QuotaExceededError reports that a caller used up a limited allowance.
+func QuotaExceededError(code string, format string, args ...any) *Error {
+	return &Error{Kind: KindQuotaExceeded, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// UpstreamError reports that a dependency such as Redis or the notification
+// server failed.
+func UpstreamError(code string, cause error, format string, args ...any) *Error {
+	return &Error{Kind: KindUpstream, Code: code, Message: fmt.Sprintf(format, args...), Err: cause}
+}
+
+// UnauthorizedError reports missing or invalid credentials.
+func UnauthorizedError(code string, format string, args ...any) *Error {
+	return &Error{Kind: KindUnauthorized, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// ForbiddenError reports that the caller is known but may not do what it asked.
+func ForbiddenError(code string, format string, args ...any) *Error {
+	return &Error{Kind: KindForbidden, Code: code, Message: fmt.Sprintf(format, args...)}
+}
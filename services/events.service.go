@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"greenbone-task/logger"
+	"greenbone-task/metrics"
+	db "greenbone-task/models/db"
+	"greenbone-task/repositories"
+	"greenbone-task/tracing"
+	"hash/fnv"
+	"sync"
+)
+
+// Event is a change of the inventory that the services publish. The
+// subscribers handle its side effects.
+type Event interface {
+	// EventName names the event type, e.g. ComputerCreated.
+	EventName() string
+	// AggregateID names the computer or employee the event is about. The
+	// asynchronous subscribers handle the events of one aggregate in the
+	// order they were published.
+	AggregateID() string
+}
+
+// ComputerCreated is published when a computer is created and assigned to
+// its employee.
+type ComputerCreated struct {
+	Computer db.Computer
+	// EmployeeComputers is how many computers the employee has, including
+	// this one.
+	EmployeeComputers int64
+}
+
+func (ComputerCreated) EventName() string     { return "ComputerCreated" }
+func (e ComputerCreated) AggregateID() string { return computerAggregate(e.Computer.ID) }
+
+// ComputerReassigned is published when a computer is assigned to another
+// employee.
+type ComputerReassigned struct {
+	Computer               db.Computer
+	PreviousEmployeeAbbrev string
+}
+
+func (ComputerReassigned) EventName() string     { return "ComputerReassigned" }
+func (e ComputerReassigned) AggregateID() string { return computerAggregate(e.Computer.ID) }
+
+// ComputerDeleted is published when a computer is deleted. Computer is the
+// computer as it was before.
+type ComputerDeleted struct {
+	Computer db.Computer
+}
+
+func (ComputerDeleted) EventName() string     { return "ComputerDeleted" }
+func (e ComputerDeleted) AggregateID() string { return computerAggregate(e.Computer.ID) }
+
+// EmployeeCreated is published when an employee is created.
+type EmployeeCreated struct {
+	Employee db.Employee
+}
+
+func (EmployeeCreated) EventName() string     { return "EmployeeCreated" }
+func (e EmployeeCreated) AggregateID() string { return "employee:" + e.Employee.Abbreviation }
+
+func computerAggregate(id uint) string {
+	return fmt.Sprintf("computer:%d", id)
+}
+
+// EventTx is the unit of work a synchronous subscriber handles an event in.
+type EventTx interface {
+	repositories.Store
+	AfterCommit(fn func())
+}
+
+type subscriber struct {
+	name string
+	// handleSync is set for synchronous subscribers, handleAsync for
+	// asynchronous ones.
+	handleSync  func(ctx context.Context, tx EventTx, event Event) error
+	handleAsync func(ctx context.Context, event Event) error
+}
+
+type queuedEvent struct {
+	ctx         context.Context
+	event       Event
+	subscribers []subscriber
+}
+
+// EventBus delivers the events published by the services to their
+// subscribers.
+//
+// Synchronous subscribers handle an event when it is published, within the
+// transaction of the change: if one fails, the change is rolled back.
+// Asynchronous subscribers handle it once the transaction is committed, and
+// never if it is rolled back. Until Start is called they do so before the
+// service returns; after it, on background workers.
+type EventBus struct {
+	mu          sync.RWMutex
+	subscribers map[string][]subscriber
+	workers     []chan queuedEvent
+	done        sync.WaitGroup
+}
+
+// Events is the event bus of the services.
+var Events = subscribeSideEffects(NewEventBus())
+
+// NewEventBus returns a bus without subscribers.
+func NewEventBus() *EventBus {
+	return &EventBus{subscribers: map[string][]subscriber{}}
+}
+
+// SubscribeSync subscribes handle to the events of type E, handled within the
+// transaction that publishes them. An error rolls the transaction back.
+func SubscribeSync[E Event](bus *EventBus, name string, handle func(ctx context.Context, tx EventTx, event E) error) {
+	var event E
+	bus.subscribe(event.EventName(), subscriber{name: name, handleSync: func(ctx context.Context, tx EventTx, event Event) error {
+		return handle(ctx, tx, event.(E))
+	}})
+}
+
+// SubscribeAsync subscribes handle to the events of type E, handled after
+// the transaction that publishes them is committed. Errors are logged.
+func SubscribeAsync[E Event](bus *EventBus, name string, handle func(ctx context.Context, event E) error) {
+	var event E
+	bus.subscribe(event.EventName(), subscriber{name: name, handleAsync: func(ctx context.Context, event Event) error {
+		return handle(ctx, event.(E))
+	}})
+}
+
+func (b *EventBus) subscribe(eventName string, s subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers[eventName] = append(b.subscribers[eventName], s)
+}
+
+// Start handles the events for the asynchronous subscribers on the given
+// number of workers, each with a queue of queueSize events. The events of
+// one aggregate always go to the same worker, so they are handled in order.
+// A publisher waits while the queue of its worker is full.
+func (b *EventBus) Start(workers int, queueSize int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.workers != nil || workers <= 0 {
+		return
+	}
+	b.workers = make([]chan queuedEvent, workers)
+	for i := range b.workers {
+		queue := make(chan queuedEvent, queueSize)
+		b.workers[i] = queue
+		b.done.Add(1)
+		go func() {
+			defer b.done.Done()
+			for queued := range queue {
+				handleAsync(queued)
+			}
+		}()
+	}
+}
+
+// Stop waits until the queued events are handled. Events published later
+// are handled before their service returns, until the bus is started again.
+func (b *EventBus) Stop(ctx context.Context) error {
+	b.mu.Lock()
+	for _, queue := range b.workers {
+		close(queue)
+	}
+	b.workers = nil
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		b.done.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// publish hands event to the synchronous subscribers within tx, and to the
+// asynchronous ones once tx is committed.
+func (b *EventBus) publish(ctx context.Context, tx EventTx, event Event) error {
+	b.mu.RLock()
+	subscribers := b.subscribers[event.EventName()]
+	b.mu.RUnlock()
+
+	var async []subscriber
+	for _, s := range subscribers {
+		if s.handleAsync != nil {
+			async = append(async, s)
+			continue
+		}
+		if err := handleSync(ctx, tx, event, s); err != nil {
+			return err
+		}
+	}
+	if len(async) > 0 {
+		tx.AfterCommit(func() {
+			b.dispatch(queuedEvent{ctx: detach(ctx), event: event, subscribers: async})
+		})
+	}
+	return nil
+}
+
+// dispatch queues an event for the worker of its aggregate, or handles it
+// right away if the bus has no workers.
+func (b *EventBus) dispatch(queued queuedEvent) {
+	b.mu.RLock()
+	if len(b.workers) > 0 {
+		hash := fnv.New32a()
+		_, _ = hash.Write([]byte(queued.event.AggregateID()))
+		b.workers[hash.Sum32()%uint32(len(b.workers))] <- queued
+		b.mu.RUnlock()
+		return
+	}
+	b.mu.RUnlock()
+	handleAsync(queued)
+}
+
+func handleSync(ctx context.Context, tx EventTx, event Event, s subscriber) (err error) {
+	ctx, span := tracing.Start(ctx, "events."+s.name)
+	defer tracing.End(span, &err)
+
+	err = s.handleSync(ctx, tx, event)
+	metrics.ObserveEvent(event.EventName(), s.name, err)
+	if err != nil {
+		return fmt.Errorf("error handling %s in %s: %w", event.EventName(), s.name, err)
+	}
+	return nil
+}
+
+func handleAsync(queued queuedEvent) {
+	for _, s := range queued.subscribers {
+		func() {
+			var err error
+			ctx, span := tracing.Start(queued.ctx, "events."+s.name)
+			defer tracing.End(span, &err)
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					err = fmt.Errorf("panic: %v", recovered)
+				}
+				metrics.ObserveEvent(queued.event.EventName(), s.name, err)
+				if err != nil {
+					logger.FromContext(ctx).Error("failed to handle event", zap.String("event", queued.event.EventName()),
+						zap.String("aggregate", queued.event.AggregateID()), zap.String("subscriber", s.name), zap.Error(err))
+				}
+			}()
+			err = s.handleAsync(ctx, queued.event)
+		}()
+	}
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Readiness values reported by CheckReadiness.
+const (
+	StatusUp       = "up"
+	StatusDown     = "down"
+	StatusReady    = "ready"
+	StatusNotReady = "not_ready"
+)
+
+var ready atomic.Bool
+
+// SetReady marks whether the service finished starting and accepts traffic.
+func SetReady(isReady bool) {
+	ready.Store(isReady)
+}
+
+// IsReady reports whether the service finished starting and accepts traffic.
+func IsReady() bool {
+	return ready.Load()
+}
+
+// DependencyStatus is the result of checking a single dependency.
+type DependencyStatus struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Required  bool    `json:"required"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// ReadinessReport is the outcome of all dependency checks.
+type ReadinessReport struct {
+	Status string                      `json:"status"`
+	Checks map[string]DependencyStatus `json:"checks"`
+}
+
+type dependencyCheck struct {
+	name     string
+	required bool
+	check    func(ctx context.Context) error
+}
+
+// CheckReadiness pings the dependencies in parallel. The service is ready
+// when it has started and all required dependencies are up.
+func CheckReadiness(ctx context.Context) ReadinessReport {
+	checks := []dependencyCheck{{name: "database", required: true, check: PingDB}}
+	if cacheEnabled() {
+		checks = append(checks, dependencyCheck{name: "redis", required: true, check: PingRedis})
+	}
+	if Config != nil && Config.HealthCheckNotification {
+		checks = append(checks, dependencyCheck{name: "notification", required: false, check: pingNotificationTarget})
+	}
+
+	report := ReadinessReport{Status: StatusReady, Checks: map[string]DependencyStatus{}}
+	if !IsReady() {
+		report.Status = StatusNotReady
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, c := range checks {
+		wg.Add(1)
+		go func(c dependencyCheck) {
+			defer wg.Done()
+
+			started := time.Now()
+			err := c.check(ctx)
+			status := DependencyStatus{
+				Status:    StatusUp,
+				LatencyMs: float64(time.Since(started).Microseconds()) / 1000,
+				Required:  c.required,
+			}
+			if err != nil {
+				status.Status = StatusDown
+				status.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[c.name] = status
+			if err != nil && c.required {
+				report.Status = StatusNotReady
+			}
+		}(c)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// pingNotificationTarget checks that the notification server accepts TCP connections.
+func pingNotificationTarget(ctx context.Context) error {
+	target, err := url.Parse(notificationURL())
+	if err != nil {
+		return err
+	}
+	host := target.Host
+	if target.Port() == "" {
+		port := "80"
+		if target.Scheme == "https" {
+			port = "443"
+		}
+		host = net.JoinHostPort(target.Hostname(), port)
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"greenbone-task/logger"
+)
+
+// ConfigureLogging applies the log level, encoding, rotation and sinks of Config.
+func ConfigureLogging() error {
+	return logger.Configure(logger.Options{
+		Level:         Config.LogLevel,
+		Format:        Config.LogFormat,
+		File:          Config.LogFile,
+		MaxSizeMB:     Config.LogMaxSizeMB,
+		MaxBackups:    Config.LogMaxBackups,
+		MaxAgeDays:    Config.LogMaxAgeDays,
+		Compress:      Config.LogCompress,
+		Syslog:        Config.LogSyslog,
+		SyslogNetwork: Config.LogSyslogNetwork,
+		SyslogAddress: Config.LogSyslogAddress,
+	})
+}
+
+// SetLogLevel changes the log level at runtime.
+func SetLogLevel(ctx context.Context, level string) error {
+	previous := logger.Level()
+	if err := logger.SetLevel(level); err != nil {
+		return err
+	}
+	if previous != logger.Level() {
+		logger.FromContext(ctx).Info("changed log level", zap.String("from", previous), zap.String("to", logger.Level()))
+	}
+	return nil
+}
+
+// RotateLogs starts new log files, on top of the rotation by size.
+func RotateLogs(context.Context) error {
+	return logger.Rotate()
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"greenbone-task/logger"
+	"greenbone-task/metrics"
+	"time"
+)
+
+// computersPerEmployeeBuckets are the upper bounds of the buckets of
+// greenbone_computers_per_employee. The employees are only counted, never
+// labelled, so the series neither grow with nor reveal the staff.
+var computersPerEmployeeBuckets = []float64{1, 2, 3, 5, 10, 20, 50}
+
+var (
+	computersPerEmployeeDesc = prometheus.NewDesc(
+		"greenbone_computers_per_employee",
+		"Distribution of the number of computers assigned to the employees with computers.",
+		nil, nil,
+	)
+	employeesOverQuotaDesc = prometheus.NewDesc(
+		"greenbone_employees_over_quota",
+		"Number of employees with at least the computer quota assigned, which notifies the administrator.",
+		nil, nil,
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(inventoryCollector{})
+}
+
+// inventoryCollector reads the business gauges from the store on every scrape,
+// so they never drift from the database.
+type inventoryCollector struct{}
+
+func (inventoryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- computersPerEmployeeDesc
+	ch <- employeesOverQuotaDesc
+}
+
+func (inventoryCollector) Collect(ch chan<- prometheus.Metric) {
+	if Store == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	counts, err := Store.Computers().CountPerEmployee(ctx)
+	if err != nil {
+		logger.FromContext(ctx).Error("failed to collect inventory metrics", zap.Error(err))
+		return
+	}
+
+	var overQuota int
+	var sum float64
+	buckets := make(map[float64]uint64, len(computersPerEmployeeBuckets))
+	for _, count := range counts {
+		sum += float64(count)
+		for _, bound := range computersPerEmployeeBuckets {
+			if float64(count) <= bound {
+				buckets[bound]++
+			}
+		}
+		if count >= computerQuota() {
+			overQuota++
+		}
+	}
+	ch <- prometheus.MustNewConstHistogram(computersPerEmployeeDesc, uint64(len(counts)), sum, buckets)
+	ch <- prometheus.MustNewConstMetric(employeesOverQuotaDesc, prometheus.GaugeValue, float64(overQuota))
+}
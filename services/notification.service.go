@@ -2,23 +2,87 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
 	"greenbone-task/constants"
+	"greenbone-task/metrics"
+	"greenbone-task/tracing"
+	"net"
 	"net/http"
+	"time"
 )
 
+// DefaultNotificationTimeout bounds a notification request if
+// NOTIFICATION_TIMEOUT is not configured.
+const DefaultNotificationTimeout = 5 * time.Second
+
 type NotificationService interface {
-	NotifySystemAdministrator(employeeAbbreviation string, message string) error
+	NotifySystemAdministrator(ctx context.Context, employeeAbbreviation string, message string) error
 }
 
-type notificationService struct{}
+// Notifier is the notification service used by the computer services.
+var Notifier NotificationService = NewNotificationService()
+
+type notificationService struct {
+	url    string
+	client *http.Client
+}
 
+// NewNotificationService returns a notification service posting to the
+// configured NOTIFICATION_URL.
 func NewNotificationService() NotificationService {
-	return &notificationService{}
+	timeout := DefaultNotificationTimeout
+	if config := LiveConfig(); config != nil && config.NotificationTimeout > 0 {
+		timeout = config.NotificationTimeout
+	}
+	return NewNotificationServiceWithTimeout(notificationURL(), timeout)
 }
 
-func (ns *notificationService) NotifySystemAdministrator(employeeAbbreviation string, message string) error {
+// notificationURL returns the configured NOTIFICATION_URL.
+func notificationURL() string {
+	if config := LiveConfig(); config != nil && config.NotificationURL != "" {
+		return config.NotificationURL
+	}
+	return constants.DefaultNotificationURL
+}
+
+// NewNotificationServiceWithURL returns a notification service posting to the given URL.
+func NewNotificationServiceWithURL(url string) NotificationService {
+	return NewNotificationServiceWithTimeout(url, DefaultNotificationTimeout)
+}
+
+// NewNotificationServiceWithTimeout returns a notification service posting to
+// the given URL that gives up on a request after timeout.
+func NewNotificationServiceWithTimeout(url string, timeout time.Duration) NotificationService {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}).DialContext
+	transport.TLSHandshakeTimeout = timeout
+	transport.ResponseHeaderTimeout = timeout
+
+	return &notificationService{
+		url: url,
+		client: &http.Client{
+			Timeout: timeout,
+			// the transport propagates the trace context to the notification server
+			Transport: otelhttp.NewTransport(transport),
+		},
+	}
+}
+
+func (ns *notificationService) NotifySystemAdministrator(ctx context.Context, employeeAbbreviation string, message string) (err error) {
+	ctx, span := tracing.Start(ctx, "services.NotifySystemAdministrator", attribute.String("employee.abbreviation", employeeAbbreviation))
+	defer tracing.End(span, &err)
+
+	err = ns.send(ctx, employeeAbbreviation, message)
+	metrics.ObserveNotification(err)
+	return err
+}
+
+// send posts a single notification to the notification server.
+func (ns *notificationService) send(ctx context.Context, employeeAbbreviation string, message string) error {
 	notification := map[string]string{
 		"level":                constants.Warning,
 		"employeeAbbreviation": employeeAbbreviation,
@@ -32,22 +96,23 @@ func (ns *notificationService) NotifySystemAdministrator(employeeAbbreviation st
 
 	// Send HTTP POST request to the notification service
 	// create the request
-	req, err := http.NewRequest("POST", constants.NOTIFICATION_URL, bytes.NewBuffer(jsonBytes))
+	req, err := http.NewRequestWithContext(ctx, "POST", ns.url, bytes.NewBuffer(jsonBytes))
 	if err != nil {
-		// handle error
+		return fmt.Errorf("error creating notification request: %w", err)
 	}
 
 	// set request headers
 	req.Header.Set("Content-Type", "application/json")
 
 	// send the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil || resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("error sending notification request: unexpected status code %d", resp.StatusCode)
-
+	resp, err := ns.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending notification request: %w", err)
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error sending notification request: unexpected status code %d", resp.StatusCode)
+	}
 
 	// handle the response
 	var responseBody map[string]interface{}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"greenbone-task/logger"
+	"greenbone-task/ratelimit"
+	"sync/atomic"
+)
+
+// RateLimiter holds the token buckets of the rate limits.
+var RateLimiter ratelimit.Limiter = ratelimit.NewMemoryLimiter()
+
+var rateLimitPolicy atomic.Pointer[ratelimit.Policy]
+
+// ConfigureRateLimiting applies the rate limits of Config. With Redis the
+// buckets are shared by all instances, falling back to buckets in memory
+// while Redis is unavailable.
+func ConfigureRateLimiting() error {
+	if !Config.RateLimitEnabled {
+		rateLimitPolicy.Store(nil)
+		return nil
+	}
+
+	policy, err := Config.RateLimitPolicy()
+	if err != nil {
+		return err
+	}
+
+	if Config.UseRedis {
+		RateLimiter = &ratelimit.FallbackLimiter{
+			Primary:  ratelimit.NewRedisLimiter(GetRedisDefaultClient()),
+			Fallback: ratelimit.NewMemoryLimiter(),
+			OnFallback: func(ctx context.Context, err error) {
+				logger.FromContext(ctx).Warn("rate limiting in memory, Redis is unavailable", zap.Error(err))
+			},
+		}
+	} else {
+		RateLimiter = ratelimit.NewMemoryLimiter()
+	}
+	rateLimitPolicy.Store(policy)
+	return nil
+}
+
+// RateLimitPolicy returns the rate limits in effect, or nil if requests are
+// not limited.
+func RateLimitPolicy() *ratelimit.Policy {
+	return rateLimitPolicy.Load()
+}
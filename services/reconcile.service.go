@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.opentelemetry.io/otel/attribute"
+	db "greenbone-task/models/db"
+	"greenbone-task/repositories"
+	"greenbone-task/tracing"
+)
+
+// Actions taken by ReconcileAssignments.
+const (
+	ReconcileAbbreviationUpdated = "abbreviation_updated" // employee_abbrev now names the assigned employee
+	ReconcileAssigned            = "assigned"             // the computer is now assigned to the employee in employee_abbrev
+	ReconcileUnknownEmployee     = "unknown_employee"     // employee_abbrev names no employee, left for a human
+)
+
+// ReconcileFinding is a computer whose employee_abbrev disagreed with its
+// row in employee_computers.
+type ReconcileFinding struct {
+	ComputerID uint   `json:"computer_id"`
+	Action     string `json:"action"`
+	From       string `json:"from"`
+	To         string `json:"to"`
+}
+
+// ReconcileAssignments makes the employee_abbrev of every computer agree with
+// its assignment in employee_computers, which the API lists computers by.
+// Where a computer has no assignment, one is created for the employee named
+// by employee_abbrev. With dryRun nothing is changed. It returns what was,
+// or would be, changed.
+func ReconcileAssignments(ctx context.Context, dryRun bool) (_ []ReconcileFinding, err error) {
+	ctx, span := tracing.Start(ctx, "services.ReconcileAssignments", attribute.Bool("dry_run", dryRun))
+	defer tracing.End(span, &err)
+
+	var findings []ReconcileFinding
+	err = runUnitOfWork(ctx, func(uow *unitOfWork) error {
+		findings = nil
+		computers, err := uow.Computers().FindAll(ctx)
+		if err != nil {
+			return fmt.Errorf("error getting all computers: %w", err)
+		}
+
+		for i := range computers {
+			finding, err := reconcileComputer(ctx, uow, &computers[i], dryRun)
+			if err != nil {
+				return fmt.Errorf("error reconciling computer %d: %w", computers[i].ID, err)
+			}
+			if finding == nil {
+				continue
+			}
+			findings = append(findings, *finding)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return findings, nil
+}
+
+func reconcileComputer(ctx context.Context, uow *unitOfWork, computer *db.Computer, dryRun bool) (*ReconcileFinding, error) {
+	assignment, err := uow.Computers().FindAssignment(ctx, computer.ID)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return nil, err
+	}
+
+	if assignment != nil {
+		employee, err := uow.Employees().FindByID(ctx, assignment.EmployeeID)
+		if err != nil {
+			return nil, err
+		}
+		if employee.Abbreviation == computer.EmployeeAbbrev {
+			return nil, nil
+		}
+		finding := &ReconcileFinding{ComputerID: computer.ID, Action: ReconcileAbbreviationUpdated,
+			From: computer.EmployeeAbbrev, To: employee.Abbreviation}
+		if !dryRun {
+			computer.EmployeeAbbrev = employee.Abbreviation
+			if err := uow.Computers().Update(ctx, computer); err != nil {
+				return nil, err
+			}
+			if err := uow.Publish(ctx, ComputerReassigned{Computer: *computer, PreviousEmployeeAbbrev: finding.From}); err != nil {
+				return nil, err
+			}
+		}
+		return finding, nil
+	}
+
+	if computer.EmployeeAbbrev == "" {
+		return nil, nil
+	}
+	employee, err := uow.Employees().FindByAbbrev(ctx, computer.EmployeeAbbrev)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return &ReconcileFinding{ComputerID: computer.ID, Action: ReconcileUnknownEmployee, From: computer.EmployeeAbbrev}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !dryRun {
+		if err := uow.Computers().Assign(ctx, employee.ID, computer.ID); err != nil {
+			return nil, err
+		}
+		// the computer was assigned to no one before
+		if err := uow.Publish(ctx, ComputerReassigned{Computer: *computer}); err != nil {
+			return nil, err
+		}
+	}
+	return &ReconcileFinding{ComputerID: computer.ID, Action: ReconcileAssigned, To: employee.Abbreviation}, nil
+}
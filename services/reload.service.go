@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"greenbone-task/filewatch"
+	"greenbone-task/logger"
+	"greenbone-task/models"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// redacted replaces the value of secret settings.
+const redacted = "[REDACTED]"
+
+// reloadedConfig is base, the configuration loaded on start, with the
+// reloadable settings of the last reload.
+type reloadedConfig struct {
+	base *models.EnvConfig
+	live *models.EnvConfig
+}
+
+var reloaded atomic.Pointer[reloadedConfig]
+
+// reloadMu serializes reloads from SIGHUP and from file changes.
+var reloadMu sync.Mutex
+
+// LiveConfig returns the configuration in effect: Config with the reloadable
+// settings of the last reload applied.
+func LiveConfig() *models.EnvConfig {
+	if r := reloaded.Load(); r != nil && r.base == Config {
+		return r.live
+	}
+	return Config
+}
+
+// ReloadConfig reads the configuration again and, if it is valid, applies
+// the settings tagged reload. Changes to other settings are logged and take
+// effect after a restart.
+func ReloadConfig(ctx context.Context) error {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	fresh, _, _, err := readConfig(configArgs, configCommandFlags)
+	if err != nil {
+		return fmt.Errorf("error reading configuration, keeping the current one: %w", err)
+	}
+
+	current := LiveConfig()
+	live := *current
+	var changed, restart []string
+	for _, field := range configFields() {
+		key := field.Tag.Get("mapstructure")
+		from := reflect.ValueOf(current).Elem().FieldByIndex(field.Index)
+		to := reflect.ValueOf(fresh).Elem().FieldByIndex(field.Index)
+		if reflect.DeepEqual(from.Interface(), to.Interface()) {
+			continue
+		}
+		if field.Tag.Get("reload") != "true" {
+			restart = append(restart, key)
+			continue
+		}
+		reflect.ValueOf(&live).Elem().FieldByIndex(field.Index).Set(to)
+		changed = append(changed, fmt.Sprintf("%s: %v -> %v", key, displayValue(field, from), displayValue(field, to)))
+	}
+
+	if live.RateLimitEnabled {
+		policy, err := live.RateLimitPolicy()
+		if err != nil {
+			return err
+		}
+		rateLimitPolicy.Store(policy)
+	}
+	reloaded.Store(&reloadedConfig{base: Config, live: &live})
+
+	log := logger.FromContext(ctx)
+	if len(restart) > 0 {
+		log.Warn("configuration changes take effect after a restart", zap.Strings("keys", restart))
+	}
+	if len(changed) > 0 {
+		log.Info("reloaded configuration", zap.Strings("changes", changed))
+	} else {
+		log.Info("reloaded configuration, nothing changed")
+	}
+
+	// restore the configured log level, also after a change at runtime
+	return SetLogLevel(ctx, live.LogLevel)
+}
+
+// WatchConfig calls reload whenever a configuration file changes, until ctx
+// is cancelled.
+func WatchConfig(ctx context.Context, reload func()) error {
+	if len(configFiles) == 0 {
+		<-ctx.Done()
+		return nil
+	}
+	return filewatch.Watch(ctx, configFiles, func() {
+		logger.Info("configuration file changed", zap.Strings("files", configFiles))
+		reload()
+	})
+}
+
+// RedactedConfig returns the configuration in effect by key, with secrets
+// redacted.
+func RedactedConfig() map[string]interface{} {
+	config := reflect.ValueOf(LiveConfig()).Elem()
+	values := make(map[string]interface{})
+	for _, field := range configFields() {
+		values[field.Tag.Get("mapstructure")] = displayValue(field, config.FieldByIndex(field.Index))
+	}
+	return values
+}
+
+// ReloadableKeys returns the settings that are applied on reload.
+func ReloadableKeys() []string {
+	var keys []string
+	for _, field := range configFields() {
+		if field.Tag.Get("reload") == "true" {
+			keys = append(keys, field.Tag.Get("mapstructure"))
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// displayValue returns the value of field to show in logs and responses.
+func displayValue(field reflect.StructField, value reflect.Value) interface{} {
+	if field.Tag.Get("secret") == "true" && !value.IsZero() {
+		return redacted
+	}
+	if duration, ok := value.Interface().(time.Duration); ok {
+		return duration.String()
+	}
+	return value.Interface()
+}
@@ -2,34 +2,139 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/glebarez/sqlite"
 	"github.com/go-redis/cache/v8"
 	"github.com/go-redis/redis/v8"
-	"github.com/jinzhu/gorm"
-	_ "github.com/lib/pq"
 	"go.uber.org/zap"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"greenbone-task/constants"
 	"greenbone-task/logger"
-	db "greenbone-task/models/db"
-	"log"
+	"greenbone-task/metrics"
+	"greenbone-task/migrations"
+	"greenbone-task/repositories"
+	"greenbone-task/tracing"
 	"sync"
 	"time"
 )
 
 var DbConnection *gorm.DB
 
+// Store is the repository layer used by the services.
+var Store repositories.Store
+
 func ConnectDB() {
-	var err error
+	err := retryWithBackoff(context.Background(), "database", func() error {
+		var err error
+		DbConnection, err = gorm.Open(dialector(), &gorm.Config{TranslateError: true})
+		return err
+	})
+	if err != nil {
+		logger.Error("Failed to connect to the Database", zap.Error(err))
+		DbConnection = nil
+		return
+	}
+
+	if Config.DBDriver == constants.DriverSQLite {
+		// SQLite allows a single writer, and every connection to ":memory:"
+		// opens a separate database, so share one connection.
+		sqlDB, err := DbConnection.DB()
+		if err != nil {
+			logger.Fatal("Failed to configure the Database", zap.Error(err))
+		}
+		sqlDB.SetMaxOpenConns(1)
+		sqlDB.SetConnMaxLifetime(0)
+		sqlDB.SetConnMaxIdleTime(0)
+	} else {
+		sqlDB, err := DbConnection.DB()
+		if err != nil {
+			logger.Fatal("Failed to configure the Database", zap.Error(err))
+		}
+		sqlDB.SetMaxOpenConns(Config.DBMaxOpenConns)
+		sqlDB.SetMaxIdleConns(Config.DBMaxIdleConns)
+		sqlDB.SetConnMaxLifetime(Config.DBConnMaxLifetime)
+	}
+	if err := DbConnection.Use(repositories.TimeoutPlugin{Timeout: Config.DBQueryTimeout}); err != nil {
+		logger.Fatal("Failed to register the database query timeout", zap.Error(err))
+	}
+	if err := DbConnection.Use(metrics.GormPlugin{}); err != nil {
+		logger.Fatal("Failed to register the database metrics", zap.Error(err))
+	}
+	if err := DbConnection.Use(tracing.GormPlugin{}); err != nil {
+		logger.Fatal("Failed to register the database tracing", zap.Error(err))
+	}
+	Store = repositories.NewGormStore(DbConnection)
+
+	if Config.DBAutoMigrate {
+		if err := MigrateDB(); err != nil {
+			logger.Fatal("Failed to migrate the Database", zap.Error(err))
+		}
+	}
+}
+
+// PingDB checks that the database accepts connections.
+func PingDB(ctx context.Context) error {
+	if DbConnection == nil {
+		return errors.New("database is not connected")
+	}
+	sqlDB, err := DbConnection.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// CloseDB closes the database connection pool.
+func CloseDB() error {
+	if DbConnection == nil {
+		return nil
+	}
+	sqlDB, err := DbConnection.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// dialector returns the gorm dialector for the configured storage driver.
+func dialector() gorm.Dialector {
+	if Config.DBDriver == constants.DriverSQLite {
+		return sqlite.Open(Config.SQLitePath + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	}
+
 	dsn := fmt.Sprintf("user=%s password=%s host=%s port=%s dbname=%s sslmode=disable",
 		Config.DBUserName, Config.DBUserPassword, Config.DBHost, Config.DBPort, Config.DBName)
+	return postgres.Open(dsn)
+}
 
-	DbConnection, err = gorm.Open("postgres", dsn)
+// NewMigrator returns a migrator for the connected database.
+func NewMigrator() (*migrations.Migrator, error) {
+	sqlDB, err := DbConnection.DB()
 	if err != nil {
-		logger.Error("Failed to connect to the Database", zap.Error(err))
+		return nil, err
+	}
+	return migrations.New(sqlDB, Config.DBDriver)
+}
+
+// MigrateDB applies all pending schema migrations.
+func MigrateDB() error {
+	migrator, err := NewMigrator()
+	if err != nil {
+		return err
 	}
 
-	DbConnection.AutoMigrate(&db.Computer{})
-	DbConnection.AutoMigrate(&db.Employee{})
-	DbConnection.AutoMigrate(&db.Token{})
+	applied, err := migrator.Up(context.Background())
+	for _, migration := range applied {
+		logger.Info("applied migration", zap.Int64("version", migration.Version), zap.String("name", migration.Name))
+	}
+	return err
+}
+
+// cacheEnabled reports whether results should be cached in Redis.
+func cacheEnabled() bool {
+	return Config != nil && Config.UseRedis
 }
 
 var redisDefaultClient *redis.Client
@@ -40,10 +145,17 @@ var redisCacheOnce sync.Once
 
 func GetRedisDefaultClient() *redis.Client {
 	redisDefaultOnce.Do(func() {
+		// a zero timeout keeps the defaults of go-redis
 		redisDefaultClient = redis.NewClient(&redis.Options{
-			Addr:     Config.RedisDefaultAddr,
-			Password: Config.RedisPassword,
+			Addr:         Config.RedisDefaultAddr,
+			Password:     Config.RedisPassword,
+			DialTimeout:  Config.RedisTimeout,
+			ReadTimeout:  Config.RedisTimeout,
+			WriteTimeout: Config.RedisTimeout,
+			PoolTimeout:  Config.RedisTimeout,
+			PoolSize:     Config.RedisPoolSize,
 		})
+		redisDefaultClient.AddHook(tracing.RedisHook{})
 	})
 
 	return redisDefaultClient
@@ -53,19 +165,61 @@ func GetRedisCache() *cache.Cache {
 	redisCacheOnce.Do(func() {
 		redisCache = cache.New(&cache.Options{
 			Redis:      GetRedisDefaultClient(),
-			LocalCache: cache.NewTinyLFU(1000, time.Minute),
+			LocalCache: cache.NewTinyLFU(Config.LocalCacheSize, Config.LocalCacheTTL),
 		})
 	})
 
 	return redisCache
 }
 
-func CheckRedisConnection() {
-	redisClient := GetRedisDefaultClient()
-	err := redisClient.Ping(context.Background()).Err()
+// CloseRedis closes the Redis connection pool if it has been opened.
+func CloseRedis() error {
+	if redisDefaultClient == nil {
+		return nil
+	}
+	return redisDefaultClient.Close()
+}
+
+// PingRedis checks that Redis answers.
+func PingRedis(ctx context.Context) error {
+	return GetRedisDefaultClient().Ping(ctx).Err()
+}
+
+// CheckRedisConnection waits for Redis to answer, retrying with backoff.
+func CheckRedisConnection() error {
+	err := retryWithBackoff(context.Background(), "redis", func() error {
+		return PingRedis(context.Background())
+	})
 	if err != nil {
-		log.Fatal("Failed to connected redis:", err.Error())
+		logger.Error("Failed to connect to Redis", zap.Error(err))
+		return err
 	}
 
 	logger.Info("Connected to Redis!")
+	return nil
+}
+
+// retryWithBackoff calls fn until it succeeds or Config.StartupRetries
+// retries are used up, doubling the delay between attempts.
+func retryWithBackoff(ctx context.Context, dependency string, fn func() error) error {
+	delay := 500 * time.Millisecond
+	const maxDelay = 10 * time.Second
+
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= Config.StartupRetries {
+			return err
+		}
+
+		logger.Info("dependency not available, retrying",
+			zap.String("dependency", dependency), zap.Int("attempt", attempt+1), zap.Duration("delay", delay), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+		if delay *= 2; delay > maxDelay {
+			delay = maxDelay
+		}
+	}
 }
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"go.uber.org/zap"
+	"greenbone-task/logger"
+	"greenbone-task/models"
+	db "greenbone-task/models/db"
+)
+
+// subscribeSideEffects subscribes the side effects of the inventory changes
+// to bus: the webhook deliveries, which are stored with the change, the cache
+// invalidation and the audit log, which are done once the change is committed
+// but before the service returns, so that no later read sees the cached
+// computer as it was, and the administrator notification, which follows on
+// the workers of the bus.
+func subscribeSideEffects(bus *EventBus) *EventBus {
+	SubscribeSync(bus, "webhooks", func(ctx context.Context, tx EventTx, event ComputerCreated) error {
+		return enqueueWebhookEvent(ctx, tx, db.EventComputerCreated, models.ComputerEventData{Computer: models.NewComputerDTO(event.Computer)})
+	})
+	SubscribeSync(bus, "webhooks", func(ctx context.Context, tx EventTx, event ComputerReassigned) error {
+		return enqueueWebhookEvent(ctx, tx, db.EventComputerReassigned, models.ComputerEventData{
+			Computer:               models.NewComputerDTO(event.Computer),
+			PreviousEmployeeAbbrev: event.PreviousEmployeeAbbrev,
+		})
+	})
+	SubscribeSync(bus, "webhooks", func(ctx context.Context, tx EventTx, event ComputerDeleted) error {
+		return enqueueWebhookEvent(ctx, tx, db.EventComputerDeleted, models.ComputerEventData{Computer: models.NewComputerDTO(event.Computer)})
+	})
+
+	SubscribeSync(bus, "cache", func(ctx context.Context, tx EventTx, event ComputerCreated) error {
+		invalidateComputerCacheAfterCommit(ctx, tx, event.Computer.ID, event.Computer.EmployeeAbbrev)
+		return nil
+	})
+	SubscribeSync(bus, "cache", func(ctx context.Context, tx EventTx, event ComputerReassigned) error {
+		invalidateComputerCacheAfterCommit(ctx, tx, event.Computer.ID, event.Computer.EmployeeAbbrev, event.PreviousEmployeeAbbrev)
+		return nil
+	})
+	SubscribeSync(bus, "cache", func(ctx context.Context, tx EventTx, event ComputerDeleted) error {
+		invalidateComputerCacheAfterCommit(ctx, tx, event.Computer.ID, event.Computer.EmployeeAbbrev)
+		return nil
+	})
+
+	SubscribeAsync(bus, "notification", notifyOverQuota)
+
+	SubscribeSync(bus, "audit", auditAfterCommit[ComputerCreated])
+	SubscribeSync(bus, "audit", auditAfterCommit[ComputerReassigned])
+	SubscribeSync(bus, "audit", auditAfterCommit[ComputerDeleted])
+	SubscribeSync(bus, "audit", auditAfterCommit[EmployeeCreated])
+	return bus
+}
+
+// notifyOverQuota notifies the system administrator when an employee has
+// COMPUTER_QUOTA or more computers.
+func notifyOverQuota(ctx context.Context, event ComputerCreated) error {
+	if event.EmployeeComputers < computerQuota() {
+		return nil
+	}
+	abbrev := event.Computer.EmployeeAbbrev
+	message := fmt.Sprintf("Employee %s already has %d computers assigned.", abbrev, event.EmployeeComputers)
+	if err := Notifier.NotifySystemAdministrator(ctx, abbrev, message); err != nil {
+		return fmt.Errorf("error notifying system administrator about %s: %w", abbrev, err)
+	}
+	return nil
+}
+
+// invalidateComputerCache drops the cached computer and the cached computer
+// lists of the employees.
+func invalidateComputerCache(ctx context.Context, computerID uint, employeeAbbrevs ...string) error {
+	if !cacheEnabled() {
+		return nil
+	}
+	keys := []string{fmt.Sprintf("computer:%d", computerID)}
+	for _, abbrev := range employeeAbbrevs {
+		if abbrev != "" {
+			keys = append(keys, employeeComputersCacheKey(abbrev))
+		}
+	}
+	if err := GetRedisDefaultClient().Del(ctx, keys...).Err(); err != nil {
+		return UpstreamError(CodeCacheUnavailable, err, "error invalidating cached computers %v", keys)
+	}
+	return nil
+}
+
+// invalidateComputerCacheAfterCommit invalidates the cached computers once tx
+// is committed. A failure is logged; the entries expire with their TTL.
+func invalidateComputerCacheAfterCommit(ctx context.Context, tx EventTx, computerID uint, employeeAbbrevs ...string) {
+	tx.AfterCommit(func() {
+		if err := invalidateComputerCache(ctx, computerID, employeeAbbrevs...); err != nil {
+			logger.FromContext(ctx).Warn("failed to invalidate cached computers", zap.Error(err))
+		}
+	})
+}
+
+// auditAfterCommit audits an event once tx is committed.
+func auditAfterCommit[E Event](ctx context.Context, tx EventTx, event E) error {
+	tx.AfterCommit(func() { audit(ctx, event) })
+	return nil
+}
+
+// audit logs an event with the user who caused it, whose ID is in the logger
+// of the request.
+func audit(ctx context.Context, event Event) {
+	fields := []zap.Field{zap.String("event", event.EventName()), zap.String("aggregate", event.AggregateID())}
+	switch event := event.(type) {
+	case ComputerCreated:
+		fields = append(fields, zap.String("mac_address", event.Computer.MacAddress), zap.String("employee", event.Computer.EmployeeAbbrev))
+	case ComputerReassigned:
+		fields = append(fields, zap.String("from", event.PreviousEmployeeAbbrev), zap.String("to", event.Computer.EmployeeAbbrev))
+	case ComputerDeleted:
+		fields = append(fields, zap.String("mac_address", event.Computer.MacAddress), zap.String("employee", event.Computer.EmployeeAbbrev))
+	case EmployeeCreated:
+		fields = append(fields, zap.String("email", event.Employee.Email))
+	}
+	logger.FromContext(ctx).Info("audit", fields...)
+}
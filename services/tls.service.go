@@ -0,0 +1,43 @@
+package services
+
+import (
+	"crypto/x509"
+	"greenbone-task/certs"
+)
+
+// Identity is a client authenticated with a certificate.
+type Identity struct {
+	Name string // common name of the certificate
+	Role string // constants.RoleAdmin or constants.RoleReadOnly
+}
+
+// TLSEnabled reports whether the server terminates TLS itself.
+func TLSEnabled() bool {
+	return Config.TLSCertFile != ""
+}
+
+// LoadCertificates reads the server certificate and the client CA of Config.
+func LoadCertificates() (*certs.Reloader, error) {
+	return certs.Load(certs.Options{
+		CertFile:     Config.TLSCertFile,
+		KeyFile:      Config.TLSKeyFile,
+		ClientCAFile: Config.TLSClientCAFile,
+		ClientAuth:   Config.TLSClientAuth,
+	})
+}
+
+// ClientIdentity maps a verified client certificate to the role configured
+// for its common name in TLS_CLIENT_ROLES.
+func ClientIdentity(certificate *x509.Certificate) (Identity, error) {
+	roles, err := Config.ClientRoles()
+	if err != nil {
+		return Identity{}, err
+	}
+
+	name := certificate.Subject.CommonName
+	role, ok := roles[name]
+	if !ok {
+		return Identity{}, UnauthorizedError(CodeUnknownClient, "no role is configured for the client certificate %q", name)
+	}
+	return Identity{Name: name, Role: role}, nil
+}
@@ -1,17 +1,22 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/golang-jwt/jwt/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+	"greenbone-task/logger"
 	db "greenbone-task/models/db"
+	"greenbone-task/tracing"
 	"math/rand"
 	"strconv"
 	"time"
 )
 
 // CreateToken create a new token record
-func CreateToken(email string, tokenType string, expiresAt time.Time) (db.Token, error) {
+func CreateToken(ctx context.Context, email string, tokenType string, expiresAt time.Time) (db.Token, error) {
 	// Generate a random UUID
 	rand.Seed(time.Now().UnixNano())
 	ID := rand.Int63()
@@ -39,34 +44,44 @@ func CreateToken(email string, tokenType string, expiresAt time.Time) (db.Token,
 		Blacklisted: false,
 	}
 
-	if err := DbConnection.Create(&tokenModel).Error; err != nil {
-		return db.Token{}, fmt.Errorf("Cannot save access token to db", err.Error())
+	if err := Store.Tokens().Create(ctx, &tokenModel); err != nil {
+		return db.Token{}, fmt.Errorf("cannot save access token to db: %w", err)
 	}
 
 	return tokenModel, nil
 }
 
 // DeleteTokenById delete token with id
-func DeleteTokenById(tokenId int64) error {
-	token := &db.Token{}
+func DeleteTokenById(ctx context.Context, tokenId int64) error {
+	return Store.Tokens().Delete(ctx, tokenId)
+}
 
-	if err := DbConnection.Where("id = ?", tokenId).First(&token).Error; err != nil {
-		return err
+// DeleteExpiredTokens removes tokens that can no longer be used
+func DeleteExpiredTokens(ctx context.Context) error {
+	deleted, err := Store.Tokens().DeleteExpired(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("error deleting expired tokens: %w", err)
 	}
-	return DbConnection.Delete(token).Error
+	if deleted > 0 {
+		logger.FromContext(ctx).Info("deleted expired tokens", zap.Int64("count", deleted))
+	}
+	return nil
 }
 
 // GenerateAccessTokens generates "access" and "refresh" token for user
-func GenerateAccessTokens(email string) (db.Token, db.Token, error) {
+func GenerateAccessTokens(ctx context.Context, email string) (_ db.Token, _ db.Token, err error) {
+	ctx, span := tracing.Start(ctx, "services.GenerateAccessTokens")
+	defer tracing.End(span, &err)
+
 	accessExpiresAt := time.Now().Add(time.Duration(Config.JWTAccessExpirationMinutes) * time.Minute)
 	refreshExpiresAt := time.Now().Add(time.Duration(Config.JWTRefreshExpirationDays) * time.Hour * 24)
 
-	accessToken, err := CreateToken(email, db.TokenTypeAccess, accessExpiresAt)
+	accessToken, err := CreateToken(ctx, email, db.TokenTypeAccess, accessExpiresAt)
 	if err != nil {
 		return db.Token{}, db.Token{}, err
 	}
 
-	refreshToken, err := CreateToken(email, db.TokenTypeRefresh, refreshExpiresAt)
+	refreshToken, err := CreateToken(ctx, email, db.TokenTypeRefresh, refreshExpiresAt)
 	if err != nil {
 		return db.Token{}, db.Token{}, err
 	}
@@ -75,25 +90,32 @@ func GenerateAccessTokens(email string) (db.Token, db.Token, error) {
 }
 
 // VerifyToken checks jwt validity, expire date, blacklisted
-func VerifyToken(token string, tokenType string) (*db.Token, error) {
+func VerifyToken(ctx context.Context, token string, tokenType string) (_ *db.Token, err error) {
+	ctx, span := tracing.Start(ctx, "services.VerifyToken", attribute.String("token.type", tokenType))
+	defer tracing.End(span, &err)
+
 	claims := &db.UserClaims{}
-	_, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+	_, err = jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
 		return []byte(Config.JWTSecretKey), nil
 	})
 
 	if err != nil || claims.Type != tokenType {
-		return nil, errors.New("not valid token")
+		return nil, UnauthorizedError(CodeInvalidToken, "not valid token")
 	}
 
 	if time.Now().Sub(claims.ExpiresAt.Time) > 10*time.Second {
-		return nil, errors.New("token is expired")
+		return nil, UnauthorizedError(CodeTokenExpired, "token is expired")
 	}
 
-	tokenModel := &db.Token{}
-	userId := claims.Subject
+	userId, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil {
+		return nil, UnauthorizedError(CodeInvalidToken, "not valid token")
+	}
 
-	if err := DbConnection.Where("id = ? AND type >= ? AND blacklisted = ?", userId, tokenType, false).First(&tokenModel).Error; err != nil {
-		return &db.Token{}, errors.New("cannot find token")
+	tokenModel, err := Store.Tokens().FindActive(ctx, userId, tokenType)
+	if err != nil {
+		return &db.Token{}, UnauthorizedError(CodeInvalidToken, "cannot find token")
 	}
+	tokenModel.Email = claims.Email
 	return tokenModel, nil
 }
@@ -0,0 +1,18 @@
+package services
+
+import (
+	"context"
+	"greenbone-task/tracing"
+)
+
+// InitTracing sets up trace propagation and the configured span exporter. The
+// returned function flushes the remaining spans on shutdown.
+func InitTracing() (func(ctx context.Context) error, error) {
+	return tracing.Setup(tracing.Options{
+		Exporter:     Config.TracingExporter,
+		File:         Config.TracingFile,
+		OTLPEndpoint: Config.TracingOTLPEndpoint,
+		OTLPInsecure: Config.TracingOTLPInsecure,
+		SampleRatio:  Config.TracingSampleRatio,
+	})
+}
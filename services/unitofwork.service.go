@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	"greenbone-task/repositories"
+)
+
+// unitOfWork gives a service operation repositories that share a single
+// database transaction, and defers side effects until that transaction
+// has been committed.
+type unitOfWork struct {
+	repositories.Store
+	afterCommit []func()
+}
+
+// AfterCommit registers fn to run once the transaction has been committed.
+// It is never called if the unit of work is rolled back.
+func (uow *unitOfWork) AfterCommit(fn func()) {
+	uow.afterCommit = append(uow.afterCommit, fn)
+}
+
+// Publish hands event to the subscribers of Events: to the synchronous ones
+// now, so their changes are part of the transaction, and to the asynchronous
+// ones once it has been committed.
+func (uow *unitOfWork) Publish(ctx context.Context, event Event) error {
+	return Events.publish(ctx, uow, event)
+}
+
+// runUnitOfWork runs fn in a transaction. Everything fn writes through the
+// unit of work is rolled back if it returns an error.
+func runUnitOfWork(ctx context.Context, fn func(uow *unitOfWork) error) error {
+	var uow *unitOfWork
+	err := Store.Transaction(ctx, func(tx repositories.Store) error {
+		uow = &unitOfWork{Store: tx}
+		return fn(uow)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, fn := range uow.afterCommit {
+		fn()
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"greenbone-task/models"
+	db "greenbone-task/models/db"
+	"greenbone-task/repositories"
+	"greenbone-task/tracing"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers of the requests sent to webhooks.
+const (
+	WebhookEventHeader     = "X-Inventory-Event"
+	WebhookDeliveryHeader  = "X-Inventory-Delivery"
+	WebhookTimestampHeader = "X-Inventory-Timestamp"
+	WebhookSignatureHeader = "X-Inventory-Signature"
+)
+
+// CodeWebhookNotFound and CodeDeliveryNotFound are returned for unknown
+// webhooks and deliveries.
+const (
+	CodeWebhookNotFound  = "webhook_not_found"
+	CodeDeliveryNotFound = "delivery_not_found"
+)
+
+// webhookDeliveryBatch is how many due deliveries a worker sends in one run.
+const webhookDeliveryBatch = 50
+
+// webhookDeliveryLimit is how many deliveries of a webhook are listed.
+const webhookDeliveryLimit = 100
+
+// SignWebhookPayload returns the signature of a webhook request: the hex
+// HMAC-SHA256 of the timestamp, a dot and the body, keyed with the secret of
+// the webhook, prefixed with "sha256=".
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// CreateWebhook subscribes a URL to events. The returned webhook holds the
+// secret, which is not shown again.
+func CreateWebhook(ctx context.Context, request models.WebhookRequest) (_ *db.Webhook, err error) {
+	ctx, span := tracing.Start(ctx, "services.CreateWebhook")
+	defer tracing.End(span, &err)
+
+	secret := request.Secret
+	if secret == "" {
+		if secret, err = newWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+	webhook := &db.Webhook{
+		URL:        request.URL,
+		Secret:     secret,
+		EventTypes: strings.Join(request.EventTypes, ","),
+		Active:     request.Active == nil || *request.Active,
+	}
+	if err := Store.Webhooks().Create(ctx, webhook); err != nil {
+		return nil, fmt.Errorf("error creating webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+// GetWebhooks returns all webhooks.
+func GetWebhooks(ctx context.Context) (_ []db.Webhook, err error) {
+	ctx, span := tracing.Start(ctx, "services.GetWebhooks")
+	defer tracing.End(span, &err)
+
+	return Store.Webhooks().FindAll(ctx)
+}
+
+// GetWebhook returns a webhook by ID.
+func GetWebhook(ctx context.Context, id uint) (_ *db.Webhook, err error) {
+	ctx, span := tracing.Start(ctx, "services.GetWebhook", attribute.Int64("webhook.id", int64(id)))
+	defer tracing.End(span, &err)
+
+	return findWebhook(ctx, Store, id)
+}
+
+// UpdateWebhook changes the URL, the events and the state of a webhook, and
+// its secret if the request has one.
+func UpdateWebhook(ctx context.Context, id uint, request models.WebhookRequest) (_ *db.Webhook, err error) {
+	ctx, span := tracing.Start(ctx, "services.UpdateWebhook", attribute.Int64("webhook.id", int64(id)))
+	defer tracing.End(span, &err)
+
+	webhook, err := findWebhook(ctx, Store, id)
+	if err != nil {
+		return nil, err
+	}
+	webhook.URL = request.URL
+	webhook.EventTypes = strings.Join(request.EventTypes, ",")
+	if request.Secret != "" {
+		webhook.Secret = request.Secret
+	}
+	if request.Active != nil {
+		webhook.Active = *request.Active
+	}
+	if err := Store.Webhooks().Update(ctx, webhook); err != nil {
+		return nil, fmt.Errorf("error updating webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+// DeleteWebhook deletes a webhook with its deliveries.
+func DeleteWebhook(ctx context.Context, id uint) (err error) {
+	ctx, span := tracing.Start(ctx, "services.DeleteWebhook", attribute.Int64("webhook.id", int64(id)))
+	defer tracing.End(span, &err)
+
+	err = Store.Webhooks().Delete(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return NotFoundError(CodeWebhookNotFound, "no webhook found with ID: %d", id)
+	}
+	return err
+}
+
+// GetWebhookDeliveries returns the last deliveries of a webhook, newest
+// first, only those with status unless it is empty.
+func GetWebhookDeliveries(ctx context.Context, webhookID uint, status string) (_ []db.WebhookDelivery, err error) {
+	ctx, span := tracing.Start(ctx, "services.GetWebhookDeliveries", attribute.Int64("webhook.id", int64(webhookID)))
+	defer tracing.End(span, &err)
+
+	if _, err := findWebhook(ctx, Store, webhookID); err != nil {
+		return nil, err
+	}
+	return Store.Webhooks().FindDeliveries(ctx, webhookID, status, webhookDeliveryLimit)
+}
+
+// GetWebhookDelivery returns a delivery of a webhook with the log of its
+// attempts.
+func GetWebhookDelivery(ctx context.Context, webhookID uint, deliveryID uint) (_ *db.WebhookDelivery, _ []db.WebhookAttempt, err error) {
+	ctx, span := tracing.Start(ctx, "services.GetWebhookDelivery", attribute.Int64("webhook.id", int64(webhookID)))
+	defer tracing.End(span, &err)
+
+	delivery, err := findDelivery(ctx, webhookID, deliveryID)
+	if err != nil {
+		return nil, nil, err
+	}
+	attempts, err := Store.Webhooks().FindAttempts(ctx, deliveryID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return delivery, attempts, nil
+}
+
+// ReplayWebhookDelivery sends the event of a delivery again as a new
+// delivery, e.g. once a dead one's receiver is fixed.
+func ReplayWebhookDelivery(ctx context.Context, webhookID uint, deliveryID uint) (_ *db.WebhookDelivery, err error) {
+	ctx, span := tracing.Start(ctx, "services.ReplayWebhookDelivery", attribute.Int64("webhook.id", int64(webhookID)))
+	defer tracing.End(span, &err)
+
+	original, err := findDelivery(ctx, webhookID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	replay := &db.WebhookDelivery{
+		WebhookID:     original.WebhookID,
+		EventID:       original.EventID,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		Status:        db.DeliveryPending,
+		NextAttemptAt: &now,
+	}
+	if err := Store.Webhooks().CreateDelivery(ctx, replay); err != nil {
+		return nil, fmt.Errorf("error replaying delivery: %w", err)
+	}
+	WebhookWorker.Wake()
+	return replay, nil
+}
+
+func findWebhook(ctx context.Context, store repositories.Store, id uint) (*db.Webhook, error) {
+	webhook, err := store.Webhooks().FindByID(ctx, id)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil, NotFoundError(CodeWebhookNotFound, "no webhook found with ID: %d", id)
+	}
+	return webhook, err
+}
+
+func findDelivery(ctx context.Context, webhookID uint, deliveryID uint) (*db.WebhookDelivery, error) {
+	delivery, err := Store.Webhooks().FindDelivery(ctx, deliveryID)
+	if errors.Is(err, repositories.ErrNotFound) || (err == nil && delivery.WebhookID != webhookID) {
+		return nil, NotFoundError(CodeDeliveryNotFound, "webhook %d has no delivery with ID: %d", webhookID, deliveryID)
+	}
+	return delivery, err
+}
+
+func newWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("error generating webhook secret: %w", err)
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// enqueueWebhookEvent stores a delivery of an event for every webhook that
+// subscribes to it within tx, so that the deliveries are only sent if the
+// change is committed.
+func enqueueWebhookEvent(ctx context.Context, tx EventTx, eventType string, data any) error {
+	webhooks, err := tx.Webhooks().FindAll(ctx)
+	if err != nil {
+		return fmt.Errorf("error finding webhooks: %w", err)
+	}
+
+	var payload []byte
+	now := time.Now()
+	event := models.WebhookEvent{ID: uuid.NewString(), Type: eventType, OccurredAt: now.UTC(), Data: data}
+	for _, webhook := range webhooks {
+		if !webhook.Subscribes(eventType) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(event); err != nil {
+				return fmt.Errorf("error encoding %s event: %w", eventType, err)
+			}
+		}
+		err := tx.Webhooks().CreateDelivery(ctx, &db.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventID:       event.ID,
+			EventType:     eventType,
+			Payload:       string(payload),
+			Status:        db.DeliveryPending,
+			NextAttemptAt: &now,
+		})
+		if err != nil {
+			return fmt.Errorf("error storing %s delivery: %w", eventType, err)
+		}
+	}
+	if payload != nil {
+		tx.AfterCommit(WebhookWorker.Wake)
+	}
+	return nil
+}
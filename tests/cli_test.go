@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+	"greenbone-task/admin"
+	"greenbone-task/cli"
+	db "greenbone-task/models/db"
+	"greenbone-task/routes"
+	"greenbone-task/services"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// inventoryctl runs the CLI against a server with a token cache of its own.
+type inventoryctl struct {
+	t          *testing.T
+	server     string
+	tokenCache string
+}
+
+func newInventoryctl(t *testing.T) *inventoryctl {
+	setupSQLiteServices(t)
+	routes.InitGin()
+	server := httptest.NewServer(routes.New())
+	t.Cleanup(server.Close)
+	return &inventoryctl{t: t, server: server.URL, tokenCache: filepath.Join(t.TempDir(), "tokens.json")}
+}
+
+// run runs inventoryctl with args and returns its exit code and output.
+func (c *inventoryctl) run(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	args = append([]string{"--server", c.server, "--token-cache", c.tokenCache}, args...)
+	code := cli.Run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// ok runs inventoryctl with args, requires it to succeed and returns its output.
+func (c *inventoryctl) ok(args ...string) string {
+	code, stdout, stderr := c.run(args...)
+	require.Equal(c.t, 0, code, "inventoryctl %s: %s", strings.Join(args, " "), stderr)
+	return stdout
+}
+
+func TestInventoryctlManagesComputers(t *testing.T) {
+	ctl := newInventoryctl(t)
+
+	code, _, stderr := ctl.run("computers", "list")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "not logged in")
+
+	assert.Contains(t, ctl.ok("login", "--email", "helpdesk@example.com"), "logged in")
+	info, err := os.Stat(ctl.tokenCache)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	for _, abbreviation := range []string{"JDE", "AJK"} {
+		ctl.ok("employees", "create", "--first-name", "Test", "--last-name", abbreviation,
+			"--email", abbreviation+"@example.com", "--abbreviation", abbreviation)
+	}
+
+	var created cli.Computer
+	out := ctl.ok("computers", "create", "-o", "json", "--mac", "12:34:56:78:90:a0", "--name", "John's computer",
+		"--ip", "192.168.1.103", "--employee", "JDE")
+	require.NoError(t, json.Unmarshal([]byte(out), &created))
+	require.NotZero(t, created.ID)
+	assert.Equal(t, "JDE", created.EmployeeAbbrev)
+	id := fmt.Sprint(created.ID)
+
+	var fetched cli.Computer
+	require.NoError(t, yaml.Unmarshal([]byte(ctl.ok("computers", "get", id, "--output", "yaml")), &fetched))
+	assert.Equal(t, created, fetched)
+
+	table := ctl.ok("computers", "list")
+	assert.Contains(t, table, "MAC ADDRESS")
+	assert.Contains(t, table, "12:34:56:78:90:a0")
+
+	ctl.ok("computers", "assign", id, "AJK")
+	var assigned []cli.Computer
+	require.NoError(t, json.Unmarshal([]byte(ctl.ok("-o", "json", "employees", "computers", "AJK")), &assigned))
+	require.Len(t, assigned, 1)
+	assert.Equal(t, created.ID, assigned[0].ID)
+
+	// problems are reported with their code
+	code, _, stderr = ctl.run("computers", "assign", id, "NOPE")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "(employee_not_found)")
+
+	code, _, stderr = ctl.run("computers", "get", "abc")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "invalid computer ID")
+
+	ctl.ok("logout")
+	code, _, _ = ctl.run("computers", "list")
+	assert.Equal(t, 1, code)
+}
+
+func TestInventoryctlExportsAndImports(t *testing.T) {
+	for _, format := range []string{"json", "yaml", "csv"} {
+		t.Run(format, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "computers."+format)
+			require.NoError(t, os.WriteFile(file, []byte(importFiles[format]), 0600))
+			exported := filepath.Join(t.TempDir(), "export."+format)
+
+			// import into one server, export and import the export into another
+			var computers [2][]cli.Computer
+			for i := range computers {
+				ctl := newInventoryctl(t)
+				ctl.ok("login", "--email", "helpdesk@example.com")
+				ctl.ok("employees", "create", "--first-name", "Test", "--last-name", "JDE",
+					"--email", "jde@example.com", "--abbreviation", "JDE")
+				assert.Contains(t, ctl.ok("import", file), "imported 2 computers")
+				ctl.ok("export", "--file", exported)
+				require.NoError(t, json.Unmarshal([]byte(ctl.ok("export", "--format", "json")), &computers[i]))
+				file = exported
+			}
+
+			require.Len(t, computers[0], 2)
+			assert.Equal(t, "spare", computers[0][1].Description)
+			assert.Equal(t, computers[0], computers[1])
+		})
+	}
+}
+
+var importFiles = map[string]string{
+	"json": `[
+  {"mac_address": "10:00:00:00:00:01", "computer_name": "one", "ip_address": "10.0.0.1", "employee_abbrev": "JDE"},
+  {"mac_address": "10:00:00:00:00:02", "computer_name": "two", "ip_address": "10.0.0.2", "employee_abbrev": "JDE", "description": "spare"}
+]`,
+	"yaml": `
+- mac_address: "10:00:00:00:00:01"
+  computer_name: one
+  ip_address: 10.0.0.1
+  employee_abbrev: JDE
+- mac_address: "10:00:00:00:00:02"
+  computer_name: two
+  ip_address: 10.0.0.2
+  employee_abbrev: JDE
+  description: spare
+`,
+	"csv": `computer_name,mac_address,ip_address,employee_abbrev,description
+one,10:00:00:00:00:01,10.0.0.1,JDE,
+two,10:00:00:00:00:02,10.0.0.2,JDE,spare
+`,
+}
+
+func TestReconcileAssignments(t *testing.T) {
+	store, _ := setupMemoryServices(t)
+	ctx := context.Background()
+	createEmployee(t, "JDE")
+	createEmployee(t, "AJK")
+	jde, err := store.Employees().FindByAbbrev(ctx, "JDE")
+	require.NoError(t, err)
+
+	// assigned to JDE but labelled AJK, labelled JDE but unassigned, and
+	// labelled with an unknown employee
+	computers := []db.Computer{newComputer(1, "AJK"), newComputer(2, "JDE"), newComputer(3, "XYZ")}
+	for i := range computers {
+		require.NoError(t, store.Computers().Create(ctx, &computers[i]))
+	}
+	require.NoError(t, store.Computers().Assign(ctx, jde.ID, computers[0].ID))
+
+	findings, err := services.ReconcileAssignments(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, []services.ReconcileFinding{
+		{ComputerID: computers[0].ID, Action: services.ReconcileAbbreviationUpdated, From: "AJK", To: "JDE"},
+		{ComputerID: computers[1].ID, Action: services.ReconcileAssigned, To: "JDE"},
+		{ComputerID: computers[2].ID, Action: services.ReconcileUnknownEmployee, From: "XYZ"},
+	}, findings)
+	assigned, err := store.Computers().FindByEmployeeID(ctx, jde.ID)
+	require.NoError(t, err)
+	assert.Len(t, assigned, 1, "a dry run changes nothing")
+
+	findings, err = services.ReconcileAssignments(ctx, false)
+	require.NoError(t, err)
+	assert.Len(t, findings, 3)
+	assigned, err = store.Computers().FindByEmployeeID(ctx, jde.ID)
+	require.NoError(t, err)
+	assert.Len(t, assigned, 2)
+	for _, computer := range assigned {
+		assert.Equal(t, "JDE", computer.EmployeeAbbrev)
+	}
+
+	// only the unknown employee is left
+	findings, err = services.ReconcileAssignments(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, []services.ReconcileFinding{
+		{ComputerID: computers[2].ID, Action: services.ReconcileUnknownEmployee, From: "XYZ"},
+	}, findings)
+}
+
+func TestAdminCreateUserIssuesTokens(t *testing.T) {
+	previous := services.Config
+	t.Cleanup(func() { services.Config = previous })
+	path := filepath.Join(t.TempDir(), "inventory.db")
+	flags := []string{"--db-driver", "sqlite", "--sqlite-path", path, "--jwt-secret", "test-secret",
+		"--jwt-access-expiration-minutes", "10", "--jwt-refresh-expiration-days", "1"}
+
+	var stdout, stderr bytes.Buffer
+	require.Equal(t, 0, admin.Migrate(append(flags, "up"), &stdout, &stderr), stderr.String())
+
+	stdout.Reset()
+	require.Equal(t, 0, admin.CreateUser(append(flags, "helpdesk@example.com"), &stdout, &stderr), stderr.String())
+	assert.Contains(t, stdout.String(), "access token")
+	assert.Contains(t, stdout.String(), "refresh token")
+
+	assert.Equal(t, 2, admin.CreateUser(append(flags, "not-an-email"), &stdout, &stderr))
+
+	stdout.Reset()
+	require.Equal(t, 0, admin.Reconcile(append(flags, "--dry-run"), &stdout, &stderr), stderr.String())
+	assert.Contains(t, stdout.String(), "all computers are consistent")
+}
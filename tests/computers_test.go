@@ -1,23 +1,70 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/spf13/cast"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"greenbone-task/constants"
 	"greenbone-task/models"
 	db "greenbone-task/models/db"
+	"greenbone-task/repositories"
 	"greenbone-task/services"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 )
 
+// fakeNotifier records notifications instead of sending them.
+type fakeNotifier struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (n *fakeNotifier) NotifySystemAdministrator(_ context.Context, employeeAbbreviation string, message string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.messages = append(n.messages, employeeAbbreviation+": "+message)
+	return nil
+}
+
+func (n *fakeNotifier) count() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.messages)
+}
+
+// setupMemoryServices points the services at an in-memory store and a fake notifier.
+func setupMemoryServices(t *testing.T) (*repositories.MemoryStore, *fakeNotifier) {
+	store := repositories.NewMemoryStore()
+	notifier := &fakeNotifier{}
+
+	previousStore, previousNotifier, previousConfig := services.Store, services.Notifier, services.Config
+	services.Store = store
+	services.Notifier = notifier
+	services.Config = &models.EnvConfig{}
+	t.Cleanup(func() {
+		services.Store, services.Notifier, services.Config = previousStore, previousNotifier, previousConfig
+	})
+
+	return store, notifier
+}
+
+func createEmployee(t *testing.T, abbreviation string) {
+	err := services.CreateEmployee(context.Background(), models.EmployeeRequest{
+		FirstName:    "Test",
+		LastName:     abbreviation,
+		Abbreviation: abbreviation,
+		Email:        abbreviation + "@example.com",
+	})
+	require.NoError(t, err)
+}
+
 func TestCreateComputer(t *testing.T) {
-	services.LoadConfig()
-	services.ConnectDB()
+	setupMemoryServices(t)
+	ctx := context.Background()
 
 	employee := models.EmployeeRequest{
 		FirstName:    "Test",
@@ -25,7 +72,7 @@ func TestCreateComputer(t *testing.T) {
 		Abbreviation: "DTT",
 		Email:        "test.dummy3@tes2t.com",
 	}
-	err := services.CreateEmployee(employee)
+	err := services.CreateEmployee(ctx, employee)
 	require.NoError(t, err)
 
 	computer := db.Computer{
@@ -36,26 +83,59 @@ func TestCreateComputer(t *testing.T) {
 		Description:    "Custom-built PC1",
 	}
 
-	id, err := services.CreateComputer(computer)
+	id, err := services.CreateComputer(ctx, computer)
 	require.NoError(t, err)
 	require.NotEqual(t, uint(0), id)
 
+	computers, err := services.FindComputersByEmployeeAbbrev(ctx, employee.Abbreviation)
+	require.NoError(t, err)
+	require.Len(t, computers, 1)
+	assert.Equal(t, id, computers[0].ID)
+}
+
+func TestCreateComputerNotifiesAboveQuota(t *testing.T) {
+	_, notifier := setupMemoryServices(t)
+	ctx := context.Background()
+	createEmployee(t, "JDE")
+
+	// the administrator is informed once the employee has 3 or more computers
+	for i, expectedNotifications := range []int{0, 0, 1, 2} {
+		_, err := services.CreateComputer(ctx, db.Computer{
+			MacAddress:     fmt.Sprintf("00:00:00:00:00:0%d", i),
+			ComputerName:   "John's computer",
+			EmployeeAbbrev: "JDE",
+			IPAddress:      "192.168.1.10",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, expectedNotifications, notifier.count())
+	}
 }
 
 func TestGetAllComputers(t *testing.T) {
-	services.LoadConfig()
-	services.ConnectDB()
+	setupMemoryServices(t)
+	ctx := context.Background()
+	createEmployee(t, "JAD")
+
+	_, err := services.CreateComputer(ctx, db.Computer{
+		MacAddress:     "az:bx:cd:ed:ee:ff",
+		ComputerName:   "Dummy'ss Desktop",
+		EmployeeAbbrev: "JAD",
+		IPAddress:      "192.168.7.121",
+	})
+	require.NoError(t, err)
 
 	// Test
-	computers, err := services.GetAllComputers()
+	computers, err := services.GetAllComputers(ctx)
 	require.NoError(t, err)
 	require.NotNil(t, computers)
 	assert.True(t, len(computers) > 0)
 }
 
 func TestAssignComputerToEmployee(t *testing.T) {
-	services.LoadConfig()
-	services.ConnectDB()
+	store, _ := setupMemoryServices(t)
+	ctx := context.Background()
+	createEmployee(t, "JAD")
+	createEmployee(t, "JDE")
 
 	// Create test data
 	testEmployeeAbbrev := "JAD"
@@ -66,61 +146,91 @@ func TestAssignComputerToEmployee(t *testing.T) {
 		IPAddress:      "192.168.7.121",
 		Description:    "Custom-built PC1",
 	}
-	err := services.DbConnection.Create(testComputer).Error
+	err := store.Computers().Create(ctx, testComputer)
 	require.NoError(t, err)
 
 	// Test case 1: Assign computer to employee for the first time
-	err = services.AssignComputerToEmployee(cast.ToInt64(testComputer.ID), testEmployeeAbbrev)
+	_, err = services.AssignComputerToEmployee(ctx, cast.ToInt64(testComputer.ID), testEmployeeAbbrev)
 	require.NoError(t, err)
 
-	employee, err := services.FindByEmployeeAbbrev(testEmployeeAbbrev)
+	employee, err := services.FindByEmployeeAbbrev(ctx, testEmployeeAbbrev)
 	require.NoError(t, err)
 
-	var employeeComputer db.EmployeeComputer
-	err = services.DbConnection.Where("employee_id = ? AND computer_id = ?", employee.ID, testComputer.ID).First(&employeeComputer).Error
+	employeeComputer, err := store.Computers().FindAssignment(ctx, testComputer.ID)
 	require.NoError(t, err)
 	assert.Equal(t, employee.ID, employeeComputer.EmployeeID)
 
 	// Test case 2: Assign computer to a different employee
 	otherTestEmployeeAbbrev := "JDE"
-	otherEmployee, err := services.FindByEmployeeAbbrev(otherTestEmployeeAbbrev)
+	otherEmployee, err := services.FindByEmployeeAbbrev(ctx, otherTestEmployeeAbbrev)
 	require.NoError(t, err)
 
-	// Add existing relationship to the employee_computers table
-	existingEmployeeComputer := &db.EmployeeComputer{
-		EmployeeID: otherEmployee.ID,
-		ComputerID: testComputer.ID,
-	}
-	err = services.DbConnection.Create(existingEmployeeComputer).Error
+	assigned, err := services.AssignComputerToEmployee(ctx, cast.ToInt64(testComputer.ID), otherTestEmployeeAbbrev)
 	require.NoError(t, err)
+	assert.Equal(t, testComputer.ID, assigned.ID)
+	assert.Equal(t, otherTestEmployeeAbbrev, assigned.EmployeeAbbrev, "the computer is returned as assigned")
 
-	err = services.AssignComputerToEmployee(cast.ToInt64(testComputer.ID), testEmployeeAbbrev)
+	employeeComputer, err = store.Computers().FindAssignment(ctx, testComputer.ID)
 	require.NoError(t, err)
+	assert.Equal(t, otherEmployee.ID, employeeComputer.EmployeeID)
 
-	var newEmployeeComputer db.EmployeeComputer
-	err = services.DbConnection.Where("employee_id = ? AND computer_id = ?", employee.ID, testComputer.ID).First(&newEmployeeComputer).Error
+	_, err = services.AssignComputerToEmployee(ctx, cast.ToInt64(testComputer.ID), testEmployeeAbbrev)
 	require.NoError(t, err)
-	assert.Equal(t, employee.ID, newEmployeeComputer.EmployeeID)
+
+	employeeComputer, err = store.Computers().FindAssignment(ctx, testComputer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, employee.ID, employeeComputer.EmployeeID)
 
 	// Test case 3: Assign computer to the same employee
-	err = services.AssignComputerToEmployee(cast.ToInt64(testComputer.ID), testEmployeeAbbrev)
+	assigned, err = services.AssignComputerToEmployee(ctx, cast.ToInt64(testComputer.ID), testEmployeeAbbrev)
 	require.NoError(t, err)
+	assert.Equal(t, testEmployeeAbbrev, assigned.EmployeeAbbrev)
 
-	err = services.DbConnection.Where("employee_id = ? AND computer_id = ?", employee.ID, testComputer.ID).First(&newEmployeeComputer).Error
+	employeeComputer, err = store.Computers().FindAssignment(ctx, testComputer.ID)
 	require.NoError(t, err)
-	assert.Equal(t, employee.ID, newEmployeeComputer.EmployeeID)
+	assert.Equal(t, employee.ID, employeeComputer.EmployeeID)
+
+	// Test case 4: Assign computer to an unknown employee
+	_, err = services.AssignComputerToEmployee(ctx, cast.ToInt64(testComputer.ID), "NOPE")
+	assert.Error(t, err)
 
 	// Cleanup
-	err = services.DeleteComputer(cast.ToInt64(testComputer.ID))
+	err = services.DeleteComputer(ctx, cast.ToInt64(testComputer.ID))
+	require.NoError(t, err)
+
+	_, err = services.GetComputerByID(ctx, cast.ToInt64(testComputer.ID))
+	assert.Error(t, err)
+}
+
+func TestDeleteEmployeeComputer(t *testing.T) {
+	setupMemoryServices(t)
+	ctx := context.Background()
+	createEmployee(t, "JAD")
+	createEmployee(t, "JDE")
+
+	id, err := services.CreateComputer(ctx, db.Computer{
+		MacAddress:     "az:bx:cd:ed:ee:ff",
+		ComputerName:   "Dummy'ss Desktop",
+		EmployeeAbbrev: "JAD",
+		IPAddress:      "192.168.7.121",
+	})
 	require.NoError(t, err)
 
+	err = services.DeleteEmployeeComputer(ctx, cast.ToInt64(id), "JDE")
+	assert.Error(t, err)
+
+	err = services.DeleteEmployeeComputer(ctx, cast.ToInt64(id), "JAD")
+	require.NoError(t, err)
+
+	computers, err := services.GetAllComputers(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, computers)
 }
 
 func TestNotifySystemAdministrator(t *testing.T) {
 	// Set up test case
 	employeeAbbreviation := "JDOE"
 	message := "Warning: Disk space is running low"
-	ns := services.NewNotificationService()
 
 	// Mock HTTP server
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -141,11 +251,11 @@ func TestNotifySystemAdministrator(t *testing.T) {
 	}))
 	defer ts.Close()
 
-	// Set the NOTIFICATION_URL to the mock server URL
-	ts.URL = constants.NOTIFICATION_URL
+	// Point the notification service at the mock server
+	ns := services.NewNotificationServiceWithURL(ts.URL)
 
 	// Call the method being tested
-	err := ns.NotifySystemAdministrator(employeeAbbreviation, message)
+	err := ns.NotifySystemAdministrator(context.Background(), employeeAbbreviation, message)
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
 	}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"greenbone-task/services"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// loadConfig loads the configuration from args and restores the previous one
+// after the test.
+func loadConfig(t *testing.T, args ...string) ([]string, error) {
+	previous := services.Config
+	t.Cleanup(func() { services.Config = previous })
+	return services.LoadConfig(args)
+}
+
+func writeConfigFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestConfigLayersOverrideEachOther(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+db_driver: sqlite
+sqlite_path: ":memory:"
+jwt_secret: from-file
+jwt_access_expiration_minutes: 15
+jwt_refresh_expiration_days: 1
+server_port: "9000"
+log_level: debug
+computer_quota: 5
+`)
+	t.Setenv("SERVER_PORT", "9100")
+	t.Setenv("LOG_LEVEL", "error")
+
+	args, err := loadConfig(t, "--config", path, "--log-level", "warn", "up")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"up"}, args)
+
+	config := services.Config
+	assert.Equal(t, 30*time.Minute, config.CacheTTL, "default")
+	assert.Equal(t, 5, config.ComputerQuota, "file over default")
+	assert.Equal(t, "9100", config.ServerPort, "env over file")
+	assert.Equal(t, "warn", config.LogLevel, "flag over env")
+	assert.Equal(t, "from-file", config.JWTSecretKey)
+}
+
+func TestConfigReadsTOMLAndSecretFiles(t *testing.T) {
+	path := writeConfigFile(t, "config.toml", `
+db_driver = "postgres"
+postgres_host = "db"
+postgres_db = "inventory"
+jwt_access_expiration_minutes = 15
+jwt_refresh_expiration_days = 1
+server_read_timeout = "5s"
+`)
+	t.Setenv(services.ConfigFileEnv, path)
+	t.Setenv("JWT_SECRET_FILE", writeConfigFile(t, "jwt_secret", "s3cret\n"))
+	t.Setenv("POSTGRES_PASSWORD_FILE", writeConfigFile(t, "postgres_password", "p4ssword"))
+
+	_, err := loadConfig(t)
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, services.Config.ServerReadTimeout)
+	assert.Equal(t, "s3cret", services.Config.JWTSecretKey)
+	assert.Equal(t, "p4ssword", services.Config.DBUserPassword)
+}
+
+func TestConfigReportsEveryProblem(t *testing.T) {
+	t.Setenv("DB_DRIVER", "sqlite")
+	t.Setenv("DB_QUERY_TIMEOUT", "soon")
+	t.Setenv("JWT_SECRET_FILE", filepath.Join(t.TempDir(), "missing"))
+	t.Setenv("JWT_ACCESS_EXPIRATION_MINUTES", "15")
+	t.Setenv("JWT_REFRESH_EXPIRATION_DAYS", "1")
+
+	_, err := loadConfig(t, "--log-level", "loud", "--computer-quota", "-1")
+	var configErr *services.ConfigError
+	require.ErrorAs(t, err, &configErr)
+
+	require.Len(t, configErr.Problems, 4, configErr.Problems)
+	assert.Contains(t, configErr.Problems[0], "COMPUTER_QUOTA")
+	assert.Contains(t, configErr.Problems[1], "DB_QUERY_TIMEOUT")
+	assert.Contains(t, configErr.Problems[2], "JWT_SECRET_FILE")
+	assert.Contains(t, configErr.Problems[3], "LOG_LEVEL")
+}
+
+func TestConfigRejectsUnknownFlags(t *testing.T) {
+	_, err := loadConfig(t, "--no-such-flag")
+	require.Error(t, err)
+	var configErr *services.ConfigError
+	assert.False(t, errors.As(err, &configErr))
+}
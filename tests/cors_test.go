@@ -0,0 +1,108 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"greenbone-task/models"
+	"greenbone-task/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCORS(t *testing.T) {
+	setupSQLiteServices(t)
+	services.Config.CORSAllowedOrigins = "https://inventory.example.com, http://localhost:3000"
+	services.Config.CORSAllowCredentials = true
+	services.Config.CORSMaxAge = time.Hour
+	client := newAPIClient(t)
+
+	serve := func(method string, origin string, requestMethod string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/v1/computers", nil)
+		req.Header.Set("Origin", origin)
+		if requestMethod != "" {
+			req.Header.Set("Access-Control-Request-Method", requestMethod)
+			req.Header.Set("Access-Control-Request-Headers", "Bearer-Token, Content-Type")
+		}
+		recorder := httptest.NewRecorder()
+		client.router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	t.Run("preflight from an allowed origin", func(t *testing.T) {
+		recorder := serve(http.MethodOptions, "http://localhost:3000", http.MethodPost)
+		require.Equal(t, http.StatusNoContent, recorder.Code)
+		assert.Equal(t, "http://localhost:3000", recorder.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", recorder.Header().Get("Access-Control-Allow-Credentials"))
+		assert.Contains(t, recorder.Header().Get("Access-Control-Allow-Methods"), http.MethodPost)
+		assert.Contains(t, recorder.Header().Get("Access-Control-Allow-Headers"), "Bearer-Token")
+		assert.Contains(t, recorder.Header().Get("Access-Control-Allow-Headers"), "Authorization")
+		assert.Equal(t, "3600", recorder.Header().Get("Access-Control-Max-Age"))
+		assert.Contains(t, recorder.Header().Values("Vary"), "Origin")
+	})
+
+	t.Run("preflight from another origin", func(t *testing.T) {
+		recorder := serve(http.MethodOptions, "https://evil.example.com", http.MethodDelete)
+		assert.Equal(t, http.StatusForbidden, recorder.Code)
+		assert.Empty(t, recorder.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, models.ProblemContentType, recorder.Header().Get("Content-Type"))
+	})
+
+	t.Run("request from an allowed origin", func(t *testing.T) {
+		recorder := serve(http.MethodGet, "https://inventory.example.com", "")
+		assert.Equal(t, "https://inventory.example.com", recorder.Header().Get("Access-Control-Allow-Origin"))
+		assert.Contains(t, recorder.Header().Get("Access-Control-Expose-Headers"), "X-Request-ID")
+	})
+
+	t.Run("request from another origin", func(t *testing.T) {
+		recorder := serve(http.MethodGet, "https://evil.example.com", "")
+		assert.Empty(t, recorder.Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, recorder.Header().Get("Access-Control-Allow-Credentials"))
+	})
+
+	t.Run("any origin without credentials", func(t *testing.T) {
+		services.Config.CORSAllowedOrigins = "*"
+		recorder := serve(http.MethodGet, "https://any.example.com", "")
+		assert.Equal(t, "*", recorder.Header().Get("Access-Control-Allow-Origin"), "the origin is not echoed")
+		assert.Empty(t, recorder.Header().Get("Access-Control-Allow-Credentials"), "even if credentials are allowed")
+
+		recorder = serve(http.MethodOptions, "https://any.example.com", http.MethodPost)
+		require.Equal(t, http.StatusNoContent, recorder.Code)
+		assert.Equal(t, "*", recorder.Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, recorder.Header().Get("Access-Control-Allow-Credentials"))
+
+		services.Config.CORSAllowCredentials = false
+		recorder = serve(http.MethodGet, "https://any.example.com", "")
+		assert.Equal(t, "*", recorder.Header().Get("Access-Control-Allow-Origin"))
+		assert.Empty(t, recorder.Header().Get("Access-Control-Allow-Credentials"))
+	})
+}
+
+func TestCORSOriginsAreValidated(t *testing.T) {
+	config := models.EnvConfig{CORSAllowedOrigins: "https://inventory.example.com,*"}
+	assert.Equal(t, []string{"https://inventory.example.com", "*"}, config.CORSOrigins())
+
+	for _, invalid := range []string{"inventory.example.com", "https://inventory.example.com/app"} {
+		config := &models.EnvConfig{DBDriver: "sqlite", SQLitePath: ":memory:", JWTSecretKey: "secret",
+			JWTAccessExpirationMinutes: 1, JWTRefreshExpirationDays: 1, ComputerQuota: 3, NotificationQueueSize: 1,
+			CORSAllowedOrigins: invalid}
+		assert.Error(t, config.Validate(), invalid)
+	}
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	setupSQLiteServices(t)
+	services.Config.HSTSMaxAge = 365 * 24 * time.Hour
+	client := newAPIClient(t)
+
+	recorder := client.send(http.MethodGet, "/healthz", nil)
+	assert.Equal(t, "nosniff", recorder.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "DENY", recorder.Header().Get("X-Frame-Options"))
+	assert.Equal(t, "max-age=31536000; includeSubDomains", recorder.Header().Get("Strict-Transport-Security"))
+	assert.Equal(t, "default-src 'none'; frame-ancestors 'none'", recorder.Header().Get("Content-Security-Policy"))
+
+	recorder = client.send(http.MethodGet, "/swagger/index.html", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Header().Get("Content-Security-Policy"), "script-src 'self' 'unsafe-inline'")
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"greenbone-task/middlewares"
+	"greenbone-task/models"
+	"greenbone-task/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestErrorsAreProblemDetails(t *testing.T) {
+	setupSQLiteServices(t)
+	client := newAPIClient(t)
+
+	require.Equal(t, http.StatusCreated, client.do(http.MethodPost, "/v1/api/employees/", gin.H{
+		"first_name":   "John",
+		"last_name":    "Doe",
+		"email":        "john@example.com",
+		"abbreviation": "JDE",
+	}, nil))
+	computer := gin.H{"mac_address": "12:34:56:78:90:ab", "computer_name": "John's computer", "ip_address": "192.168.1.103", "employee_abbrev": "JDE"}
+	require.Equal(t, http.StatusCreated, client.do(http.MethodPost, "/v1/computers", computer, nil))
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		payload any
+		status  int
+		code    string
+	}{
+		{"duplicate MAC address", http.MethodPost, "/v1/computers", computer, http.StatusConflict, services.CodeDuplicateMacAddress},
+		{"unknown computer", http.MethodGet, "/v1/computers/999", nil, http.StatusNotFound, services.CodeComputerNotFound},
+		{"invalid computer ID", http.MethodGet, "/v1/computers/abc", nil, http.StatusBadRequest, services.CodeValidationFailed},
+		{"unknown employee", http.MethodGet, "/v1/api/employees/computers/XYZ", nil, http.StatusNotFound, services.CodeEmployeeNotFound},
+		{"computer of another employee", http.MethodDelete, "/v1/api/employees/computers/999/JDE", nil, http.StatusNotFound, services.CodeComputerNotAssigned},
+		{"unknown route", http.MethodGet, "/v1/unknown", nil, http.StatusNotFound, "route_not_found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := client.send(tt.method, tt.path, tt.payload)
+
+			var problem models.Problem
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &problem), recorder.Body.String())
+			assert.Equal(t, tt.status, recorder.Code)
+			assert.Equal(t, models.ProblemContentType, recorder.Header().Get("Content-Type"))
+			assert.Equal(t, tt.status, problem.Status)
+			assert.Equal(t, tt.code, problem.Code)
+			assert.Equal(t, "/problems/"+tt.code, problem.Type)
+			assert.Equal(t, http.StatusText(tt.status), problem.Title)
+			assert.NotEmpty(t, problem.Detail)
+			assert.Equal(t, recorder.Header().Get(middlewares.RequestIDHeader), problem.RequestID)
+		})
+	}
+
+	t.Run("field errors", func(t *testing.T) {
+		var problem models.Problem
+		require.Equal(t, http.StatusBadRequest, client.do(http.MethodPost, "/v1/computers", gin.H{"computer_name": "No address"}, &problem))
+		assert.Equal(t, services.CodeValidationFailed, problem.Code)
+		assert.Equal(t, map[string]string{"mac_address": "cannot be blank", "ip_address": "cannot be blank"}, problem.Errors)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		client := &apiClient{t: t, router: client.router, token: "invalid"}
+		var problem models.Problem
+		require.Equal(t, http.StatusUnauthorized, client.do(http.MethodGet, "/v1/computers", nil, &problem))
+		assert.Equal(t, services.CodeInvalidToken, problem.Code)
+	})
+}
+
+func TestProblemForMapsErrorKinds(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{services.NotFoundError(services.CodeComputerNotFound, "not found"), http.StatusNotFound, services.CodeComputerNotFound},
+		{services.ConflictError(services.CodeDuplicateEmployee, nil, "conflict"), http.StatusConflict, services.CodeDuplicateEmployee},
+		{services.ValidationError(services.CodeValidationFailed, nil, "invalid"), http.StatusBadRequest, services.CodeValidationFailed},
+		{services.QuotaExceededError("rate_limit_exceeded", "slow down"), http.StatusTooManyRequests, "rate_limit_exceeded"},
+		{services.UpstreamError(services.CodeCacheUnavailable, errors.New("connection refused"), "cache failed"), http.StatusBadGateway, services.CodeCacheUnavailable},
+		{services.UnauthorizedError(services.CodeTokenExpired, "expired"), http.StatusUnauthorized, services.CodeTokenExpired},
+		{fmt.Errorf("wrapped: %w", services.NotFoundError(services.CodeEmployeeNotFound, "not found")), http.StatusNotFound, services.CodeEmployeeNotFound},
+		{errors.New("pq: connection reset"), http.StatusInternalServerError, middlewares.CodeInternalError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+			problem := middlewares.ProblemFor(c, tt.err)
+			assert.Equal(t, tt.status, problem.Status)
+			assert.Equal(t, tt.code, problem.Code)
+			assert.NotContains(t, problem.Detail, "pq:", "internal errors must not leak")
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	db "greenbone-task/models/db"
+	"greenbone-task/services"
+	"sync"
+	"testing"
+	"time"
+)
+
+var (
+	redisServer     *miniredis.Miniredis
+	redisServerOnce sync.Once
+)
+
+// useRedis returns an empty Redis server for the test. The services create
+// their Redis client once, so all tests share a server that runs until the
+// tests end.
+func useRedis(t *testing.T) *miniredis.Miniredis {
+	redisServerOnce.Do(func() {
+		redisServer = miniredis.NewMiniRedis()
+		require.NoError(t, redisServer.Start())
+	})
+	redisServer.FlushAll()
+	return redisServer
+}
+
+// useEventBus makes the services publish to bus for the test.
+func useEventBus(t *testing.T, bus *services.EventBus) {
+	previous := services.Events
+	services.Events = bus
+	t.Cleanup(func() { services.Events = previous })
+}
+
+// reassignments records the ComputerReassigned events by aggregate.
+type reassignments struct {
+	mu     sync.Mutex
+	events map[string][]services.ComputerReassigned
+}
+
+func (r *reassignments) handle(_ context.Context, event services.ComputerReassigned) error {
+	// let the queues fill up
+	time.Sleep(time.Millisecond)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events[event.AggregateID()] = append(r.events[event.AggregateID()], event)
+	return nil
+}
+
+func TestSyncSubscriberFailureRollsBackChange(t *testing.T) {
+	for name, setup := range storeBackends {
+		t.Run(name, func(t *testing.T) {
+			notifier := setup(t)
+			ctx := context.Background()
+			bus := services.NewEventBus()
+			var handled []string
+			services.SubscribeAsync(bus, "recorder", func(_ context.Context, event services.ComputerCreated) error {
+				handled = append(handled, event.Computer.MacAddress)
+				return nil
+			})
+			services.SubscribeSync(bus, "failing", func(_ context.Context, _ services.EventTx, event services.ComputerCreated) error {
+				if event.Computer.MacAddress == newComputer(2, "JDE").MacAddress {
+					return errInjected
+				}
+				return nil
+			})
+			useEventBus(t, bus)
+			createEmployee(t, "JDE")
+
+			// without workers the asynchronous subscribers are done when the service returns
+			_, err := services.CreateComputer(ctx, newComputer(1, "JDE"))
+			require.NoError(t, err)
+			assert.Equal(t, []string{newComputer(1, "JDE").MacAddress}, handled)
+
+			_, err = services.CreateComputer(ctx, newComputer(2, "JDE"))
+			require.ErrorIs(t, err, errInjected)
+			count, err := services.CountComputersByEmployeeAbbreviation(ctx, "JDE")
+			require.NoError(t, err)
+			assert.EqualValues(t, 1, count, "the computer is rolled back")
+			assert.Len(t, handled, 1, "rolled back events are not handled after commit")
+			assert.Zero(t, notifier.count(), "the notification is a subscriber of the default bus only")
+		})
+	}
+}
+
+func TestAsyncSubscribersHandleEventsInOrderPerComputer(t *testing.T) {
+	const computers, reassignmentsPerComputer = 6, 8
+
+	setupMemoryServices(t)
+	ctx := context.Background()
+	bus := services.NewEventBus()
+	recorder := &reassignments{events: map[string][]services.ComputerReassigned{}}
+	services.SubscribeAsync(bus, "recorder", recorder.handle)
+	bus.Start(3, 1)
+	useEventBus(t, bus)
+
+	abbrevs := []string{"JDE", "AJK", "MMU"}
+	for _, abbrev := range abbrevs {
+		createEmployee(t, abbrev)
+	}
+	ids := make([]uint, computers)
+	for i := range ids {
+		id, err := services.CreateComputer(ctx, newComputer(i, abbrevs[0]))
+		require.NoError(t, err)
+		ids[i] = id
+	}
+
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id uint) {
+			defer wg.Done()
+			for n := 1; n <= reassignmentsPerComputer; n++ {
+				_, err := services.AssignComputerToEmployee(ctx, int64(id), abbrevs[n%len(abbrevs)])
+				assert.NoError(t, err)
+			}
+			// a failed reassignment publishes nothing
+			_, err := services.AssignComputerToEmployee(ctx, int64(id), "NOBODY")
+			assert.Error(t, err)
+		}(id)
+	}
+	wg.Wait()
+	require.NoError(t, bus.Stop(ctx))
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	require.Len(t, recorder.events, computers)
+	for _, id := range ids {
+		events := recorder.events[fmt.Sprintf("computer:%d", id)]
+		require.Len(t, events, reassignmentsPerComputer, "computer %d", id)
+		previous := abbrevs[0]
+		for n, event := range events {
+			assert.Equal(t, previous, event.PreviousEmployeeAbbrev, "computer %d, event %d", id, n)
+			assert.Equal(t, abbrevs[(n+1)%len(abbrevs)], event.Computer.EmployeeAbbrev, "computer %d, event %d", id, n)
+			previous = event.Computer.EmployeeAbbrev
+		}
+	}
+}
+
+func TestServicesPublishEvents(t *testing.T) {
+	for name, setup := range storeBackends {
+		t.Run(name, func(t *testing.T) {
+			setup(t)
+			ctx := context.Background()
+			bus := services.NewEventBus()
+			var mu sync.Mutex
+			var published []string
+			record := func(event services.Event) {
+				mu.Lock()
+				defer mu.Unlock()
+				published = append(published, event.EventName()+" "+event.AggregateID())
+			}
+			services.SubscribeAsync(bus, "recorder", func(_ context.Context, event services.EmployeeCreated) error { record(event); return nil })
+			services.SubscribeAsync(bus, "recorder", func(_ context.Context, event services.ComputerCreated) error { record(event); return nil })
+			services.SubscribeAsync(bus, "recorder", func(_ context.Context, event services.ComputerReassigned) error { record(event); return nil })
+			services.SubscribeAsync(bus, "recorder", func(_ context.Context, event services.ComputerDeleted) error { record(event); return nil })
+			bus.Start(2, 10)
+			useEventBus(t, bus)
+
+			createEmployee(t, "JDE")
+			createEmployee(t, "AJK")
+			id, err := services.CreateComputer(ctx, newComputer(1, "JDE"))
+			require.NoError(t, err)
+			_, err = services.AssignComputerToEmployee(ctx, int64(id), "AJK")
+			require.NoError(t, err)
+			_, err = services.AssignComputerToEmployee(ctx, int64(id), "AJK")
+			require.NoError(t, err, "an unchanged assignment publishes nothing")
+			require.NoError(t, services.DeleteEmployeeComputer(ctx, int64(id), "AJK"))
+			require.NoError(t, bus.Stop(ctx))
+
+			computer := fmt.Sprintf("computer:%d", id)
+			assert.ElementsMatch(t, []string{
+				"EmployeeCreated employee:JDE",
+				"EmployeeCreated employee:AJK",
+				"ComputerCreated " + computer,
+				"ComputerReassigned " + computer,
+				"ComputerDeleted " + computer,
+			}, published)
+		})
+	}
+}
+
+func TestReconcilePublishesReassignments(t *testing.T) {
+	for name, setup := range storeBackends {
+		t.Run(name, func(t *testing.T) {
+			setup(t)
+			ctx := context.Background()
+			bus := services.NewEventBus()
+			var published []services.ComputerReassigned
+			services.SubscribeAsync(bus, "recorder", func(_ context.Context, event services.ComputerReassigned) error {
+				published = append(published, event)
+				return nil
+			})
+			useEventBus(t, bus)
+
+			createEmployee(t, "JDE")
+			jde, err := services.Store.Employees().FindByAbbrev(ctx, "JDE")
+			require.NoError(t, err)
+			// assigned to JDE but labelled AJK, labelled JDE but unassigned, and
+			// labelled with an unknown employee
+			computers := []db.Computer{newComputer(1, "AJK"), newComputer(2, "JDE"), newComputer(3, "XYZ")}
+			for i := range computers {
+				require.NoError(t, services.Store.Computers().Create(ctx, &computers[i]))
+			}
+			require.NoError(t, services.Store.Computers().Assign(ctx, jde.ID, computers[0].ID))
+
+			_, err = services.ReconcileAssignments(ctx, true)
+			require.NoError(t, err)
+			assert.Empty(t, published, "a dry run publishes nothing")
+
+			_, err = services.ReconcileAssignments(ctx, false)
+			require.NoError(t, err)
+			require.Len(t, published, 2, "the unknown employee is left for a human")
+			assert.Equal(t, computers[0].ID, published[0].Computer.ID)
+			assert.Equal(t, "AJK", published[0].PreviousEmployeeAbbrev)
+			assert.Equal(t, "JDE", published[0].Computer.EmployeeAbbrev)
+			assert.Equal(t, computers[1].ID, published[1].Computer.ID)
+			assert.Empty(t, published[1].PreviousEmployeeAbbrev)
+			assert.Equal(t, "JDE", published[1].Computer.EmployeeAbbrev)
+		})
+	}
+}
+
+// blockingNotifier notifies once release is closed.
+type blockingNotifier struct {
+	release chan struct{}
+}
+
+func (n blockingNotifier) NotifySystemAdministrator(context.Context, string, string) error {
+	<-n.release
+	return nil
+}
+
+func TestCacheIsInvalidatedBeforeTheServiceReturns(t *testing.T) {
+	setupSQLiteServices(t)
+	server := useRedis(t)
+	services.Config.UseRedis = true
+	services.Config.RedisDefaultAddr = server.Addr()
+	services.Config.CacheTTL = time.Minute
+	services.Config.ComputerCacheTTL = time.Minute
+	// with a worker, as in the server, the asynchronous subscribers run after
+	// the services return; the notification about the new computer keeps it
+	// busy until the end of the test
+	services.Config.ComputerQuota = 1
+	release := make(chan struct{})
+	services.Notifier = blockingNotifier{release: release}
+	services.Events.Start(1, 10)
+	t.Cleanup(func() { require.NoError(t, services.Events.Stop(context.Background())) })
+	t.Cleanup(func() { close(release) })
+	ctx := context.Background()
+
+	createEmployee(t, "JDE")
+	createEmployee(t, "AJK")
+	id, err := services.CreateComputer(ctx, newComputer(1, "JDE"))
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		computer, err := services.GetComputerByID(ctx, int64(id))
+		require.NoError(t, err)
+		assert.Equal(t, "JDE", computer.EmployeeAbbrev)
+		computers, err := services.FindComputersByEmployeeAbbrev(ctx, "AJK")
+		require.NoError(t, err)
+		assert.Empty(t, computers)
+	}
+	require.True(t, server.Exists(fmt.Sprintf("computer:%d", id)), "the computer is cached")
+
+	_, err = services.AssignComputerToEmployee(ctx, int64(id), "AJK")
+	require.NoError(t, err)
+	computer, err := services.GetComputerByID(ctx, int64(id))
+	require.NoError(t, err)
+	assert.Equal(t, "AJK", computer.EmployeeAbbrev)
+	computers, err := services.FindComputersByEmployeeAbbrev(ctx, "AJK")
+	require.NoError(t, err)
+	assert.Len(t, computers, 1)
+
+	require.NoError(t, services.DeleteComputer(ctx, int64(id)))
+	_, err = services.GetComputerByID(ctx, int64(id))
+	assert.Error(t, err, "the deleted computer is not read from the cache")
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"greenbone-task/client"
+	db "greenbone-task/models/db"
+	"greenbone-task/repositories"
+	"greenbone-task/services"
+	"sync/atomic"
+	"testing"
+)
+
+// graphQL runs a request and decodes its data into out.
+func graphQL(t *testing.T, api *client.Client, query string, variables map[string]any, out any) []client.GraphQLError {
+	response, err := api.GraphQL(context.Background(), client.GraphQLRequest{Query: query, Variables: variables})
+	require.NoError(t, err)
+	if out != nil && response.Data != nil {
+		data, err := json.Marshal(response.Data)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, out))
+	}
+	return response.Errors
+}
+
+const createComputerMutation = `mutation($input: ComputerInput!) { createComputer(input: $input) { id } }`
+
+func TestGraphQLEmployeesWithComputersAndHistory(t *testing.T) {
+	api, _, _ := newV2Client(t)
+
+	for _, abbreviation := range []string{"JDE", "AJK"} {
+		errs := graphQL(t, api, `mutation($input: EmployeeInput!) { createEmployee(input: $input) { abbreviation } }`,
+			map[string]any{"input": map[string]any{
+				"firstName": "Test", "lastName": abbreviation, "email": abbreviation + "@example.com", "abbreviation": abbreviation,
+			}}, nil)
+		require.Empty(t, errs)
+	}
+	var created struct{ CreateComputer struct{ ID string } }
+	errs := graphQL(t, api, createComputerMutation, map[string]any{"input": map[string]any{
+		"macAddress": "12:34:56:78:90:a0", "name": "John's computer", "ipAddress": "192.168.1.103", "employeeAbbreviation": "JDE",
+	}}, &created)
+	require.Empty(t, errs)
+
+	var assigned struct {
+		AssignComputer struct{ Employee struct{ Abbreviation string } }
+	}
+	errs = graphQL(t, api, `mutation($id: ID!) { assignComputer(computerId: $id, employeeAbbreviation: "AJK") { employee { abbreviation } } }`,
+		map[string]any{"id": created.CreateComputer.ID}, &assigned)
+	require.Empty(t, errs)
+	assert.Equal(t, "AJK", assigned.AssignComputer.Employee.Abbreviation)
+
+	var result struct {
+		Employees []struct {
+			Abbreviation string
+			Computers    []struct{ Name string }
+			History      []struct {
+				Computer struct{ ID string }
+				Current  bool
+			}
+		}
+	}
+	errs = graphQL(t, api, `{ employees { abbreviation computers { name } history { computer { id } current } } }`, nil, &result)
+	require.Empty(t, errs)
+	require.Len(t, result.Employees, 2)
+
+	employees := map[string]int{}
+	for i, employee := range result.Employees {
+		employees[employee.Abbreviation] = i
+	}
+	john, anna := result.Employees[employees["JDE"]], result.Employees[employees["AJK"]]
+	assert.Empty(t, john.Computers)
+	require.Len(t, john.History, 1)
+	assert.Equal(t, created.CreateComputer.ID, john.History[0].Computer.ID)
+	assert.False(t, john.History[0].Current)
+	require.Len(t, anna.Computers, 1)
+	assert.Equal(t, "John's computer", anna.Computers[0].Name)
+	require.Len(t, anna.History, 1)
+	assert.True(t, anna.History[0].Current)
+
+	var deleted struct{ DeleteComputer bool }
+	errs = graphQL(t, api, `mutation($id: ID!) { deleteComputer(id: $id) }`, map[string]any{"id": created.CreateComputer.ID}, &deleted)
+	require.Empty(t, errs)
+	assert.True(t, deleted.DeleteComputer)
+
+	var missing struct{ Computer *struct{ ID string } }
+	errs = graphQL(t, api, `query($id: ID!) { computer(id: $id) { id } }`, map[string]any{"id": created.CreateComputer.ID}, &missing)
+	require.Empty(t, errs)
+	assert.Nil(t, missing.Computer)
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	api, server, _ := newV2Client(t)
+	ctx := context.Background()
+
+	// errors of fields carry the code of the problem REST would answer with
+	errs := graphQL(t, api, createComputerMutation, map[string]any{"input": map[string]any{
+		"macAddress": "12:34:56:78:90:a0", "name": "John's computer", "ipAddress": "192.168.1.103", "employeeAbbreviation": "NOPE",
+	}}, nil)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "employee_not_found", errs[0].Extensions["code"])
+	assert.EqualValues(t, 404, errs[0].Extensions["status"])
+
+	errs = graphQL(t, api, createComputerMutation, map[string]any{"input": map[string]any{
+		"macAddress": "", "name": "John's computer", "ipAddress": "192.168.1.103", "employeeAbbreviation": "JDE",
+	}}, nil)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "validation_failed", errs[0].Extensions["code"])
+	assert.Contains(t, errs[0].Extensions["errors"], "mac_address")
+
+	// mutations are only run when sent with POST
+	response, err := api.GraphQLQuery(ctx, `mutation { deleteComputer(id: "1") }`, "", "")
+	require.NoError(t, err)
+	require.Len(t, response.Errors, 1)
+	assert.Equal(t, "mutation_not_allowed", response.Errors[0].Extensions["code"])
+
+	response, err = api.GraphQLQuery(ctx, `{ employees { abbreviation } }`, "", "")
+	require.NoError(t, err)
+	assert.Empty(t, response.Errors)
+
+	_, err = client.New(server).GraphQL(ctx, client.GraphQLRequest{Query: `{ employees { abbreviation } }`})
+	var problem *client.Problem
+	require.True(t, errors.As(err, &problem), "%v", err)
+	assert.EqualValues(t, 401, problem.Status)
+
+	_, err = api.GraphQLQuery(ctx, "", "", "")
+	require.True(t, errors.As(err, &problem), "%v", err)
+	assert.Equal(t, "validation_failed", problem.Code)
+}
+
+// countingStore counts the lookups of the computers of employees.
+type countingStore struct {
+	repositories.Store
+	calls *int32
+}
+
+func (s countingStore) Computers() repositories.ComputerRepository {
+	return countingComputers{ComputerRepository: s.Store.Computers(), calls: s.calls}
+}
+
+type countingComputers struct {
+	repositories.ComputerRepository
+	calls *int32
+}
+
+func (r countingComputers) FindByEmployeeIDs(ctx context.Context, employeeIDs []uint) (map[uint][]db.Computer, error) {
+	atomic.AddInt32(r.calls, 1)
+	return r.ComputerRepository.FindByEmployeeIDs(ctx, employeeIDs)
+}
+
+func TestGraphQLBatchesLookups(t *testing.T) {
+	api, _, _ := newV2Client(t)
+	for i, abbreviation := range []string{"AAA", "BBB", "CCC", "DDD"} {
+		createEmployee(t, abbreviation)
+		_, err := services.CreateComputer(context.Background(), newComputer(i, abbreviation))
+		require.NoError(t, err)
+	}
+
+	var calls int32
+	store := services.Store
+	services.Store = countingStore{Store: store, calls: &calls}
+	t.Cleanup(func() { services.Store = store })
+
+	var result struct {
+		Employees []struct{ Computers []struct{ Name string } }
+	}
+	errs := graphQL(t, api, `{ employees { computers { name } } }`, nil, &result)
+	require.Empty(t, errs)
+	require.Len(t, result.Employees, 4)
+	for _, employee := range result.Employees {
+		assert.Len(t, employee.Computers, 1)
+	}
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls), "the computers of all employees are loaded at once")
+}
+
+func TestGraphQLLimitsTheObjectsResolved(t *testing.T) {
+	api, _, _ := newV2Client(t)
+	for i, abbreviation := range []string{"AAA", "BBB", "CCC"} {
+		createEmployee(t, abbreviation)
+		_, err := services.CreateComputer(context.Background(), newComputer(i, abbreviation))
+		require.NoError(t, err)
+	}
+	services.Config.GraphQLMaxObjects = 8
+
+	// 3 employees and their 3 computers
+	errs := graphQL(t, api, `{ employees { computers { name } } }`, nil, nil)
+	require.Empty(t, errs)
+
+	// each level of nesting resolves every computer again
+	errs = graphQL(t, api, `{ employees { computers { employee { computers { name } } } } }`, nil, nil)
+	require.NotEmpty(t, errs)
+	assert.Equal(t, "query_too_complex", errs[0].Extensions["code"])
+	assert.EqualValues(t, 400, errs[0].Extensions["status"])
+}
+
+func TestGraphQLReadOnlyClientsMayOnlyQuery(t *testing.T) {
+	api := newCertificateV2Client(t, "auditor", "read-only")
+	createEmployee(t, "JDE")
+
+	var result struct {
+		Employees []struct{ Abbreviation string }
+	}
+	errs := graphQL(t, api, `{ employees { abbreviation } }`, nil, &result)
+	require.Empty(t, errs)
+	require.Len(t, result.Employees, 1)
+
+	errs = graphQL(t, api, createComputerMutation, map[string]any{"input": map[string]any{
+		"macAddress": "12:34:56:78:90:a0", "name": "John's computer", "ipAddress": "192.168.1.103", "employeeAbbreviation": "JDE",
+	}}, nil)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "insufficient_role", errs[0].Extensions["code"])
+	assert.EqualValues(t, 403, errs[0].Extensions["status"])
+
+	computers, err := services.Store.Computers().FindAll(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, computers)
+}
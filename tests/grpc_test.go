@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"greenbone-task/certs"
+	inventoryv1 "greenbone-task/proto/inventory/v1"
+	"greenbone-task/rpc"
+	"greenbone-task/services"
+	"net"
+	"testing"
+)
+
+// newGRPCClient serves the gRPC API on an in-memory connection and returns a
+// client of it and a context authenticated with an access token.
+func newGRPCClient(t *testing.T) (inventoryv1.InventoryServiceClient, context.Context) {
+	setupSQLiteServices(t)
+	listener := bufconn.Listen(1 << 20)
+	server := rpc.NewServer()
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	access, _, err := services.GenerateAccessTokens(context.Background(), "helpdesk@example.com")
+	require.NoError(t, err)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), rpc.TokenMetadataKey, access.Token)
+	return inventoryv1.NewInventoryServiceClient(conn), ctx
+}
+
+// reason returns the code of the error in the ErrorInfo of a status.
+func reason(t *testing.T, err error) string {
+	st, ok := status.FromError(err)
+	require.True(t, ok, "%v", err)
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return ""
+}
+
+func TestGRPCInventory(t *testing.T) {
+	api, ctx := newGRPCClient(t)
+
+	for _, abbreviation := range []string{"JDE", "AJK"} {
+		created, err := api.CreateEmployee(ctx, &inventoryv1.CreateEmployeeRequest{
+			FirstName: "Test", LastName: abbreviation, Email: abbreviation + "@example.com", Abbreviation: abbreviation,
+		})
+		require.NoError(t, err)
+		assert.NotZero(t, created.Employee.Id)
+	}
+
+	created, err := api.CreateComputer(ctx, &inventoryv1.CreateComputerRequest{
+		MacAddress: "12:34:56:78:90:a0", Name: "John's computer", IpAddress: "192.168.1.103", EmployeeAbbreviation: "JDE",
+	})
+	require.NoError(t, err)
+	computer := created.Computer
+	assert.Equal(t, "JDE", computer.EmployeeAbbreviation)
+	assert.NotNil(t, computer.CreatedAt)
+
+	assigned, err := api.AssignComputer(ctx, &inventoryv1.AssignComputerRequest{ComputerId: computer.Id, EmployeeAbbreviation: "AJK"})
+	require.NoError(t, err)
+	assert.Equal(t, "AJK", assigned.Computer.EmployeeAbbreviation)
+
+	history, err := api.ListAssignments(ctx, &inventoryv1.ListAssignmentsRequest{ComputerId: computer.Id})
+	require.NoError(t, err)
+	require.Len(t, history.Assignments, 2)
+	assert.NotNil(t, history.Assignments[0].UnassignedAt)
+	assert.Nil(t, history.Assignments[1].UnassignedAt)
+
+	page, err := api.ListComputers(ctx, &inventoryv1.ListComputersRequest{})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, page.Total)
+	require.Len(t, page.Computers, 1)
+
+	_, err = api.DeleteComputer(ctx, &inventoryv1.DeleteComputerRequest{Id: computer.Id})
+	require.NoError(t, err)
+	_, err = api.GetComputer(ctx, &inventoryv1.GetComputerRequest{Id: computer.Id})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, "computer_not_found", reason(t, err))
+}
+
+func TestGRPCErrors(t *testing.T) {
+	api, ctx := newGRPCClient(t)
+
+	// calls without a valid token are refused
+	_, err := api.ListEmployees(context.Background(), &inventoryv1.ListEmployeesRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, "invalid_token", reason(t, err))
+
+	invalid := metadata.AppendToOutgoingContext(context.Background(), rpc.TokenMetadataKey, "not a token")
+	_, err = api.ListEmployees(invalid, &inventoryv1.ListEmployeesRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// validation errors name the fields
+	_, err = api.CreateComputer(ctx, &inventoryv1.CreateComputerRequest{Name: "John's computer", EmployeeAbbreviation: "JDE"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, "validation_failed", reason(t, err))
+	var fields []string
+	for _, detail := range status.Convert(err).Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.FieldViolations {
+				fields = append(fields, violation.Field)
+			}
+		}
+	}
+	assert.Equal(t, []string{"ip_address", "mac_address"}, fields)
+
+	_, err = api.AssignComputer(ctx, &inventoryv1.AssignComputerRequest{EmployeeAbbreviation: "JDE"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = api.CreateEmployee(ctx, &inventoryv1.CreateEmployeeRequest{FirstName: "John", LastName: "Doe", Email: "jde@example.com", Abbreviation: "JDE"})
+	require.NoError(t, err)
+	_, err = api.CreateEmployee(ctx, &inventoryv1.CreateEmployeeRequest{FirstName: "John", LastName: "Doe", Email: "jde@example.com", Abbreviation: "JDE"})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = api.ListComputers(ctx, &inventoryv1.ListComputersRequest{PageSize: 1000})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCMutualTLSRoles(t *testing.T) {
+	setupSQLiteServices(t)
+	services.Config.TLSClientRoles = "ops=admin, auditor=read-only"
+	ca := newTestCA(t, "Test CA")
+	reloader, err := certs.Load(ca.writeServerCertificate(t.TempDir(), certs.ClientAuthOptional))
+	require.NoError(t, err)
+
+	listener := bufconn.Listen(1 << 20)
+	server := rpc.NewServer(grpc.Creds(credentials.NewTLS(reloader.TLSConfig())))
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	dial := func(commonName string) inventoryv1.InventoryServiceClient {
+		pool := x509.NewCertPool()
+		pool.AddCert(ca.certificate)
+		config := &tls.Config{RootCAs: pool, ServerName: "127.0.0.1", Certificates: []tls.Certificate{ca.clientCertificate(commonName)}}
+		conn, err := grpc.Dial("bufnet",
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+			grpc.WithTransportCredentials(credentials.NewTLS(config)),
+		)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = conn.Close() })
+		return inventoryv1.NewInventoryServiceClient(conn)
+	}
+	ctx := context.Background()
+
+	_, err = dial("ops").CreateEmployee(ctx, &inventoryv1.CreateEmployeeRequest{FirstName: "John", LastName: "Doe", Email: "jde@example.com", Abbreviation: "JDE"})
+	require.NoError(t, err)
+
+	auditor := dial("auditor")
+	_, err = auditor.GetEmployee(ctx, &inventoryv1.GetEmployeeRequest{Abbreviation: "JDE"})
+	require.NoError(t, err)
+	_, err = auditor.DeleteComputer(ctx, &inventoryv1.DeleteComputerRequest{Id: 1})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Equal(t, services.CodeInsufficientRole, reason(t, err))
+
+	_, err = dial("stranger").ListEmployees(ctx, &inventoryv1.ListEmployeesRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, services.CodeUnknownClient, reason(t, err))
+}
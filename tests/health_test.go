@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"greenbone-task/routes"
+	"greenbone-task/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHealthAndReadiness(t *testing.T) {
+	setupSQLiteServices(t)
+	routes.InitGin()
+	client := &apiClient{t: t, router: routes.New()}
+	t.Cleanup(func() { services.SetReady(false) })
+
+	var health map[string]string
+	require.Equal(t, http.StatusOK, client.do(http.MethodGet, "/healthz", nil, &health))
+	assert.Equal(t, "ok", health["status"])
+
+	// not ready until startup has finished
+	services.SetReady(false)
+	var report services.ReadinessReport
+	require.Equal(t, http.StatusServiceUnavailable, client.do(http.MethodGet, "/readyz", nil, &report))
+	assert.Equal(t, services.StatusNotReady, report.Status)
+
+	services.SetReady(true)
+	report = services.ReadinessReport{}
+	require.Equal(t, http.StatusOK, client.do(http.MethodGet, "/readyz", nil, &report))
+	assert.Equal(t, services.StatusReady, report.Status)
+	require.Contains(t, report.Checks, "database")
+	assert.Equal(t, services.StatusUp, report.Checks["database"].Status)
+	assert.True(t, report.Checks["database"].Required)
+	assert.NotContains(t, report.Checks, "redis")
+
+	// a lost database makes the service unready
+	sqlDB, err := services.DbConnection.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Close())
+	report = services.ReadinessReport{}
+	require.Equal(t, http.StatusServiceUnavailable, client.do(http.MethodGet, "/readyz", nil, &report))
+	assert.Equal(t, services.StatusDown, report.Checks["database"].Status)
+	assert.NotEmpty(t, report.Checks["database"].Error)
+}
+
+func TestReadinessChecksNotificationTarget(t *testing.T) {
+	setupSQLiteServices(t)
+	services.Config.HealthCheckNotification = true
+	services.SetReady(true)
+	t.Cleanup(func() { services.SetReady(false) })
+	target := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(target.Close)
+
+	cases := []struct {
+		url    string
+		status string
+		dialed string
+	}{
+		{url: target.URL + "/api/notify", status: services.StatusUp},
+		// without a port, the default port of the scheme is dialed
+		{url: "http://127.0.0.1/api/notify", status: services.StatusDown, dialed: "127.0.0.1:80"},
+		{url: "https://127.0.0.1/api/notify", status: services.StatusDown, dialed: "127.0.0.1:443"},
+	}
+	for _, c := range cases {
+		services.Config.NotificationURL = c.url
+		report := services.CheckReadiness(context.Background())
+		require.Contains(t, report.Checks, "notification")
+		check := report.Checks["notification"]
+		assert.Equal(t, c.status, check.Status, c.url)
+		assert.False(t, check.Required)
+		assert.Contains(t, check.Error, c.dialed, c.url)
+		assert.Equal(t, services.StatusReady, report.Status, "the notification target is optional")
+	}
+}
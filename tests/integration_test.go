@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"greenbone-task/constants"
+	"greenbone-task/models"
+	"greenbone-task/routes"
+	"greenbone-task/services"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setupSQLiteServices connects the services to a fresh in-memory SQLite database.
+func setupSQLiteServices(t *testing.T) *fakeNotifier {
+	return setupDBServices(t, models.EnvConfig{
+		DBDriver:   constants.DriverSQLite,
+		SQLitePath: ":memory:",
+	})
+}
+
+// setupPostgresServices connects the services to the Postgres database named
+// by the TEST_POSTGRES_* variables, after emptying it. Unlike SQLite, Postgres
+// runs concurrent transactions on separate connections, so row locks are
+// exercised. The test is skipped unless TEST_POSTGRES_HOST is set.
+func setupPostgresServices(t *testing.T) *fakeNotifier {
+	host := os.Getenv("TEST_POSTGRES_HOST")
+	if host == "" {
+		t.Skip("TEST_POSTGRES_HOST is not set")
+	}
+	notifier := setupDBServices(t, models.EnvConfig{
+		DBDriver:       constants.DriverPostgres,
+		DBHost:         host,
+		DBPort:         envOrDefault("TEST_POSTGRES_PORT", "5432"),
+		DBUserName:     envOrDefault("TEST_POSTGRES_USER", "postgres"),
+		DBUserPassword: envOrDefault("TEST_POSTGRES_PASSWORD", "postgres"),
+		DBName:         envOrDefault("TEST_POSTGRES_DB", "postgres"),
+		DBMaxOpenConns: 20,
+		DBMaxIdleConns: 2,
+	})
+
+	var tables []string
+	require.NoError(t, services.DbConnection.Raw(
+		"SELECT tablename FROM pg_tables WHERE schemaname = current_schema() AND tablename <> 'schema_migrations'").Scan(&tables).Error)
+	if len(tables) > 0 {
+		require.NoError(t, services.DbConnection.Exec("TRUNCATE "+strings.Join(tables, ", ")+" RESTART IDENTITY CASCADE").Error)
+	}
+	return notifier
+}
+
+// setupDBServices connects the services to the database of config, migrated
+// to the latest version, with the settings the tests share.
+func setupDBServices(t *testing.T, config models.EnvConfig) *fakeNotifier {
+	notifier := &fakeNotifier{}
+
+	previousStore, previousNotifier, previousConfig := services.Store, services.Notifier, services.Config
+	config.DBAutoMigrate = true
+	config.DBQueryTimeout = 5 * time.Second
+	config.ComputerQuota = constants.DefaultComputerQuota
+	config.NotificationQueueSize = 100
+	config.JWTSecretKey = "test-secret"
+	config.JWTAccessExpirationMinutes = 10
+	config.JWTRefreshExpirationDays = 1
+	config.Mode = gin.ReleaseMode
+	services.Config = &config
+	require.NoError(t, services.Config.Validate())
+	services.ConnectDB()
+	require.NotNil(t, services.DbConnection)
+	services.Notifier = notifier
+
+	t.Cleanup(func() {
+		if sqlDB, err := services.DbConnection.DB(); err == nil {
+			sqlDB.Close()
+		}
+		services.Store, services.Notifier, services.Config = previousStore, previousNotifier, previousConfig
+	})
+
+	return notifier
+}
+
+func envOrDefault(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// apiClient performs authenticated requests against the router.
+type apiClient struct {
+	t      *testing.T
+	router *gin.Engine
+	token  string
+	// tls is the connection state of the requests, set for a client
+	// authenticated with a certificate
+	tls *tls.ConnectionState
+}
+
+func newAPIClient(t *testing.T) *apiClient {
+	routes.InitGin()
+	client := &apiClient{t: t, router: routes.New()}
+
+	var body struct {
+		Data struct {
+			Token struct {
+				Access struct {
+					Token string `json:"token"`
+				} `json:"access"`
+			} `json:"token"`
+		} `json:"data"`
+	}
+	status := client.do(http.MethodPost, "/v1/auth/generate_access_token", gin.H{"email": "admin@example.com"}, &body)
+	require.Equal(t, http.StatusOK, status)
+	require.NotEmpty(t, body.Data.Token.Access.Token)
+	client.token = body.Data.Token.Access.Token
+
+	return client
+}
+
+// asAdmin makes the client authenticate with a verified certificate of a
+// client with the admin role, as the TLS server would.
+func (c *apiClient) asAdmin() *apiClient {
+	services.Config.TLSClientRoles = "ops=admin"
+	certificate := &x509.Certificate{Subject: pkix.Name{CommonName: "ops"}}
+	c.tls = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{certificate}}}
+	return c
+}
+
+func (c *apiClient) do(method string, path string, payload any, out any) int {
+	recorder := c.send(method, path, payload)
+	if out != nil {
+		require.NoError(c.t, json.Unmarshal(recorder.Body.Bytes(), out), recorder.Body.String())
+	}
+	return recorder.Code
+}
+
+// send performs a request and returns the raw response.
+func (c *apiClient) send(method string, path string, payload any) *httptest.ResponseRecorder {
+	var reader bytes.Buffer
+	if payload != nil {
+		require.NoError(c.t, json.NewEncoder(&reader).Encode(payload))
+	}
+
+	req := httptest.NewRequest(method, path, &reader)
+	req.Header.Set("Content-Type", "application/json")
+	req.TLS = c.tls
+	if c.token != "" {
+		req.Header.Set("Bearer-Token", c.token)
+	}
+	recorder := httptest.NewRecorder()
+	c.router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestComputerLifecycleOnSQLite(t *testing.T) {
+	notifier := setupSQLiteServices(t)
+	client := newAPIClient(t)
+
+	for _, abbreviation := range []string{"JDE", "AJK"} {
+		status := client.do(http.MethodPost, "/v1/api/employees/", gin.H{
+			"first_name":   "Test",
+			"last_name":    abbreviation,
+			"email":        abbreviation + "@example.com",
+			"abbreviation": abbreviation,
+		}, nil)
+		require.Equal(t, http.StatusCreated, status)
+	}
+
+	var ids []uint
+	for i := 0; i < 3; i++ {
+		var created struct {
+			Data struct {
+				ComputerID uint `json:"Computer ID"`
+			} `json:"data"`
+		}
+		status := client.do(http.MethodPost, "/v1/computers", gin.H{
+			"mac_address":     fmt.Sprintf("12:34:56:78:90:a%d", i),
+			"computer_name":   "John's computer",
+			"ip_address":      "192.168.1.103",
+			"employee_abbrev": "JDE",
+		}, &created)
+		require.Equal(t, http.StatusCreated, status)
+		ids = append(ids, created.Data.ComputerID)
+	}
+	assert.Equal(t, 1, notifier.count())
+
+	// a duplicate MAC address is rejected
+	status := client.do(http.MethodPost, "/v1/computers", gin.H{
+		"mac_address":     "12:34:56:78:90:a0",
+		"computer_name":   "Duplicate",
+		"ip_address":      "192.168.1.104",
+		"employee_abbrev": "JDE",
+	}, nil)
+	assert.Equal(t, http.StatusConflict, status)
+
+	type computerList struct {
+		Data struct {
+			Data []struct {
+				ID uint `json:"ID"`
+			} `json:"Data"`
+		} `json:"data"`
+	}
+
+	var assigned computerList
+	status = client.do(http.MethodGet, "/v1/api/employees/computers/JDE", nil, &assigned)
+	require.Equal(t, http.StatusOK, status)
+	assert.Len(t, assigned.Data.Data, 3)
+
+	status = client.do(http.MethodPut, fmt.Sprintf("/v1/computers/%d/AJK", ids[0]), nil, nil)
+	require.Equal(t, http.StatusCreated, status)
+
+	var reassigned computerList
+	status = client.do(http.MethodGet, "/v1/api/employees/computers/AJK", nil, &reassigned)
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, reassigned.Data.Data, 1)
+	assert.Equal(t, ids[0], reassigned.Data.Data[0].ID)
+
+	status = client.do(http.MethodDelete, fmt.Sprintf("/v1/api/employees/computers/%d/JDE", ids[1]), nil, nil)
+	require.Equal(t, http.StatusOK, status)
+
+	var all computerList
+	status = client.do(http.MethodGet, "/v1/computers", nil, &all)
+	require.Equal(t, http.StatusOK, status)
+	assert.Len(t, all.Data.Data, 2)
+
+	status = client.do(http.MethodGet, fmt.Sprintf("/v1/computers/%d", ids[1]), nil, nil)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestMigrationsUpAndDownOnSQLite(t *testing.T) {
+	setupSQLiteServices(t)
+
+	migrator, err := services.NewMigrator()
+	require.NoError(t, err)
+
+	statuses, err := migrator.Status(context.Background())
+	require.NoError(t, err)
+	for _, status := range statuses {
+		assert.True(t, status.Applied, "migration %d should be applied", status.Version)
+	}
+
+	reverted, err := migrator.Down(context.Background(), len(statuses))
+	require.NoError(t, err)
+	assert.Len(t, reverted, len(statuses))
+	assert.False(t, services.DbConnection.Migrator().HasTable("computers"))
+
+	applied, err := migrator.Up(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, applied, len(statuses))
+	assert.True(t, services.DbConnection.Migrator().HasTable("computers"))
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"greenbone-task/lifecycle"
+	"greenbone-task/services"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestLifecycleStopsHooksInOrder(t *testing.T) {
+	app := lifecycle.New(time.Second)
+
+	var mu sync.Mutex
+	var stopped []string
+	for _, name := range []string{"http server", "workers", "database", "redis"} {
+		name := name
+		app.OnStop(name, func(context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			stopped = append(stopped, name)
+			return nil
+		})
+	}
+
+	app.Shutdown()
+	assert.Equal(t, lifecycle.ExitOK, app.Wait())
+	assert.Equal(t, []string{"http server", "workers", "database", "redis"}, stopped)
+}
+
+func TestLifecycleExitCodes(t *testing.T) {
+	t.Run("component failure", func(t *testing.T) {
+		app := lifecycle.New(time.Second)
+		app.Go("server", func() error { return errors.New("address already in use") })
+		assert.Equal(t, lifecycle.ExitComponentFailed, app.Wait())
+	})
+
+	t.Run("failing stop hook", func(t *testing.T) {
+		app := lifecycle.New(time.Second)
+		app.OnStop("database", func(context.Context) error { return errors.New("close failed") })
+		app.Shutdown()
+		assert.Equal(t, lifecycle.ExitShutdownFailed, app.Wait())
+	})
+
+	t.Run("shutdown timeout", func(t *testing.T) {
+		app := lifecycle.New(50 * time.Millisecond)
+		app.OnStop("stuck", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		app.Shutdown()
+		assert.Equal(t, lifecycle.ExitShutdownFailed, app.Wait())
+	})
+}
+
+func TestLifecycleStopsBackgroundJobs(t *testing.T) {
+	app := lifecycle.New(time.Second)
+
+	var runs atomic.Int32
+	app.Every("job", 5*time.Millisecond, func(ctx context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+	require.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, 5*time.Millisecond)
+
+	app.Shutdown()
+	assert.Equal(t, lifecycle.ExitOK, app.Wait())
+
+	stoppedAt := runs.Load()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, stoppedAt, runs.Load())
+}
+
+func TestLifecycleReloadsOnSIGHUP(t *testing.T) {
+	app := lifecycle.New(time.Second)
+
+	var reloads atomic.Int32
+	app.OnReload("config", func(context.Context) error {
+		reloads.Add(1)
+		return nil
+	})
+	app.OnReload("failing", func(context.Context) error { return errors.New("invalid config") })
+
+	// keep SIGHUP from killing the test binary before Wait subscribes to it
+	subscribed := make(chan os.Signal, 10)
+	signal.Notify(subscribed, syscall.SIGHUP)
+	defer signal.Stop(subscribed)
+
+	code := make(chan int, 1)
+	go func() { code <- app.Wait() }()
+
+	// the process keeps running after a reload, even if a hook failed
+	require.Eventually(t, func() bool {
+		require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+		return reloads.Load() >= 1
+	}, time.Second, 20*time.Millisecond)
+	select {
+	case <-code:
+		t.Fatal("SIGHUP stopped the manager")
+	default:
+	}
+
+	app.Shutdown()
+	assert.Equal(t, lifecycle.ExitOK, <-code)
+}
+
+func TestNotificationDispatcherDrainsOnStop(t *testing.T) {
+	notifier := &fakeNotifier{}
+	dispatcher := services.NewNotificationDispatcher(notifier, 10)
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, dispatcher.NotifySystemAdministrator(context.Background(), "JDE", "message"))
+	}
+	require.NoError(t, dispatcher.Stop(context.Background()))
+	assert.Equal(t, 5, notifier.count())
+
+	assert.Error(t, dispatcher.NotifySystemAdministrator(context.Background(), "JDE", "too late"))
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"greenbone-task/logger"
+	"greenbone-task/middlewares"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRequestIDIsPropagatedToLogs(t *testing.T) {
+	core, observed := observer.New(zap.InfoLevel)
+	var accessLog bytes.Buffer
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(logger.WithContext(c.Request.Context(), zap.New(core)))
+	})
+	router.Use(middlewares.RequestIDMiddleware(), middlewares.AccessLogger(&accessLog))
+	router.GET("/computers/:computer_id", func(c *gin.Context) {
+		c.Set("userId", int64(42))
+		logger.FromContext(c.Request.Context()).Info("handled")
+		c.Status(http.StatusNoContent)
+	})
+
+	serve := func(requestID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/computers/7", nil)
+		if requestID != "" {
+			req.Header.Set(middlewares.RequestIDHeader, requestID)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	// the caller's ID is kept and ends up in the service log and the access log
+	recorder := serve("client-id-1")
+	assert.Equal(t, "client-id-1", recorder.Header().Get(middlewares.RequestIDHeader))
+
+	entries := observed.FilterMessage("handled").All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "client-id-1", entries[0].ContextMap()["request_id"])
+
+	var access map[string]any
+	require.NoError(t, json.Unmarshal(accessLog.Bytes(), &access))
+	assert.Equal(t, "client-id-1", access["request_id"])
+	assert.Equal(t, "GET", access["method"])
+	assert.Equal(t, "/computers/:computer_id", access["route"])
+	assert.EqualValues(t, http.StatusNoContent, access["status"])
+	assert.EqualValues(t, 42, access["user_id"])
+	assert.Contains(t, access, "latency")
+
+	// a missing or unsafe ID is replaced by a generated one
+	for _, requestID := range []string{"", "bad id\nwith newline"} {
+		generated := serve(requestID).Header().Get(middlewares.RequestIDHeader)
+		assert.Len(t, generated, 36)
+		assert.NotEqual(t, requestID, generated)
+	}
+}
+
+func TestLogLevelCanBeChangedAtRuntime(t *testing.T) {
+	setupSQLiteServices(t)
+	client := newAPIClient(t).asAdmin()
+	previous := logger.Level()
+	t.Cleanup(func() { require.NoError(t, logger.SetLevel(previous)) })
+
+	var response struct {
+		Data struct {
+			Level string `json:"level"`
+		} `json:"data"`
+	}
+	require.Equal(t, http.StatusOK, client.do(http.MethodPut, "/v1/admin/log-level", gin.H{"level": "debug"}, &response))
+	assert.Equal(t, "debug", response.Data.Level)
+	assert.True(t, logger.FromContext(context.Background()).Core().Enabled(zap.DebugLevel))
+
+	require.Equal(t, http.StatusOK, client.do(http.MethodGet, "/v1/admin/log-level", nil, &response))
+	assert.Equal(t, "debug", response.Data.Level)
+
+	assert.Equal(t, http.StatusBadRequest, client.do(http.MethodPut, "/v1/admin/log-level", gin.H{"level": "verbose"}, nil))
+	assert.Equal(t, "debug", logger.Level())
+
+	// a token carries no role
+	client.tls = nil
+	assert.Equal(t, http.StatusForbidden, client.do(http.MethodPut, "/v1/admin/log-level", gin.H{"level": "error"}, nil))
+	assert.Equal(t, http.StatusForbidden, client.do(http.MethodGet, "/v1/admin/log-level", nil, nil))
+	assert.Equal(t, "debug", logger.Level())
+
+	client.token = ""
+	assert.Equal(t, http.StatusUnauthorized, client.do(http.MethodPut, "/v1/admin/log-level", gin.H{"level": "error"}, nil))
+}
+
+func TestRotatingFileKeepsBackups(t *testing.T) {
+	dir := t.TempDir()
+	file := logger.RotatingFile(filepath.Join(dir, "access.log"))
+
+	_, err := file.Write([]byte("first\n"))
+	require.NoError(t, err)
+	require.NoError(t, logger.Rotate())
+	_, err = file.Write([]byte("second\n"))
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "current file and one backup")
+
+	current, err := os.ReadFile(filepath.Join(dir, "access.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "second\n", string(current))
+}
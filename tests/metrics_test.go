@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"greenbone-task/metrics"
+	"greenbone-task/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func scrapeMetrics(t *testing.T, client *apiClient) string {
+	recorder := httptest.NewRecorder()
+	client.router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	return recorder.Body.String()
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	setupSQLiteServices(t)
+	client := newAPIClient(t)
+
+	for _, abbreviation := range []string{"JDE", "AJK"} {
+		status := client.do(http.MethodPost, "/v1/api/employees/", gin.H{
+			"first_name":   "Test",
+			"last_name":    abbreviation,
+			"email":        abbreviation + "@example.com",
+			"abbreviation": abbreviation,
+		}, nil)
+		require.Equal(t, http.StatusCreated, status)
+	}
+	for i, abbreviation := range []string{"JDE", "JDE", "JDE", "AJK"} {
+		status := client.do(http.MethodPost, "/v1/computers", gin.H{
+			"mac_address":     fmt.Sprintf("12:34:56:78:90:b%d", i),
+			"computer_name":   "Computer",
+			"ip_address":      "192.168.1.103",
+			"employee_abbrev": abbreviation,
+		}, nil)
+		require.Equal(t, http.StatusCreated, status)
+	}
+	require.Equal(t, http.StatusOK, client.do(http.MethodGet, "/v1/computers/1", nil, nil))
+
+	body := scrapeMetrics(t, client)
+
+	// requests are labelled with the route template, not the path
+	assert.Contains(t, body, `greenbone_http_request_duration_seconds_count{method="GET",route="/v1/computers/:computer_id",status="200"}`)
+	assert.NotContains(t, body, `route="/v1/computers/1"`)
+	assert.Contains(t, body, `greenbone_db_query_duration_seconds_count{operation="create",table="computers"}`)
+
+	// the employees are counted in buckets, not labelled
+	assert.Contains(t, body, `greenbone_computers_per_employee_bucket{le="1"} 1`)
+	assert.Contains(t, body, `greenbone_computers_per_employee_bucket{le="2"} 1`)
+	assert.Contains(t, body, `greenbone_computers_per_employee_bucket{le="3"} 2`)
+	assert.Contains(t, body, `greenbone_computers_per_employee_bucket{le="+Inf"} 2`)
+	assert.Contains(t, body, "greenbone_computers_per_employee_sum 4")
+	assert.NotContains(t, body, `employee="JDE"`)
+	assert.Contains(t, body, "greenbone_employees_over_quota 1")
+}
+
+func TestNotificationMetrics(t *testing.T) {
+	fail := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+
+	succeeded := testutil.ToFloat64(metrics.Notifications.WithLabelValues("success"))
+	failed := testutil.ToFloat64(metrics.Notifications.WithLabelValues("failure"))
+
+	ns := services.NewNotificationServiceWithURL(ts.URL)
+	require.NoError(t, ns.NotifySystemAdministrator(context.Background(), "JDE", "test"))
+	fail = true
+	require.Error(t, ns.NotifySystemAdministrator(context.Background(), "JDE", "test"))
+
+	assert.Equal(t, succeeded+1, testutil.ToFloat64(metrics.Notifications.WithLabelValues("success")))
+	assert.Equal(t, failed+1, testutil.ToFloat64(metrics.Notifications.WithLabelValues("failure")))
+}
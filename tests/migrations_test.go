@@ -0,0 +1,32 @@
+package main
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"greenbone-task/migrations"
+	"testing"
+)
+
+func TestEmbeddedMigrations(t *testing.T) {
+	migrator, err := migrations.New(nil, "postgres")
+	require.NoError(t, err)
+
+	all := migrator.Migrations()
+	require.NotEmpty(t, all)
+	assert.Equal(t, int64(1), all[0].Version)
+	assert.Equal(t, "initial_schema", all[0].Name)
+	assert.Contains(t, all[0].Up, "idx_computers_employee_abbrev")
+
+	for i, migration := range all {
+		assert.NotEmpty(t, migration.Up)
+		assert.NotEmpty(t, migration.Down)
+		if i > 0 {
+			assert.Greater(t, migration.Version, all[i-1].Version)
+		}
+	}
+}
+
+func TestUnknownMigrationDialect(t *testing.T) {
+	_, err := migrations.New(nil, "oracle")
+	assert.Error(t, err)
+}
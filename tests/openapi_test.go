@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"greenbone-task/client"
+	"greenbone-task/openapi"
+	"greenbone-task/routes"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"testing"
+)
+
+func TestOpenAPIMatchesRoutes(t *testing.T) {
+	doc, err := openapi.Load(openapi.Spec)
+	require.NoError(t, err)
+	documented, err := doc.Routes()
+	require.NoError(t, err)
+
+	var specified []string
+	for _, route := range documented {
+		specified = append(specified, route.Method+" "+openapi.GinPath(route.Path))
+	}
+	var registered []string
+	for _, route := range routes.New().Routes() {
+		// the Swagger UI is not part of the API
+		if route.Path == "/swagger/*any" {
+			continue
+		}
+		registered = append(registered, route.Method+" "+route.Path)
+	}
+	sort.Strings(specified)
+	sort.Strings(registered)
+
+	assert.Equal(t, registered, specified, "openapi/openapi.yaml is out of sync with the routes")
+}
+
+func TestGeneratedClientIsUpToDate(t *testing.T) {
+	doc, err := openapi.Load(openapi.Spec)
+	require.NoError(t, err)
+	generated, err := openapi.GenerateClient(doc, "client")
+	require.NoError(t, err)
+
+	committed, err := os.ReadFile("../client/client.gen.go")
+	require.NoError(t, err)
+	assert.Equal(t, string(generated), string(committed), "run go generate ./openapi")
+}
+
+func TestGeneratedClient(t *testing.T) {
+	setupSQLiteServices(t)
+	routes.InitGin()
+	server := httptest.NewServer(routes.New())
+	t.Cleanup(server.Close)
+	ctx := context.Background()
+
+	spec, err := client.New(server.URL).GetOpenAPISpec(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, string(openapi.Spec), spec)
+
+	tokens, err := client.New(server.URL).GenerateAccessToken(ctx, client.AuthRequest{Email: "helpdesk@example.com"})
+	require.NoError(t, err)
+	api := client.New(server.URL, client.WithToken(tokens.Data.Token.Access.Token))
+
+	_, err = api.CreateEmployee(ctx, client.EmployeeRequest{FirstName: "John", LastName: "Doe", Email: "jde@example.com", Abbreviation: "JDE"})
+	require.NoError(t, err)
+	created, err := api.CreateComputer(ctx, client.ComputerRequest{
+		MacAddress: "12:34:56:78:90:a0", ComputerName: "John's computer", IPAddress: "192.168.1.103", EmployeeAbbrev: "JDE",
+	})
+	require.NoError(t, err)
+
+	computer, err := api.GetComputer(ctx, created.Data.ComputerID)
+	require.NoError(t, err)
+	assert.Equal(t, "John's computer", computer.Data.Data.ComputerName)
+	assert.Equal(t, "JDE", computer.Data.Data.EmployeeAbbrev)
+
+	computers, err := api.ListEmployeeComputers(ctx, "JDE")
+	require.NoError(t, err)
+	require.Len(t, computers.Data.Data, 1)
+	assert.Equal(t, created.Data.ComputerID, computers.Data.Data[0].ID)
+
+	// errors of the API are returned as problems
+	_, err = api.AssignComputer(ctx, created.Data.ComputerID, "NOPE")
+	var problem *client.Problem
+	require.True(t, errors.As(err, &problem), "%v", err)
+	assert.Equal(t, "employee_not_found", problem.Code)
+
+	_, err = client.New(server.URL).ListComputers(ctx)
+	require.True(t, errors.As(err, &problem), "%v", err)
+	assert.EqualValues(t, 401, problem.Status)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"greenbone-task/constants"
+	"greenbone-task/services"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentCreatesRespectQuota(t *testing.T) {
+	const parallelCreates = 12
+
+	for name, setup := range storeBackends {
+		t.Run(name, func(t *testing.T) {
+			notifier := setup(t)
+			ctx := context.Background()
+			createEmployee(t, "JDE")
+
+			var wg sync.WaitGroup
+			errs := make(chan error, parallelCreates)
+			start := make(chan struct{})
+			for i := 0; i < parallelCreates; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					<-start
+					_, err := services.CreateComputer(ctx, newComputer(i, "JDE"))
+					errs <- err
+				}(i)
+			}
+			close(start)
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				require.NoError(t, err)
+			}
+
+			count, err := services.CountComputersByEmployeeAbbreviation(ctx, "JDE")
+			require.NoError(t, err)
+			assert.Equal(t, int64(parallelCreates), count)
+
+			// every create from the quota on notifies exactly once, each with its own count
+			var expected []string
+			for n := constants.DefaultComputerQuota; n <= parallelCreates; n++ {
+				expected = append(expected, fmt.Sprintf("JDE: Employee JDE already has %d computers assigned.", n))
+			}
+			notifier.mu.Lock()
+			messages := append([]string(nil), notifier.messages...)
+			notifier.mu.Unlock()
+			sort.Slice(messages, func(i, j int) bool {
+				return len(messages[i]) < len(messages[j]) || len(messages[i]) == len(messages[j]) && messages[i] < messages[j]
+			})
+			assert.Equal(t, expected, messages)
+		})
+	}
+}
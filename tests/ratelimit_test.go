@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"greenbone-task/middlewares"
+	"greenbone-task/models"
+	"greenbone-task/ratelimit"
+	"greenbone-task/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	limit, err := ratelimit.ParseLimit("10/m")
+	require.NoError(t, err)
+	assert.Equal(t, 10, limit.Burst)
+	assert.InDelta(t, 10.0/60, limit.Rate, 1e-9)
+
+	limit, err = ratelimit.ParseLimit("5/30s")
+	require.NoError(t, err)
+	assert.InDelta(t, 5.0/30, limit.Rate, 1e-9)
+
+	for _, disabled := range []string{"", "0/m"} {
+		limit, err := ratelimit.ParseLimit(disabled)
+		require.NoError(t, err)
+		assert.True(t, limit.Disabled())
+	}
+	for _, invalid := range []string{"10", "ten/m", "10/fortnight", "-1/m", "10/0s"} {
+		_, err := ratelimit.ParseLimit(invalid)
+		assert.Error(t, err, invalid)
+	}
+
+	routes, err := ratelimit.ParseRoutes("/v1/auth/refresh=30/m, /v1/computers=100/h")
+	require.NoError(t, err)
+	assert.Len(t, routes, 2)
+	_, err = ratelimit.ParseRoutes("v1/auth/refresh")
+	assert.Error(t, err)
+}
+
+func TestMemoryLimiterRefillsTokens(t *testing.T) {
+	now := time.Unix(0, 0)
+	limiter := ratelimit.NewMemoryLimiterWithClock(func() time.Time { return now })
+	limit := ratelimit.Limit{Rate: 1, Burst: 2}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		result, err := limiter.Allow(ctx, "ip:1", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+	}
+	result, err := limiter.Allow(ctx, "ip:1", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+
+	// other keys have their own bucket
+	result, _ = limiter.Allow(ctx, "ip:2", limit)
+	assert.True(t, result.Allowed)
+
+	now = now.Add(time.Second)
+	result, _ = limiter.Allow(ctx, "ip:1", limit)
+	assert.True(t, result.Allowed)
+}
+
+func TestRedisLimiterSharesBuckets(t *testing.T) {
+	server := miniredis.RunT(t)
+	limit := ratelimit.Limit{Rate: 0.1, Burst: 3}
+	ctx := context.Background()
+
+	// two instances of the service
+	first := ratelimit.NewRedisLimiter(redis.NewClient(&redis.Options{Addr: server.Addr()}))
+	second := ratelimit.NewRedisLimiter(redis.NewClient(&redis.Options{Addr: server.Addr()}))
+
+	for _, limiter := range []*ratelimit.RedisLimiter{first, second, first} {
+		result, err := limiter.Allow(ctx, "user:1", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+	}
+	result, err := second.Allow(ctx, "user:1", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+	assert.InDelta(t, 10*time.Second, result.RetryAfter, float64(time.Second))
+}
+
+func TestFallbackLimiterWhenRedisIsDown(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr(), MaxRetries: -1})
+	server.Close()
+
+	var failures int
+	limiter := &ratelimit.FallbackLimiter{
+		Primary:    ratelimit.NewRedisLimiter(client),
+		Fallback:   ratelimit.NewMemoryLimiter(),
+		OnFallback: func(context.Context, error) { failures++ },
+	}
+	limit := ratelimit.Limit{Rate: 0.1, Burst: 1}
+
+	result, err := limiter.Allow(context.Background(), "ip:1", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	result, err = limiter.Allow(context.Background(), "ip:1", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 2, failures)
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	setupSQLiteServices(t)
+	services.Config.RateLimitEnabled = true
+	services.Config.RateLimitIP = "100/m"
+	services.Config.RateLimitUser = "2/m"
+	services.Config.RateLimitAPIKey = "1/m"
+	services.Config.RateLimitRoutes = "/v1/auth/generate_access_token=2/m"
+	require.NoError(t, services.ConfigureRateLimiting())
+	t.Cleanup(func() {
+		services.Config.RateLimitEnabled = false
+		require.NoError(t, services.ConfigureRateLimiting())
+	})
+
+	client := newAPIClient(t) // takes the first token of the auth route
+
+	t.Run("per route", func(t *testing.T) {
+		payload := gin.H{"email": "admin@example.com"}
+		assert.Equal(t, http.StatusOK, client.send(http.MethodPost, "/v1/auth/generate_access_token", payload).Code)
+
+		recorder := client.send(http.MethodPost, "/v1/auth/generate_access_token", payload)
+		require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+		assert.Equal(t, "30", recorder.Header().Get("Retry-After"))
+		assert.Equal(t, models.ProblemContentType, recorder.Header().Get("Content-Type"))
+		assert.Contains(t, recorder.Body.String(), services.CodeRateLimitExceeded)
+
+		// other clients are not affected
+		req := httptest.NewRequest(http.MethodPost, "/v1/auth/generate_access_token", nil)
+		req.RemoteAddr = "198.51.100.7:1234"
+		other := httptest.NewRecorder()
+		client.router.ServeHTTP(other, req)
+		assert.NotEqual(t, http.StatusTooManyRequests, other.Code)
+	})
+
+	t.Run("per user", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			assert.Equal(t, http.StatusOK, client.do(http.MethodGet, "/v1/computers", nil, nil))
+		}
+		assert.Equal(t, http.StatusTooManyRequests, client.do(http.MethodGet, "/v1/computers", nil, nil))
+
+		// a new token of the same user does not reset the limit
+		access, _, err := services.GenerateAccessTokens(context.Background(), "admin@example.com")
+		require.NoError(t, err)
+		client.token = access.Token
+		assert.Equal(t, http.StatusTooManyRequests, client.do(http.MethodGet, "/v1/computers", nil, nil))
+	})
+
+	t.Run("probes are not limited", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, http.StatusOK, client.send(http.MethodGet, "/healthz", nil).Code)
+		}
+	})
+
+	t.Run("per API key", func(t *testing.T) {
+		serve := func(apiKey string, remoteAddr string) int {
+			req := httptest.NewRequest(http.MethodGet, "/v1/unknown", nil)
+			req.Header.Set(middlewares.APIKeyHeader, apiKey)
+			req.RemoteAddr = remoteAddr
+			recorder := httptest.NewRecorder()
+			client.router.ServeHTTP(recorder, req)
+			return recorder.Code
+		}
+		assert.Equal(t, http.StatusNotFound, serve("key-1", "198.51.100.8:1234"))
+		// the key is limited across client IPs
+		assert.Equal(t, http.StatusTooManyRequests, serve("key-1", "198.51.100.9:1234"))
+		assert.Equal(t, http.StatusNotFound, serve("key-2", "198.51.100.9:1234"))
+	})
+}
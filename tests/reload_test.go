@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"greenbone-task/logger"
+	"greenbone-task/routes"
+	"greenbone-task/services"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+const reloadableConfig = `
+db_driver: sqlite
+sqlite_path: ":memory:"
+jwt_secret: reload-secret
+jwt_access_expiration_minutes: 15
+jwt_refresh_expiration_days: 1
+rate_limit_enabled: false
+`
+
+// loadConfigFile loads the configuration from a YAML file with content and
+// returns the path of the file.
+func loadConfigFile(t *testing.T, content string) string {
+	level := logger.Level()
+	t.Cleanup(func() { _ = logger.SetLevel(level) })
+
+	path := writeConfigFile(t, "config.yaml", content)
+	_, err := loadConfig(t, "--config", path)
+	require.NoError(t, err)
+	return path
+}
+
+func TestReloadAppliesReloadableSettings(t *testing.T) {
+	path := loadConfigFile(t, reloadableConfig+"computer_quota: 3\nlog_level: info\nserver_port: \"8000\"\n")
+	routes.InitGin()
+	router := routes.New()
+
+	preflight := func() int {
+		req := httptest.NewRequest(http.MethodOptions, "/v1/computers", nil)
+		req.Header.Set("Origin", "https://inventory.example.com")
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder.Code
+	}
+	assert.Equal(t, http.StatusForbidden, preflight())
+
+	require.NoError(t, os.WriteFile(path, []byte(reloadableConfig+`
+computer_quota: 5
+log_level: debug
+cors_allowed_origins: https://inventory.example.com
+server_port: "9000"
+`), 0600))
+	require.NoError(t, services.ReloadConfig(context.Background()))
+
+	live := services.LiveConfig()
+	assert.Equal(t, 5, live.ComputerQuota)
+	assert.Equal(t, "debug", logger.Level())
+	assert.Equal(t, http.StatusNoContent, preflight())
+
+	// structural settings wait for a restart
+	assert.Equal(t, "8000", live.ServerPort)
+	assert.Equal(t, "8000", services.Config.ServerPort)
+	assert.Equal(t, 3, services.Config.ComputerQuota)
+}
+
+func TestReloadKeepsConfigurationWhenInvalid(t *testing.T) {
+	path := loadConfigFile(t, reloadableConfig+"computer_quota: 4\n")
+
+	require.NoError(t, os.WriteFile(path, []byte(reloadableConfig+"computer_quota: 6\nlog_level: loud\n"), 0600))
+	assert.Error(t, services.ReloadConfig(context.Background()))
+	assert.Equal(t, 4, services.LiveConfig().ComputerQuota)
+}
+
+func TestConfigFileChangesTriggerReload(t *testing.T) {
+	path := loadConfigFile(t, reloadableConfig)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloads := make(chan struct{}, 1)
+	go services.WatchConfig(ctx, func() { reloads <- struct{}{} })
+	time.Sleep(50 * time.Millisecond) // let the watcher subscribe
+
+	require.NoError(t, os.WriteFile(path, []byte(reloadableConfig+"computer_quota: 7\n"), 0600))
+	select {
+	case <-reloads:
+	case <-time.After(5 * time.Second):
+		t.Fatal("changing the configuration file did not trigger a reload")
+	}
+}
+
+func TestAdminConfigRedactsSecrets(t *testing.T) {
+	setupSQLiteServices(t)
+	client := newAPIClient(t)
+	require.Equal(t, http.StatusForbidden, client.do(http.MethodGet, "/v1/admin/config", nil, nil), "a token carries no role")
+	client.asAdmin()
+
+	var body struct {
+		Data struct {
+			Config     map[string]interface{} `json:"config"`
+			Reloadable []string               `json:"reloadable"`
+		} `json:"data"`
+	}
+	status := client.do(http.MethodGet, "/v1/admin/config", nil, &body)
+	require.Equal(t, http.StatusOK, status)
+
+	assert.Equal(t, "[REDACTED]", body.Data.Config["JWT_SECRET"])
+	assert.Equal(t, "", body.Data.Config["POSTGRES_PASSWORD"])
+	assert.Equal(t, "sqlite", body.Data.Config["DB_DRIVER"])
+	assert.Equal(t, "5s", body.Data.Config["DB_QUERY_TIMEOUT"])
+	assert.Contains(t, body.Data.Reloadable, "COMPUTER_QUOTA")
+	assert.NotContains(t, body.Data.Reloadable, "SERVER_PORT")
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"greenbone-task/repositories"
+	"greenbone-task/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNotificationGivesUpAfterTimeout(t *testing.T) {
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// a hung notification server
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	ns := services.NewNotificationServiceWithTimeout(ts.URL, 50*time.Millisecond)
+	started := time.Now()
+	err := ns.NotifySystemAdministrator(context.Background(), "JDE", "message")
+	assert.Error(t, err)
+	assert.Less(t, time.Since(started), time.Second)
+}
+
+func TestCancelledContextStopsQueries(t *testing.T) {
+	setupSQLiteServices(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel() // the client went away
+
+	_, err := services.GetAllComputers(ctx)
+	assert.True(t, errors.Is(err, context.Canceled), "unexpected error: %v", err)
+}
+
+func TestTimeoutPluginBoundsStatements(t *testing.T) {
+	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, database.Use(repositories.TimeoutPlugin{Timeout: time.Minute}))
+
+	var deadline time.Time
+	var bounded bool
+	require.NoError(t, database.Callback().Raw().Before("gorm:raw").After("timeout:before_raw").Register("test:deadline", func(db *gorm.DB) {
+		deadline, bounded = db.Statement.Context.Deadline()
+	}))
+
+	require.NoError(t, database.Exec("SELECT 1").Error)
+	require.True(t, bounded)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, 5*time.Second)
+}
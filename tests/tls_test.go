@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"greenbone-task/certs"
+	"greenbone-task/models"
+	"greenbone-task/routes"
+	"greenbone-task/services"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCA is a local certificate authority issuing server and client
+// certificates for the TLS tests.
+type testCA struct {
+	t           *testing.T
+	certificate *x509.Certificate
+	key         *ecdsa.PrivateKey
+	pem         []byte
+	serial      int64
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	certificate, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{t: t, certificate: certificate, key: key, serial: 1,
+		pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns the PEM encoded certificate and key for commonName.
+func (ca *testCA) issue(commonName string, usage x509.ExtKeyUsage) (certPEM []byte, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(ca.t, err)
+	ca.serial++
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(ca.serial),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.certificate, &key.PublicKey, ca.key)
+	require.NoError(ca.t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(ca.t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// clientCertificate returns a client certificate for commonName.
+func (ca *testCA) clientCertificate(commonName string) tls.Certificate {
+	certPEM, keyPEM := ca.issue(commonName, x509.ExtKeyUsageClientAuth)
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(ca.t, err)
+	return certificate
+}
+
+// writeServerCertificate writes a server certificate for 127.0.0.1 and the
+// CA to dir and returns the certificate options.
+func (ca *testCA) writeServerCertificate(dir string, clientAuth string) certs.Options {
+	certPEM, keyPEM := ca.issue("localhost", x509.ExtKeyUsageServerAuth)
+	opts := certs.Options{
+		CertFile:     filepath.Join(dir, "tls.crt"),
+		KeyFile:      filepath.Join(dir, "tls.key"),
+		ClientCAFile: filepath.Join(dir, "ca.crt"),
+		ClientAuth:   clientAuth,
+	}
+	require.NoError(ca.t, os.WriteFile(opts.KeyFile, keyPEM, 0600))
+	require.NoError(ca.t, os.WriteFile(opts.CertFile, certPEM, 0600))
+	require.NoError(ca.t, os.WriteFile(opts.ClientCAFile, ca.pem, 0600))
+	return opts
+}
+
+// serveTLS serves handler over TLS with the reloader like the main server and
+// returns its base URL.
+func serveTLS(t *testing.T, reloader *certs.Reloader, handler http.Handler) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &http.Server{Handler: handler}
+	go server.Serve(tls.NewListener(listener, reloader.TLSConfig()))
+	t.Cleanup(func() { server.Close() })
+	return "https://" + listener.Addr().String()
+}
+
+func tlsClient(ca *testCA, certificates ...tls.Certificate) *http.Client {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.certificate)
+	return &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{RootCAs: pool, Certificates: certificates},
+		DisableKeepAlives: true,
+	}}
+}
+
+func TestCertificatesAreReloadedOnChange(t *testing.T) {
+	ca := newTestCA(t, "Test CA")
+	dir := t.TempDir()
+	opts := ca.writeServerCertificate(dir, certs.ClientAuthNone)
+
+	reloader, err := certs.Load(opts)
+	require.NoError(t, err)
+	url := serveTLS(t, reloader, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	client := tlsClient(ca)
+
+	servedSerial := func() int64 {
+		resp, err := client.Get(url)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.TLS.PeerCertificates[0].SerialNumber.Int64()
+	}
+	first := servedSerial()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go reloader.Watch(ctx)
+	time.Sleep(50 * time.Millisecond) // let the watcher subscribe
+
+	ca.writeServerCertificate(dir, certs.ClientAuthNone)
+	require.Eventually(t, func() bool { return servedSerial() != first }, 5*time.Second, 50*time.Millisecond)
+
+	// a broken certificate is not picked up
+	renewed := servedSerial()
+	require.NoError(t, os.WriteFile(opts.CertFile, []byte("not a certificate"), 0600))
+	assert.Error(t, reloader.Reload())
+	assert.Equal(t, renewed, servedSerial())
+}
+
+func TestMutualTLSAuthentication(t *testing.T) {
+	setupSQLiteServices(t)
+	services.Config.TLSClientRoles = "ops=admin, auditor=read-only"
+	routes.InitGin()
+	router := routes.New()
+
+	ca := newTestCA(t, "Test CA")
+	reloader, err := certs.Load(ca.writeServerCertificate(t.TempDir(), certs.ClientAuthOptional))
+	require.NoError(t, err)
+	url := serveTLS(t, reloader, router)
+
+	request := func(client *http.Client, method string, path string, body string) (int, string) {
+		req, err := http.NewRequest(method, url+path, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		var problem models.Problem
+		_ = json.NewDecoder(resp.Body).Decode(&problem)
+		return resp.StatusCode, problem.Code
+	}
+	employee := `{"first_name":"John","last_name":"Doe","email":"john@example.com","abbreviation":"JDE"}`
+
+	ops := tlsClient(ca, ca.clientCertificate("ops"))
+	status, _ := request(ops, http.MethodPost, "/v1/api/employees/", employee)
+	assert.Equal(t, http.StatusCreated, status)
+	status, _ = request(ops, http.MethodGet, "/v1/computers", "")
+	assert.Equal(t, http.StatusOK, status)
+
+	auditor := tlsClient(ca, ca.clientCertificate("auditor"))
+	status, _ = request(auditor, http.MethodGet, "/v1/api/employees/computers/JDE", "")
+	assert.Equal(t, http.StatusOK, status)
+	status, code := request(auditor, http.MethodDelete, "/v1/computers/1", "")
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Equal(t, services.CodeInsufficientRole, code)
+
+	stranger := tlsClient(ca, ca.clientCertificate("stranger"))
+	status, code = request(stranger, http.MethodGet, "/v1/computers", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Equal(t, services.CodeUnknownClient, code)
+
+	// without a certificate the Bearer-Token is required
+	anonymous := tlsClient(ca)
+	status, code = request(anonymous, http.MethodGet, "/v1/computers", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Equal(t, services.CodeInvalidToken, code)
+	status, _ = request(anonymous, http.MethodPost, "/v1/auth/generate_access_token", `{"email":"admin@example.com"}`)
+	assert.Equal(t, http.StatusOK, status)
+
+	// certificates of other CAs fail the handshake
+	impostor := tlsClient(ca)
+	forged := newTestCA(t, "Other CA").clientCertificate("ops")
+	impostor.Transport.(*http.Transport).TLSClientConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		return &forged, nil
+	}
+	_, err = impostor.Get(url + "/v1/computers")
+	assert.Error(t, err)
+}
+
+func TestMutualTLSCanBeRequired(t *testing.T) {
+	ca := newTestCA(t, "Test CA")
+	reloader, err := certs.Load(ca.writeServerCertificate(t.TempDir(), certs.ClientAuthRequire))
+	require.NoError(t, err)
+	url := serveTLS(t, reloader, gin.New())
+
+	_, err = tlsClient(ca).Get(url)
+	assert.Error(t, err)
+
+	resp, err := tlsClient(ca, ca.clientCertificate("ops")).Get(url)
+	require.NoError(t, err)
+	resp.Body.Close()
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"greenbone-task/services"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+const incomingTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+
+// recordSpans installs a tracer provider that keeps finished spans in memory.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+
+	return recorder
+}
+
+func TestTracePropagatesThroughServicesAndNotifications(t *testing.T) {
+	setupSQLiteServices(t)
+	spans := recordSpans(t)
+	client := newAPIClient(t)
+
+	var mu sync.Mutex
+	var traceparents []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		mu.Unlock()
+		w.Write([]byte(`{}`))
+	}))
+	defer ts.Close()
+	services.Notifier = services.NewNotificationServiceWithURL(ts.URL)
+
+	require.Equal(t, http.StatusCreated, client.do(http.MethodPost, "/v1/api/employees/", gin.H{
+		"first_name":   "John",
+		"last_name":    "Doe",
+		"email":        "john@example.com",
+		"abbreviation": "JDE",
+	}, nil))
+
+	payload, err := json.Marshal([]gin.H{
+		{"mac_address": "12:34:56:78:90:c1", "computer_name": "One", "ip_address": "192.168.1.1", "employee_abbrev": "JDE"},
+		{"mac_address": "12:34:56:78:90:c2", "computer_name": "Two", "ip_address": "192.168.1.2", "employee_abbrev": "JDE"},
+		{"mac_address": "12:34:56:78:90:c3", "computer_name": "Three", "ip_address": "192.168.1.3", "employee_abbrev": "JDE"},
+	})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/v1/computers/import", bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Bearer-Token", client.token)
+	req.Header.Set("traceparent", "00-"+incomingTraceID+"-00f067aa0ba902b7-01")
+	recorder := httptest.NewRecorder()
+	client.router.ServeHTTP(recorder, req)
+	require.Equal(t, http.StatusCreated, recorder.Code, recorder.Body.String())
+
+	// the quota notification carries the trace of the request that caused it
+	mu.Lock()
+	require.Len(t, traceparents, 1)
+	assert.Contains(t, traceparents[0], incomingTraceID)
+	mu.Unlock()
+
+	names := map[string]bool{}
+	for _, span := range spans.Ended() {
+		if span.SpanContext().TraceID().String() == incomingTraceID {
+			names[span.Name()] = true
+		}
+	}
+	assert.True(t, names["/v1/computers/import"], "server span")
+	assert.True(t, names["services.ImportComputers"], "service span")
+	assert.True(t, names["gorm.create"], "database span")
+	assert.True(t, names["services.NotifySystemAdministrator"], "notification span")
+	assert.True(t, names["HTTP POST"], "notification client span")
+}
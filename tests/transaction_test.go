@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	db "greenbone-task/models/db"
+	"greenbone-task/repositories"
+	"greenbone-task/services"
+	"sync"
+	"testing"
+)
+
+var errInjected = errors.New("injected failure")
+
+// faultInjector fails a repository method once it has been called more than `after` times.
+type faultInjector struct {
+	mu     sync.Mutex
+	method string
+	after  int
+	calls  int
+}
+
+func (f *faultInjector) check(method string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if method != f.method {
+		return nil
+	}
+	f.calls++
+	if f.calls > f.after {
+		return errInjected
+	}
+	return nil
+}
+
+// faultyStore wraps a Store so that computer repository calls can be made to fail mid-flow.
+type faultyStore struct {
+	repositories.Store
+	faults *faultInjector
+}
+
+func (s faultyStore) Computers() repositories.ComputerRepository {
+	return faultyComputers{ComputerRepository: s.Store.Computers(), faults: s.faults}
+}
+
+func (s faultyStore) Transaction(ctx context.Context, fn func(tx repositories.Store) error) error {
+	return s.Store.Transaction(ctx, func(tx repositories.Store) error {
+		return fn(faultyStore{Store: tx, faults: s.faults})
+	})
+}
+
+type faultyComputers struct {
+	repositories.ComputerRepository
+	faults *faultInjector
+}
+
+func (r faultyComputers) Create(ctx context.Context, computer *db.Computer) error {
+	if err := r.faults.check("Create"); err != nil {
+		return err
+	}
+	return r.ComputerRepository.Create(ctx, computer)
+}
+
+func (r faultyComputers) Update(ctx context.Context, computer *db.Computer) error {
+	if err := r.faults.check("Update"); err != nil {
+		return err
+	}
+	return r.ComputerRepository.Update(ctx, computer)
+}
+
+func (r faultyComputers) Delete(ctx context.Context, id uint) error {
+	if err := r.faults.check("Delete"); err != nil {
+		return err
+	}
+	return r.ComputerRepository.Delete(ctx, id)
+}
+
+func (r faultyComputers) Assign(ctx context.Context, employeeID uint, computerID uint) error {
+	if err := r.faults.check("Assign"); err != nil {
+		return err
+	}
+	return r.ComputerRepository.Assign(ctx, employeeID, computerID)
+}
+
+// storeBackends runs a test against the in-memory store, against SQLite and,
+// if TEST_POSTGRES_HOST is set, against Postgres.
+var storeBackends = map[string]func(t *testing.T) *fakeNotifier{
+	"memory": func(t *testing.T) *fakeNotifier {
+		_, notifier := setupMemoryServices(t)
+		return notifier
+	},
+	"sqlite":   setupSQLiteServices,
+	"postgres": setupPostgresServices,
+}
+
+// injectFault makes the given computer repository method fail after `after` successful calls.
+func injectFault(t *testing.T, method string, after int) {
+	store := services.Store
+	services.Store = faultyStore{Store: store, faults: &faultInjector{method: method, after: after}}
+	t.Cleanup(func() { services.Store = store })
+}
+
+func newComputer(i int, abbreviation string) db.Computer {
+	return db.Computer{
+		MacAddress:     fmt.Sprintf("10:00:00:00:00:%02d", i),
+		ComputerName:   fmt.Sprintf("Computer %d", i),
+		IPAddress:      "192.168.1.10",
+		EmployeeAbbrev: abbreviation,
+	}
+}
+
+func TestCreateComputerRollsBackOnFailure(t *testing.T) {
+	for name, setup := range storeBackends {
+		t.Run(name, func(t *testing.T) {
+			notifier := setup(t)
+			ctx := context.Background()
+			createEmployee(t, "JDE")
+			for i := 0; i < 2; i++ {
+				_, err := services.CreateComputer(ctx, newComputer(i, "JDE"))
+				require.NoError(t, err)
+			}
+
+			// the third computer crosses the quota but fails after the insert
+			injectFault(t, "Assign", 0)
+			_, err := services.CreateComputer(ctx, newComputer(2, "JDE"))
+			require.ErrorIs(t, err, errInjected)
+
+			computers, err := services.GetAllComputers(ctx)
+			require.NoError(t, err)
+			assert.Len(t, computers, 2, "the computer must not be half-written")
+			assert.Equal(t, 0, notifier.count(), "no notification for a rolled back assignment")
+		})
+	}
+}
+
+func TestImportComputersIsAllOrNothing(t *testing.T) {
+	for name, setup := range storeBackends {
+		t.Run(name, func(t *testing.T) {
+			setup(t)
+			ctx := context.Background()
+			createEmployee(t, "JDE")
+
+			injectFault(t, "Assign", 2)
+			_, err := services.ImportComputers(ctx, []db.Computer{
+				newComputer(0, "JDE"),
+				newComputer(1, "JDE"),
+				newComputer(2, "JDE"),
+			})
+			require.ErrorIs(t, err, errInjected)
+
+			computers, err := services.GetAllComputers(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, computers)
+
+			assigned, err := services.FindComputersByEmployeeAbbrev(ctx, "JDE")
+			require.NoError(t, err)
+			assert.Empty(t, assigned)
+		})
+	}
+}
+
+func TestReassignComputerRollsBackOnFailure(t *testing.T) {
+	for name, setup := range storeBackends {
+		t.Run(name, func(t *testing.T) {
+			setup(t)
+			ctx := context.Background()
+			createEmployee(t, "JAD")
+			createEmployee(t, "JDE")
+			id, err := services.CreateComputer(ctx, newComputer(0, "JAD"))
+			require.NoError(t, err)
+
+			// the join table is updated but the computer row is not
+			injectFault(t, "Update", 0)
+			_, err = services.AssignComputerToEmployee(ctx, int64(id), "JDE")
+			require.ErrorIs(t, err, errInjected)
+
+			assigned, err := services.FindComputersByEmployeeAbbrev(ctx, "JAD")
+			require.NoError(t, err)
+			require.Len(t, assigned, 1)
+			assert.Equal(t, "JAD", assigned[0].EmployeeAbbrev)
+
+			reassigned, err := services.FindComputersByEmployeeAbbrev(ctx, "JDE")
+			require.NoError(t, err)
+			assert.Empty(t, reassigned)
+		})
+	}
+}
+
+func TestDeleteComputerRollsBackOnFailure(t *testing.T) {
+	for name, setup := range storeBackends {
+		t.Run(name, func(t *testing.T) {
+			setup(t)
+			ctx := context.Background()
+			createEmployee(t, "JAD")
+			id, err := services.CreateComputer(ctx, newComputer(0, "JAD"))
+			require.NoError(t, err)
+
+			// the assignment is removed but the computer is not
+			injectFault(t, "Delete", 0)
+			err = services.DeleteEmployeeComputer(ctx, int64(id), "JAD")
+			require.ErrorIs(t, err, errInjected)
+
+			assigned, err := services.FindComputersByEmployeeAbbrev(ctx, "JAD")
+			require.NoError(t, err)
+			assert.Len(t, assigned, 1)
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"greenbone-task/certs"
+	"greenbone-task/client"
+	"greenbone-task/routes"
+	"greenbone-task/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newV2Client starts the API on a SQLite database and returns a client
+// authenticated for it.
+func newV2Client(t *testing.T) (*client.Client, string, string) {
+	setupSQLiteServices(t)
+	routes.InitGin()
+	server := httptest.NewServer(routes.New())
+	t.Cleanup(server.Close)
+
+	tokens, err := client.New(server.URL).GenerateAccessToken(context.Background(), client.AuthRequest{Email: "helpdesk@example.com"})
+	require.NoError(t, err)
+	token := tokens.Data.Token.Access.Token
+	return client.New(server.URL, client.WithToken(token)), server.URL, token
+}
+
+// newAdminV2Client starts the API on a SQLite database over mutual TLS and
+// returns a client authenticated with a certificate of the admin role.
+func newAdminV2Client(t *testing.T) *client.Client {
+	return newCertificateV2Client(t, "ops", "admin")
+}
+
+// newCertificateV2Client starts the API on a SQLite database over mutual TLS
+// and returns a client authenticated with a certificate of the given role.
+func newCertificateV2Client(t *testing.T, name string, role string) *client.Client {
+	setupSQLiteServices(t)
+	services.Config.TLSClientRoles = name + "=" + role
+	routes.InitGin()
+
+	ca := newTestCA(t, "Test CA")
+	reloader, err := certs.Load(ca.writeServerCertificate(t.TempDir(), certs.ClientAuthOptional))
+	require.NoError(t, err)
+	url := serveTLS(t, reloader, routes.New())
+	return client.New(url, client.WithHTTPClient(tlsClient(ca, ca.clientCertificate(name))))
+}
+
+func TestV2ReturnsDTOs(t *testing.T) {
+	api, server, token := newV2Client(t)
+	ctx := context.Background()
+
+	employee, err := api.CreateEmployeeV2(ctx, client.EmployeeRequest{FirstName: "John", LastName: "Doe", Email: "jde@example.com", Abbreviation: "JDE"})
+	require.NoError(t, err)
+	assert.NotZero(t, employee.ID)
+	assert.Equal(t, "JDE", employee.Abbreviation)
+
+	created, err := api.CreateComputerV2(ctx, client.ComputerRequest{
+		MacAddress: "12:34:56:78:90:a0", ComputerName: "John's computer", IPAddress: "192.168.1.103", EmployeeAbbrev: "JDE",
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, created.ID)
+	assert.Equal(t, "JDE", created.EmployeeAbbrev)
+	assert.False(t, created.CreatedAt.IsZero())
+
+	// the fields are snake_case and gorm internals are not exposed
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v2/computers/%d", server, created.ID), nil)
+	require.NoError(t, err)
+	req.Header.Set("Bearer-Token", token)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var fields map[string]any
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&fields))
+	assert.ElementsMatch(t, []string{"id", "mac_address", "computer_name", "ip_address", "employee_abbrev", "created_at", "updated_at"}, keys(fields))
+
+	fetched, err := api.GetEmployeeV2(ctx, "JDE")
+	require.NoError(t, err)
+	assert.Equal(t, employee.ID, fetched.ID)
+
+	_, err = api.CreateEmployeeV2(ctx, client.EmployeeRequest{FirstName: "Anna", LastName: "Kay", Email: "ajk@example.com", Abbreviation: "AJK"})
+	require.NoError(t, err)
+	assigned, err := api.AssignComputerV2(ctx, created.ID, "AJK")
+	require.NoError(t, err)
+	assert.Equal(t, "AJK", assigned.EmployeeAbbrev)
+
+	page, err := api.ListEmployeeComputersV2(ctx, "AJK", 0, 0)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, page.Total)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, created.ID, page.Items[0].ID)
+
+	require.NoError(t, api.DeleteEmployeeComputerV2(ctx, "AJK", created.ID))
+	_, err = api.GetComputerV2(ctx, created.ID)
+	var problem *client.Problem
+	require.True(t, errors.As(err, &problem), "%v", err)
+	assert.Equal(t, "computer_not_found", problem.Code)
+}
+
+func TestV2PagesComputers(t *testing.T) {
+	api, _, _ := newV2Client(t)
+	ctx := context.Background()
+
+	_, err := api.CreateEmployeeV2(ctx, client.EmployeeRequest{FirstName: "John", LastName: "Doe", Email: "jde@example.com", Abbreviation: "JDE"})
+	require.NoError(t, err)
+	requests := make([]client.ComputerRequest, 5)
+	for i := range requests {
+		requests[i] = client.ComputerRequest{
+			MacAddress: fmt.Sprintf("10:00:00:00:00:%02d", i), ComputerName: fmt.Sprint("computer ", i),
+			IPAddress: fmt.Sprintf("10.0.0.%d", i), EmployeeAbbrev: "JDE",
+		}
+	}
+	imported, err := api.ImportComputersV2(ctx, requests)
+	require.NoError(t, err)
+	require.Len(t, imported.ComputerIDs, 5)
+
+	page, err := api.ListComputersV2(ctx, 2, 2)
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, page.Total)
+	assert.EqualValues(t, 2, page.Page)
+	assert.EqualValues(t, 2, page.PageSize)
+	require.Len(t, page.Items, 2)
+	assert.Equal(t, imported.ComputerIDs[2:4], []int64{page.Items[0].ID, page.Items[1].ID})
+
+	page, err = api.ListComputersV2(ctx, 4, 2)
+	require.NoError(t, err)
+	assert.Empty(t, page.Items)
+	assert.EqualValues(t, 50, must(api.ListComputersV2(ctx, 0, 0)).PageSize)
+
+	require.NoError(t, api.DeleteComputerV2(ctx, imported.ComputerIDs[0]))
+	assert.EqualValues(t, 4, must(api.ListComputersV2(ctx, 0, 0)).Total)
+
+	_, err = api.ListComputersV2(ctx, 0, 1000)
+	var problem *client.Problem
+	require.True(t, errors.As(err, &problem), "%v", err)
+	assert.Contains(t, problem.Errors, "page_size")
+}
+
+func keys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func must[T any](value T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"greenbone-task/client"
+	"greenbone-task/models"
+	db "greenbone-task/models/db"
+	"greenbone-task/repositories"
+	"greenbone-task/services"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// webhookReceiver records the webhook requests it receives and answers them
+// with status.
+type webhookReceiver struct {
+	mu       sync.Mutex
+	status   int
+	requests []receivedWebhook
+}
+
+type receivedWebhook struct {
+	header http.Header
+	body   []byte
+}
+
+func newWebhookReceiver(t *testing.T) (*webhookReceiver, string) {
+	receiver := &webhookReceiver{status: http.StatusNoContent}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		receiver.mu.Lock()
+		defer receiver.mu.Unlock()
+		receiver.requests = append(receiver.requests, receivedWebhook{header: r.Header.Clone(), body: body})
+		w.WriteHeader(receiver.status)
+	}))
+	t.Cleanup(server.Close)
+	return receiver, server.URL
+}
+
+func (r *webhookReceiver) answer(status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+func (r *webhookReceiver) received() []receivedWebhook {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]receivedWebhook(nil), r.requests...)
+}
+
+func TestWebhooksReceiveSignedSubscribedEvents(t *testing.T) {
+	api := newAdminV2Client(t)
+	ctx := context.Background()
+	receiver, url := newWebhookReceiver(t)
+
+	// a token carries no role, so a token user cannot make the server send
+	// requests to URLs of their choosing
+	status := newAPIClient(t).do(http.MethodPost, "/v1/webhooks", models.WebhookRequest{URL: url}, nil)
+	require.Equal(t, http.StatusForbidden, status)
+
+	const secret = "0123456789abcdef-secret"
+	created, err := api.CreateWebhook(ctx, client.WebhookRequest{
+		URL: url, EventTypes: []string{db.EventComputerCreated, db.EventComputerDeleted}, Secret: secret,
+	})
+	require.NoError(t, err)
+	webhook := created.Data.Webhook
+	assert.Equal(t, secret, created.Data.Secret)
+	assert.True(t, webhook.Active)
+	assert.Equal(t, []string{db.EventComputerCreated, db.EventComputerDeleted}, webhook.EventTypes)
+
+	// a second, disabled webhook receives nothing
+	disabled := false
+	_, err = api.CreateWebhook(ctx, client.WebhookRequest{URL: url + "/disabled", Active: &disabled})
+	require.NoError(t, err)
+
+	for _, abbreviation := range []string{"JDE", "AJK"} {
+		_, err := api.CreateEmployeeV2(ctx, client.EmployeeRequest{FirstName: abbreviation, LastName: "Doe", Email: abbreviation + "@example.com", Abbreviation: abbreviation})
+		require.NoError(t, err)
+	}
+	computer, err := api.CreateComputerV2(ctx, client.ComputerRequest{
+		MacAddress: "12:34:56:78:90:a0", ComputerName: "John's computer", IPAddress: "192.168.1.103", EmployeeAbbrev: "JDE",
+	})
+	require.NoError(t, err)
+	_, err = api.AssignComputerV2(ctx, computer.ID, "AJK")
+	require.NoError(t, err)
+	require.NoError(t, api.DeleteComputerV2(ctx, computer.ID))
+
+	sent, err := services.DeliverDueWebhooks(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+
+	// the reassignment is filtered out, and every request is signed
+	requests := receiver.received()
+	require.Len(t, requests, 2)
+	var types []string
+	for _, request := range requests {
+		timestamp, err := strconv.ParseInt(request.header.Get(services.WebhookTimestampHeader), 10, 64)
+		require.NoError(t, err)
+		assert.Equal(t, services.SignWebhookPayload(secret, timestamp, request.body), request.header.Get(services.WebhookSignatureHeader))
+
+		var event struct {
+			models.WebhookEvent
+			Data models.ComputerEventData `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(request.body, &event))
+		assert.Equal(t, event.Type, request.header.Get(services.WebhookEventHeader))
+		assert.NotEmpty(t, event.ID)
+		assert.EqualValues(t, computer.ID, event.Data.Computer.ID)
+		types = append(types, event.Type)
+	}
+	assert.ElementsMatch(t, []string{db.EventComputerCreated, db.EventComputerDeleted}, types)
+
+	deliveries, err := api.GetWebhookDeliveries(ctx, webhook.ID, db.DeliverySucceeded)
+	require.NoError(t, err)
+	require.Len(t, deliveries.Data.Deliveries, 2)
+	assert.Equal(t, db.EventComputerDeleted, deliveries.Data.Deliveries[0].EventType, "newest first")
+
+	detail, err := api.GetWebhookDelivery(ctx, webhook.ID, deliveries.Data.Deliveries[0].ID)
+	require.NoError(t, err)
+	require.Len(t, detail.Data.Attempts, 1)
+	assert.EqualValues(t, http.StatusNoContent, detail.Data.Attempts[0].StatusCode)
+	assert.NotNil(t, detail.Data.Delivery.DeliveredAt)
+
+	// the secret is only returned on create
+	fetched, err := api.GetWebhook(ctx, webhook.ID)
+	require.NoError(t, err)
+	assert.Equal(t, url, fetched.Data.Webhook.URL)
+
+	_, err = api.DeleteWebhook(ctx, webhook.ID)
+	require.NoError(t, err)
+	_, err = api.GetWebhookDeliveries(ctx, webhook.ID, "")
+	var problem *client.Problem
+	require.True(t, errors.As(err, &problem), "%v", err)
+	assert.Equal(t, services.CodeWebhookNotFound, problem.Code)
+}
+
+// makeDeliveriesDue moves the next attempt of the pending deliveries into
+// the past, as if their backoff had passed.
+func makeDeliveriesDue(t *testing.T) {
+	// claiming them until a past time makes them due again
+	past := time.Now().Add(-time.Minute).Truncate(time.Microsecond)
+	_, err := services.Store.Webhooks().ClaimDueDeliveries(context.Background(), time.Now().Add(24*time.Hour), past, 100)
+	require.NoError(t, err)
+}
+
+func TestWebhookDeliveriesRetryUntilDeadAndReplay(t *testing.T) {
+	for name, setup := range storeBackends {
+		t.Run(name, func(t *testing.T) {
+			setup(t)
+			services.Config.WebhookMaxAttempts = 3
+			services.Config.WebhookRetryBackoff = time.Hour
+			ctx := context.Background()
+			receiver, url := newWebhookReceiver(t)
+			receiver.answer(http.StatusInternalServerError)
+
+			webhook, err := services.CreateWebhook(ctx, models.WebhookRequest{URL: url})
+			require.NoError(t, err)
+			assert.Len(t, webhook.Secret, 64)
+			createEmployee(t, "JDE")
+			_, err = services.CreateComputer(ctx, newComputer(1, "JDE"))
+			require.NoError(t, err)
+
+			for attempt := 1; attempt <= 3; attempt++ {
+				sent, err := services.DeliverDueWebhooks(ctx)
+				require.NoError(t, err)
+				assert.Equal(t, 1, sent, "attempt %d", attempt)
+				sent, err = services.DeliverDueWebhooks(ctx)
+				require.NoError(t, err)
+				assert.Zero(t, sent, "attempt %d is retried after the backoff", attempt)
+				makeDeliveriesDue(t)
+			}
+			sent, err := services.DeliverDueWebhooks(ctx)
+			require.NoError(t, err)
+			assert.Zero(t, sent, "dead deliveries are not retried")
+
+			dead, err := services.GetWebhookDeliveries(ctx, webhook.ID, db.DeliveryDead)
+			require.NoError(t, err)
+			require.Len(t, dead, 1)
+			assert.Equal(t, 3, dead[0].Attempts)
+			assert.Equal(t, http.StatusInternalServerError, dead[0].LastStatusCode)
+			_, attempts, err := services.GetWebhookDelivery(ctx, webhook.ID, dead[0].ID)
+			require.NoError(t, err)
+			assert.Len(t, attempts, 3)
+
+			// once the receiver is fixed, the event is replayed with its ID
+			receiver.answer(http.StatusOK)
+			replay, err := services.ReplayWebhookDelivery(ctx, webhook.ID, dead[0].ID)
+			require.NoError(t, err)
+			sent, err = services.DeliverDueWebhooks(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, 1, sent)
+
+			delivered, err := services.GetWebhookDeliveries(ctx, webhook.ID, db.DeliverySucceeded)
+			require.NoError(t, err)
+			require.Len(t, delivered, 1)
+			assert.Equal(t, replay.ID, delivered[0].ID)
+			assert.Equal(t, dead[0].EventID, delivered[0].EventID)
+
+			requests := receiver.received()
+			require.Len(t, requests, 4)
+			assert.Equal(t, requests[0].body, requests[3].body)
+		})
+	}
+}
+
+func TestExpiredDeliveryClaimIsNotRecorded(t *testing.T) {
+	for name, setup := range storeBackends {
+		t.Run(name, func(t *testing.T) {
+			setup(t)
+			ctx := context.Background()
+			_, url := newWebhookReceiver(t)
+			_, err := services.CreateWebhook(ctx, models.WebhookRequest{URL: url})
+			require.NoError(t, err)
+			createEmployee(t, "JDE")
+			_, err = services.CreateComputer(ctx, newComputer(1, "JDE"))
+			require.NoError(t, err)
+
+			// the first claim expires, and a second worker claims the delivery
+			now := time.Now()
+			expired := now.Add(time.Millisecond).Truncate(time.Microsecond)
+			first, err := services.Store.Webhooks().ClaimDueDeliveries(ctx, now, expired, 1)
+			require.NoError(t, err)
+			require.Len(t, first, 1)
+			now = expired.Add(time.Millisecond)
+			claimed := now.Add(time.Minute).Truncate(time.Microsecond)
+			second, err := services.Store.Webhooks().ClaimDueDeliveries(ctx, now, claimed, 1)
+			require.NoError(t, err)
+			require.Len(t, second, 1)
+			assert.Equal(t, first[0].ID, second[0].ID)
+
+			first[0].Attempts++
+			first[0].Status = db.DeliveryDead
+			first[0].NextAttemptAt = nil
+			assert.ErrorIs(t, services.Store.Webhooks().UpdateDelivery(ctx, &first[0], expired), repositories.ErrStale)
+
+			second[0].Attempts++
+			second[0].Status = db.DeliverySucceeded
+			second[0].NextAttemptAt = nil
+			require.NoError(t, services.Store.Webhooks().UpdateDelivery(ctx, &second[0], claimed))
+			stored, err := services.Store.Webhooks().FindDelivery(ctx, second[0].ID)
+			require.NoError(t, err)
+			assert.Equal(t, db.DeliverySucceeded, stored.Status)
+			assert.Equal(t, 1, stored.Attempts)
+		})
+	}
+}